@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+	"github.com/spf13/cobra"
+)
+
+var importCatalogPath string
+
+var importCmd = &cobra.Command{
+	Use:   "import [game]",
+	Short: "Import a game's catalog with default options",
+	Long: `Import loads a game's catalog data into its schema using the game's
+default importer. No stats seeding, icon uploads or verification are done;
+use 'seed' for a full catalog population with options.
+
+Available games:
+  d2 - Diablo II: Resurrected
+
+Examples:
+  lootstash-catalog import d2
+  lootstash-catalog import d2 --catalog ./catalogs/d2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importCatalogPath, "catalog", "", "Path to catalog folder (default: catalogs/<game>)")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Minute)
+	defer cancel()
+
+	PrintInfo("Connecting to database...")
+	db, err := database.NewConnection(ctx, GetDatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+	PrintSuccess("Connected to database")
+
+	var registry games.Registry
+	if err := registry.Register(api.NewD2Game(d2.NewRepository(db.Pool()), api.DefaultConfig())); err != nil {
+		return err
+	}
+
+	game := registry.Get(args[0])
+	if game == nil {
+		return fmt.Errorf("unknown game: %s. Available games: d2", args[0])
+	}
+
+	path := importCatalogPath
+	if path == "" {
+		path = "catalogs/" + game.Name()
+	}
+
+	PrintInfo(fmt.Sprintf("Importing %s into schema %s...", path, game.SchemaName()))
+	start := time.Now()
+	if err := game.Import(ctx, path); err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	PrintSuccess(fmt.Sprintf("Import completed in %v", time.Since(start).Round(time.Millisecond)))
+	return nil
+}
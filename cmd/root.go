@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -44,6 +48,43 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// splitList splits a comma-separated flag value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setupTracing configures OpenTelemetry from OTEL_EXPORTER_OTLP_ENDPOINT.
+// Tracing is a no-op when the endpoint is unset.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	endpoint := getEnvOrDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint: endpoint,
+		Insecure: getEnvOrDefault("OTEL_EXPORTER_OTLP_INSECURE", "") == "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if endpoint != "" {
+		PrintInfo(fmt.Sprintf("Exporting traces to %s", endpoint))
+	}
+	return shutdown, nil
+}
+
 func GetDatabaseURL() string {
 	return databaseURL
 }
@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/storage"
@@ -13,11 +15,22 @@ import (
 )
 
 var (
-	seedDryRun            bool
-	seedSkipIcons         bool
-	seedSkipRunewordIcons bool
-	seedSkipVerify        bool
-	seedCatalogPath       string
+	seedDryRun              bool
+	seedSkipIcons           bool
+	seedSkipRunewordIcons   bool
+	seedSkipVerify          bool
+	seedCatalogPath         string
+	seedUploadConcurrency   int
+	seedPruneStale          bool
+	seedCombineRules        string
+	seedGemNames            string
+	seedBatchSize           int
+	seedIncremental         bool
+	seedTransaction         bool
+	seedReport              string
+	seedIncludePlaceholders bool
+	seedNormalizeRules      string
+	seedPlaceholderPatterns []string
 )
 
 var seedCmd = &cobra.Command{
@@ -47,7 +60,8 @@ Available games:
 Examples:
   supabase db reset && lootstash-catalog seed d2
   lootstash-catalog seed d2 --dry-run
-  lootstash-catalog seed d2 --skip-icons`,
+  lootstash-catalog seed d2 --skip-icons
+  lootstash-catalog seed d2 --transaction`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSeed,
 }
@@ -60,6 +74,17 @@ func init() {
 	seedCmd.Flags().BoolVar(&seedSkipRunewordIcons, "skip-runeword-icons", false, "Skip runeword icon generation step")
 	seedCmd.Flags().BoolVar(&seedSkipVerify, "skip-verify", false, "Skip verification step")
 	seedCmd.Flags().StringVar(&seedCatalogPath, "catalog", "catalogs/d2", "Path to catalog folder")
+	seedCmd.Flags().BoolVar(&seedPruneStale, "prune-stale", false, "Soft-delete items that no longer appear in the source data")
+	seedCmd.Flags().StringVar(&seedCombineRules, "combine-rules", "", "JSON file of property combine rules (default: built-in rules)")
+	seedCmd.Flags().StringVar(&seedGemNames, "gem-names", "", "JSON file of localized gem name words and codes (merged over built-in English names)")
+	seedCmd.Flags().BoolVar(&seedIncremental, "incremental", false, "Only write rows whose source record changed since the last import")
+	seedCmd.Flags().BoolVar(&seedTransaction, "transaction", false, "Run the HTML import in one transaction, rolling back everything on any error")
+	seedCmd.Flags().StringVar(&seedReport, "report", "", "Write the import result as JSON to this file")
+	seedCmd.Flags().BoolVar(&seedIncludePlaceholders, "include-placeholders", false, "Import \"Expansion\"/\"Not Used\" style base and misc rows marked as placeholders instead of skipping them")
+	seedCmd.Flags().StringArrayVar(&seedPlaceholderPatterns, "placeholder-patterns", nil, "Extra case-insensitive regex for placeholder item names, added to the built-in ones (repeatable); also used to hide existing matches")
+	seedCmd.Flags().StringVar(&seedNormalizeRules, "normalize-rules", "", "Comma-separated item name normalization rules applied after the defaults when matching existing images: collapse-whitespace, fold-diacritics")
+	seedCmd.Flags().IntVar(&seedBatchSize, "batch-size", d2.DefaultImportBatchSize, "Items per progress batch during HTML import (0 = no batch progress)")
+	seedCmd.Flags().IntVar(&seedUploadConcurrency, "upload-concurrency", d2.DefaultUploadConcurrency, "Number of parallel image uploads during HTML import and icon upload (0 = upload inline)")
 }
 
 func runSeed(cmd *cobra.Command, args []string) error {
@@ -83,6 +108,13 @@ func runSeed(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	// Configure tracing before connecting so queries are traced
+	shutdownTracing, err := setupTracing(ctx)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
 	// Connect to database
 	PrintInfo("Connecting to database...")
 	db, err := database.NewConnection(ctx, GetDatabaseURL())
@@ -238,25 +270,95 @@ func seedStepHTMLImportV2(ctx context.Context, repo *d2.Repository) error {
 
 	// Create and run V2 importer
 	importer := d2.NewHTMLImporterV2(repo, statRegistry, stor, seedDryRun)
+	importer.SetUploadConcurrency(seedUploadConcurrency)
+	importer.SetPruneStale(seedPruneStale)
+	importer.SetReportPath(seedReport)
+	importer.SetBatchSize(seedBatchSize)
+	importer.SetTransactional(seedTransaction)
+	if len(seedPlaceholderPatterns) > 0 {
+		patterns := append(append([]string{}, d2.DefaultPlaceholderPatterns...), seedPlaceholderPatterns...)
+		filter, err := d2.NewPlaceholderFilter(patterns)
+		if err != nil {
+			return err
+		}
+		importer.SetPlaceholderFilter(filter)
+		PrintInfo(fmt.Sprintf("Placeholder patterns: %d built-in + %d custom", len(d2.DefaultPlaceholderPatterns), len(seedPlaceholderPatterns)))
+	}
+	if seedNormalizeRules != "" {
+		rules, err := d2.ParseNormalizeRules(splitList(seedNormalizeRules))
+		if err != nil {
+			return err
+		}
+		importer.SetNameNormalizer(d2.DefaultNameNormalizer.With(rules...))
+		PrintInfo(fmt.Sprintf("Name normalization: defaults + %s", seedNormalizeRules))
+	}
+	if seedCombineRules != "" {
+		f, err := os.Open(seedCombineRules)
+		if err != nil {
+			return fmt.Errorf("open combine rules: %w", err)
+		}
+		rules, err := d2.LoadCombineRules(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		importer.SetCombineRules(rules)
+		PrintInfo(fmt.Sprintf("Loaded %d combine rules from %s", len(rules), seedCombineRules))
+	}
+	if seedGemNames != "" {
+		f, err := os.Open(seedGemNames)
+		if err != nil {
+			return fmt.Errorf("open gem name table: %w", err)
+		}
+		table, err := d2.LoadGemNameTable(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		importer.SetGemNameTable(table)
+		PrintInfo(fmt.Sprintf("Loaded gem name table from %s", seedGemNames))
+	}
 
 	PrintInfo("Importing all items from HTML...")
-	result, err := importer.ImportAll(ctx, seedCatalogPath)
+	opts := d2.ImportOptions{IncludePlaceholders: seedIncludePlaceholders}
+	var result *d2.ImportResult
+	var err error
+	if seedIncremental {
+		result, err = importer.ImportIncremental(ctx, seedCatalogPath, opts)
+	} else {
+		result, err = importer.ImportAll(ctx, seedCatalogPath, opts)
+	}
 	if err != nil {
 		return fmt.Errorf("HTML import failed: %w", err)
 	}
 
 	PrintSuccess("HTML import completed!")
-	fmt.Printf("  Item Bases:       %d imported\n", result.ItemBases.Imported)
-	fmt.Printf("  Unique Items:     %d imported\n", result.UniqueItems.Imported)
+	fmt.Printf("  Item Bases:       %d imported, %d unchanged\n", result.ItemBases.Imported, result.ItemBases.Unchanged)
+	fmt.Printf("  Unique Items:     %d imported, %d unchanged\n", result.UniqueItems.Imported, result.UniqueItems.Unchanged)
 	fmt.Printf("  Set Bonuses:      %d imported\n", result.SetBonuses.Imported)
-	fmt.Printf("  Set Items:        %d imported\n", result.SetItems.Imported)
-	fmt.Printf("  Runewords:        %d imported\n", result.Runewords.Imported)
-	fmt.Printf("  Runes:            %d imported\n", result.Runes.Imported)
-	fmt.Printf("  Gems:             %d imported\n", result.Gems.Imported)
+	fmt.Printf("  Set Items:        %d imported, %d unchanged\n", result.SetItems.Imported, result.SetItems.Unchanged)
+	fmt.Printf("  Runewords:        %d imported, %d unchanged\n", result.Runewords.Imported, result.Runewords.Unchanged)
+	fmt.Printf("  Runes:            %d imported, %d unchanged\n", result.Runes.Imported, result.Runes.Unchanged)
+	fmt.Printf("  Gems:             %d imported, %d unchanged\n", result.Gems.Imported, result.Gems.Unchanged)
 	fmt.Printf("  Runeword Bases:   %d computed\n", result.RunewordBases.Imported)
 	fmt.Printf("  Images uploaded:  %d\n", result.ImagesUploaded)
 	fmt.Printf("  Images missing:   %d\n", result.ImagesMissing)
-	fmt.Printf("  Stats discovered: %d total\n", statRegistry.Count())
+	fmt.Printf("  Thumbnails:       %d\n", result.ThumbnailsUploaded)
+	fmt.Printf("  Images reused:    %d (identical bytes)\n", result.ImagesDeduplicated)
+	fmt.Printf("  Placeholders:     %d filtered, %d kept\n", result.PlaceholdersFiltered, result.PlaceholdersImported)
+	fmt.Printf("  Rune order:       %d repaired\n", result.RuneOrderRepaired)
+	fmt.Printf("  Duplicate codes:  %d\n", result.DuplicateCodes)
+	fmt.Printf("  Socket mismatch:  %d runewords\n", result.SocketMismatches)
+	fmt.Printf("  Raw properties:   %d unmatched lines\n", result.RawProperties)
+	fmt.Printf("  Stale items:      %d found, %d removed\n", len(result.StaleItems), result.StaleRemoved)
+	for _, stale := range result.StaleItems {
+		fmt.Printf("    - %s: %s (%s)\n", stale.Type, stale.Name, stale.Key)
+	}
+	fmt.Printf("  Stats discovered: %d total, %d new\n", statRegistry.Count(), len(result.NewStatCodes))
+	fmt.Printf("  Orphaned bases:   %d items\n", len(result.Orphans))
+	fmt.Printf("  Errors:           %d\n", len(result.Errors))
+
+	invalidateCatalogCache(ctx)
 
 	return nil
 }
@@ -279,6 +381,7 @@ func seedStepUploadIcons(ctx context.Context, db *database.DB) error {
 	// Create uploader
 	repo := d2.NewRepository(db.Pool())
 	uploader := d2.NewIconUploader(repo, s3Stor, seedDryRun, true)
+	uploader.SetConcurrency(seedUploadConcurrency)
 
 	// Run upload
 	stats, err := uploader.Upload(ctx, seedCatalogPath)
@@ -340,16 +443,16 @@ func seedStepVerify(ctx context.Context, db *database.DB) error {
 		name  string
 		query string
 	}{
-		{"Item Types", "SELECT COUNT(*) FROM d2.item_types"},
-		{"Item Bases", "SELECT COUNT(*) FROM d2.item_bases"},
-		{"Unique Items", "SELECT COUNT(*) FROM d2.unique_items"},
-		{"Set Bonuses", "SELECT COUNT(*) FROM d2.set_bonuses"},
-		{"Set Items", "SELECT COUNT(*) FROM d2.set_items"},
-		{"Runewords", "SELECT COUNT(*) FROM d2.runewords"},
-		{"Runes", "SELECT COUNT(*) FROM d2.runes"},
-		{"Gems", "SELECT COUNT(*) FROM d2.gems"},
-		{"Stats", "SELECT COUNT(*) FROM d2.stats"},
-		{"Runeword Bases", "SELECT COUNT(*) FROM d2.runeword_bases"},
+		{"Item Types", "SELECT COUNT(*) FROM " + d2.QualifiedTable("item_types")},
+		{"Item Bases", "SELECT COUNT(*) FROM " + d2.QualifiedTable("item_bases")},
+		{"Unique Items", "SELECT COUNT(*) FROM " + d2.QualifiedTable("unique_items")},
+		{"Set Bonuses", "SELECT COUNT(*) FROM " + d2.QualifiedTable("set_bonuses")},
+		{"Set Items", "SELECT COUNT(*) FROM " + d2.QualifiedTable("set_items")},
+		{"Runewords", "SELECT COUNT(*) FROM " + d2.QualifiedTable("runewords")},
+		{"Runes", "SELECT COUNT(*) FROM " + d2.QualifiedTable("runes")},
+		{"Gems", "SELECT COUNT(*) FROM " + d2.QualifiedTable("gems")},
+		{"Stats", "SELECT COUNT(*) FROM " + d2.QualifiedTable("stats")},
+		{"Runeword Bases", "SELECT COUNT(*) FROM " + d2.QualifiedTable("runeword_bases")},
 	}
 
 	fmt.Println("  Record Counts:")
@@ -367,9 +470,9 @@ func seedStepVerify(ctx context.Context, db *database.DB) error {
 		name  string
 		query string
 	}{
-		{"Unique Items (name)", `SELECT COUNT(*) FROM (SELECT name FROM d2.unique_items GROUP BY name HAVING COUNT(*) > 1) x`},
-		{"Set Items (name)", `SELECT COUNT(*) FROM (SELECT name FROM d2.set_items GROUP BY name HAVING COUNT(*) > 1) x`},
-		{"Runewords (name)", `SELECT COUNT(*) FROM (SELECT name FROM d2.runewords GROUP BY name HAVING COUNT(*) > 1) x`},
+		{"Unique Items (name)", `SELECT COUNT(*) FROM (SELECT name FROM ` + d2.QualifiedTable("unique_items") + ` GROUP BY name HAVING COUNT(*) > 1) x`},
+		{"Set Items (name)", `SELECT COUNT(*) FROM (SELECT name FROM ` + d2.QualifiedTable("set_items") + ` GROUP BY name HAVING COUNT(*) > 1) x`},
+		{"Runewords (name)", `SELECT COUNT(*) FROM (SELECT name FROM ` + d2.QualifiedTable("runewords") + ` GROUP BY name HAVING COUNT(*) > 1) x`},
 	}
 
 	fmt.Println("\n  Duplicate Checks:")
@@ -389,27 +492,27 @@ func seedStepVerify(ctx context.Context, db *database.DB) error {
 
 	// Check for items with images
 	var withImages, total int
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.unique_items WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.unique_items`).Scan(&total)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("unique_items")+` WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("unique_items")).Scan(&total)
 	if total > 0 {
 		fmt.Printf("\n  Unique items with images: %d/%d (%.1f%%)\n", withImages, total, float64(withImages)/float64(total)*100)
 	}
 
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.set_items WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.set_items`).Scan(&total)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("set_items")+` WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("set_items")).Scan(&total)
 	if total > 0 {
 		fmt.Printf("  Set items with images: %d/%d (%.1f%%)\n", withImages, total, float64(withImages)/float64(total)*100)
 	}
 
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.runewords WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.runewords`).Scan(&total)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("runewords")+` WHERE image_url IS NOT NULL AND image_url != ''`).Scan(&withImages)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("runewords")).Scan(&total)
 	if total > 0 {
 		fmt.Printf("  Runewords with images: %d/%d (%.1f%%)\n", withImages, total, float64(withImages)/float64(total)*100)
 	}
 
 	// Check stats
 	var statCount int
-	pool.QueryRow(ctx, `SELECT COUNT(*) FROM d2.stats`).Scan(&statCount)
+	pool.QueryRow(ctx, `SELECT COUNT(*) FROM `+d2.QualifiedTable("stats")).Scan(&statCount)
 	fmt.Printf("\n  Stat codes registered: %d\n", statCount)
 
 	if allGood {
@@ -438,3 +541,20 @@ func seedCreateS3Storage() (storage.Storage, error) {
 		supabaseURL,
 	)
 }
+
+// invalidateCatalogCache drops every cached D2 entry after an import so the
+// API serves fresh data. A missing Redis is not an error.
+func invalidateCatalogCache(ctx context.Context) {
+	redisCache, err := cache.NewRedisCache(ctx, GetRedisURL())
+	if err != nil {
+		PrintInfo(fmt.Sprintf("Redis unavailable, skipping cache invalidation: %v", err))
+		return
+	}
+	defer redisCache.Close()
+
+	if err := redisCache.DeleteByPattern(ctx, cache.D2AllPattern()); err != nil {
+		PrintError(fmt.Sprintf("Failed to invalidate cache: %v", err))
+		return
+	}
+	PrintSuccess("Cache invalidated")
+}
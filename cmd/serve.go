@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
 	"github.com/spf13/cobra"
@@ -16,6 +18,13 @@ import (
 var (
 	port           int
 	allowedOrigins string
+	cacheMaxAge    int
+	noRedis        bool
+	searchLimit    int
+	catalogLimit   int
+	apiKeys        string
+	proxyHeader    string
+	trustedProxies string
 )
 
 var serveCmd = &cobra.Command{
@@ -48,11 +57,25 @@ func init() {
 
 	serveCmd.Flags().IntVar(&port, "port", 8080, "Port to listen on")
 	serveCmd.Flags().StringVar(&allowedOrigins, "allowed-origins", getEnvOrDefault("ALLOWED_ORIGIN", "*"), "Comma-separated list of allowed CORS origins (use * for all)")
+	serveCmd.Flags().IntVar(&cacheMaxAge, "cache-max-age", getEnvIntOrDefault("CACHE_MAX_AGE", 3600), "Cache-Control max-age in seconds for catalog endpoints (0 disables)")
+	serveCmd.Flags().BoolVar(&noRedis, "no-redis", false, "Disable Redis caching of item details")
+	serveCmd.Flags().IntVar(&searchLimit, "search-rate-limit", getEnvIntOrDefault("SEARCH_RATE_LIMIT", 60), "Search requests per minute per API key or IP (0 disables)")
+	serveCmd.Flags().IntVar(&catalogLimit, "rate-limit", getEnvIntOrDefault("RATE_LIMIT", 600), "Catalog requests per minute per API key or IP (0 disables)")
+	serveCmd.Flags().StringVar(&apiKeys, "api-keys", getEnvOrDefault("API_KEYS", ""), "Comma-separated X-API-Key values rate limited per key; other clients are limited per IP")
+	serveCmd.Flags().StringVar(&proxyHeader, "proxy-header", getEnvOrDefault("PROXY_HEADER", ""), "Header holding the client IP set by the load balancer, e.g. Fly-Client-IP (empty uses the connection IP)")
+	serveCmd.Flags().StringVar(&trustedProxies, "trusted-proxies", getEnvOrDefault("TRUSTED_PROXIES", ""), "Comma-separated IPs or CIDRs allowed to set the proxy header (empty trusts all)")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	// Configure tracing before connecting so queries are traced
+	shutdownTracing, err := setupTracing(ctx)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
 	// Connect to database
 	PrintInfo("Connecting to database...")
 	db, err := database.NewConnection(ctx, GetDatabaseURL())
@@ -76,6 +99,40 @@ func runServe(cmd *cobra.Command, args []string) error {
 		JWTAudience:    "authenticated",
 		JWTIssuer:      supabaseURL + "/auth/v1",
 		AuthDebug:      getEnvOrDefault("AUTH_DEBUG", "") == "true",
+
+		ImagePlaceholderURL: getEnvOrDefault("IMAGE_PLACEHOLDER_URL", ""),
+		CacheMaxAge:         time.Duration(cacheMaxAge) * time.Second,
+		SearchRateLimit:     searchLimit,
+		CatalogRateLimit:    catalogLimit,
+		APIKeys:             splitList(apiKeys),
+		ProxyHeader:         proxyHeader,
+		TrustedProxies:      splitList(trustedProxies),
+	}
+
+	// Optional breakpoint table overrides
+	if path := getEnvOrDefault("BREAKPOINTS_FILE", ""); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open breakpoints file: %w", err)
+		}
+		config.Breakpoints, err = d2.LoadBreakpointTables(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		PrintInfo(fmt.Sprintf("Loaded breakpoint tables from %s", path))
+	}
+
+	// Optional Redis cache for item details; the server runs without it
+	if !noRedis {
+		redisCache, err := cache.NewRedisCache(ctx, GetRedisURL())
+		if err != nil {
+			PrintInfo(fmt.Sprintf("Redis unavailable, item detail caching disabled: %v", err))
+		} else {
+			defer redisCache.Close()
+			config.Cache = redisCache
+			PrintSuccess("Connected to Redis")
+		}
 	}
 
 	// Create and start server
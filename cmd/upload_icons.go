@@ -12,14 +12,15 @@ import (
 )
 
 var (
-	uploadDryRun    bool
-	uploadForce     bool
-	uploadCatalog   string
-	s3Endpoint      string
-	s3AccessKey     string
-	s3SecretKey     string
-	s3Region        string
-	s3PublicURL     string
+	uploadDryRun      bool
+	uploadForce       bool
+	uploadCatalog     string
+	uploadConcurrency int
+	s3Endpoint        string
+	s3AccessKey       string
+	s3SecretKey       string
+	s3Region          string
+	s3PublicURL       string
 )
 
 var uploadIconsCmd = &cobra.Command{
@@ -41,7 +42,10 @@ Examples:
   lootstash-catalog upload-icons --dry-run
 
   # Upload images
-  lootstash-catalog upload-icons`,
+  lootstash-catalog upload-icons
+
+  # Upload with more parallel workers
+  lootstash-catalog upload-icons --concurrency 16`,
 	RunE: runUploadIcons,
 }
 
@@ -51,6 +55,7 @@ func init() {
 	uploadIconsCmd.Flags().BoolVar(&uploadDryRun, "dry-run", false, "Preview without making changes")
 	uploadIconsCmd.Flags().BoolVar(&uploadForce, "force", false, "Re-upload all icons (default: only items missing icons)")
 	uploadIconsCmd.Flags().StringVar(&uploadCatalog, "catalog", "catalogs/d2", "Path to catalog folder (contains icons/ and pages/ subfolders)")
+	uploadIconsCmd.Flags().IntVar(&uploadConcurrency, "concurrency", d2.DefaultUploadConcurrency, "Number of parallel image uploads (ignored with --dry-run)")
 
 	// S3 configuration - derives from SUPABASE_* env vars
 	supabaseDefault := getEnvOrDefault("SUPABASE_URL", "http://127.0.0.1:54321")
@@ -96,6 +101,7 @@ func runUploadIcons(cmd *cobra.Command, args []string) error {
 	// Create uploader
 	repo := d2.NewRepository(db.Pool())
 	uploader := d2.NewIconUploader(repo, s3Storage, uploadDryRun, uploadForce)
+	uploader.SetConcurrency(uploadConcurrency)
 
 	// Run upload
 	stats, err := uploader.Upload(ctx, uploadCatalog)
@@ -114,6 +120,7 @@ func runUploadIcons(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nStatistics:")
 	fmt.Printf("  Total DB items:   %d\n", stats.TotalDBItems)
 	fmt.Printf("  Uploaded (new):   %d\n", stats.Uploaded)
+	fmt.Printf("  Thumbnails:       %d\n", stats.Thumbnails)
 	fmt.Printf("  Reused (cache):   %d\n", stats.ReusedCache)
 	fmt.Printf("  Matched unique:   %d\n", stats.MatchedUnique)
 	fmt.Printf("  Matched set:      %d\n", stats.MatchedSet)
@@ -123,6 +130,7 @@ func runUploadIcons(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Not in HTML:      %d\n", stats.NotInHTML)
 	fmt.Printf("  Missing files:    %d\n", stats.MissingFiles)
 	fmt.Printf("  Errors:           %d\n", stats.Errors)
+	fmt.Printf("  Upload time:      %s\n", stats.UploadTime.Round(time.Millisecond))
 
 	if len(stats.NotInHTMLItems) > 0 {
 		fmt.Printf("\nItems not found in HTML files (first %d):\n", len(stats.NotInHTMLItems))
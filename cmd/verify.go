@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
 	"github.com/spf13/cobra"
 )
 
@@ -53,16 +54,16 @@ func verifyD2(ctx context.Context, db *database.DB) error {
 		name  string
 		query string
 	}{
-		{"Item Types", "SELECT COUNT(*) FROM d2.item_types"},
-		{"Item Bases", "SELECT COUNT(*) FROM d2.item_bases"},
-		{"Unique Items", "SELECT COUNT(*) FROM d2.unique_items"},
-		{"Set Bonuses", "SELECT COUNT(*) FROM d2.set_bonuses"},
-		{"Set Items", "SELECT COUNT(*) FROM d2.set_items"},
-		{"Runewords", "SELECT COUNT(*) FROM d2.runewords"},
-		{"Runes", "SELECT COUNT(*) FROM d2.runes"},
-		{"Gems", "SELECT COUNT(*) FROM d2.gems"},
-		{"Properties", "SELECT COUNT(*) FROM d2.properties"},
-		{"Affixes", "SELECT COUNT(*) FROM d2.affixes"},
+		{"Item Types", "SELECT COUNT(*) FROM " + d2.QualifiedTable("item_types")},
+		{"Item Bases", "SELECT COUNT(*) FROM " + d2.QualifiedTable("item_bases")},
+		{"Unique Items", "SELECT COUNT(*) FROM " + d2.QualifiedTable("unique_items")},
+		{"Set Bonuses", "SELECT COUNT(*) FROM " + d2.QualifiedTable("set_bonuses")},
+		{"Set Items", "SELECT COUNT(*) FROM " + d2.QualifiedTable("set_items")},
+		{"Runewords", "SELECT COUNT(*) FROM " + d2.QualifiedTable("runewords")},
+		{"Runes", "SELECT COUNT(*) FROM " + d2.QualifiedTable("runes")},
+		{"Gems", "SELECT COUNT(*) FROM " + d2.QualifiedTable("gems")},
+		{"Properties", "SELECT COUNT(*) FROM " + d2.QualifiedTable("properties")},
+		{"Affixes", "SELECT COUNT(*) FROM " + d2.QualifiedTable("affixes")},
 	}
 
 	fmt.Println("\n=== Record Counts ===")
@@ -81,17 +82,17 @@ func verifyD2(ctx context.Context, db *database.DB) error {
 		name  string
 		query string
 	}{
-		{"Item Types (code)", `SELECT code, COUNT(*) as cnt FROM d2.item_types GROUP BY code HAVING COUNT(*) > 1`},
-		{"Item Bases (code)", `SELECT code, COUNT(*) as cnt FROM d2.item_bases GROUP BY code HAVING COUNT(*) > 1`},
-		{"Unique Items (index_id)", `SELECT index_id, COUNT(*) as cnt FROM d2.unique_items GROUP BY index_id HAVING COUNT(*) > 1`},
-		{"Unique Items (name)", `SELECT name, COUNT(*) as cnt FROM d2.unique_items GROUP BY name HAVING COUNT(*) > 1`},
-		{"Set Bonuses (name)", `SELECT name, COUNT(*) as cnt FROM d2.set_bonuses GROUP BY name HAVING COUNT(*) > 1`},
-		{"Set Items (index_id)", `SELECT index_id, COUNT(*) as cnt FROM d2.set_items GROUP BY index_id HAVING COUNT(*) > 1`},
-		{"Runewords (name)", `SELECT name, COUNT(*) as cnt FROM d2.runewords GROUP BY name HAVING COUNT(*) > 1`},
-		{"Runes (code)", `SELECT code, COUNT(*) as cnt FROM d2.runes GROUP BY code HAVING COUNT(*) > 1`},
-		{"Gems (code)", `SELECT code, COUNT(*) as cnt FROM d2.gems GROUP BY code HAVING COUNT(*) > 1`},
-		{"Properties (code)", `SELECT code, COUNT(*) as cnt FROM d2.properties GROUP BY code HAVING COUNT(*) > 1`},
-		{"Affixes (name+type)", `SELECT name, affix_type, COUNT(*) as cnt FROM d2.affixes GROUP BY name, affix_type HAVING COUNT(*) > 1`},
+		{"Item Types (code)", `SELECT code, COUNT(*) as cnt FROM ` + d2.QualifiedTable("item_types") + ` GROUP BY code HAVING COUNT(*) > 1`},
+		{"Item Bases (code)", `SELECT code, COUNT(*) as cnt FROM ` + d2.QualifiedTable("item_bases") + ` GROUP BY code HAVING COUNT(*) > 1`},
+		{"Unique Items (index_id)", `SELECT index_id, COUNT(*) as cnt FROM ` + d2.QualifiedTable("unique_items") + ` GROUP BY index_id HAVING COUNT(*) > 1`},
+		{"Unique Items (name)", `SELECT name, COUNT(*) as cnt FROM ` + d2.QualifiedTable("unique_items") + ` GROUP BY name HAVING COUNT(*) > 1`},
+		{"Set Bonuses (name)", `SELECT name, COUNT(*) as cnt FROM ` + d2.QualifiedTable("set_bonuses") + ` GROUP BY name HAVING COUNT(*) > 1`},
+		{"Set Items (index_id)", `SELECT index_id, COUNT(*) as cnt FROM ` + d2.QualifiedTable("set_items") + ` GROUP BY index_id HAVING COUNT(*) > 1`},
+		{"Runewords (name)", `SELECT name, COUNT(*) as cnt FROM ` + d2.QualifiedTable("runewords") + ` GROUP BY name HAVING COUNT(*) > 1`},
+		{"Runes (code)", `SELECT code, COUNT(*) as cnt FROM ` + d2.QualifiedTable("runes") + ` GROUP BY code HAVING COUNT(*) > 1`},
+		{"Gems (code)", `SELECT code, COUNT(*) as cnt FROM ` + d2.QualifiedTable("gems") + ` GROUP BY code HAVING COUNT(*) > 1`},
+		{"Properties (code)", `SELECT code, COUNT(*) as cnt FROM ` + d2.QualifiedTable("properties") + ` GROUP BY code HAVING COUNT(*) > 1`},
+		{"Affixes (name+type)", `SELECT name, affix_type, COUNT(*) as cnt FROM ` + d2.QualifiedTable("affixes") + ` GROUP BY name, affix_type HAVING COUNT(*) > 1`},
 	}
 
 	allGood := true
@@ -123,7 +124,7 @@ func verifyD2(ctx context.Context, db *database.DB) error {
 	var uniqueProps int
 	err := pool.QueryRow(ctx, `
 		SELECT name, base_code, jsonb_array_length(properties)
-		FROM d2.unique_items
+		FROM `+d2.QualifiedTable("unique_items")+`
 		WHERE name = 'The Gnasher'`).Scan(&uniqueName, &uniqueBase, &uniqueProps)
 	if err != nil {
 		PrintError("The Gnasher not found")
@@ -136,7 +137,7 @@ func verifyD2(ctx context.Context, db *database.DB) error {
 	var rwRunes, rwProps int
 	err = pool.QueryRow(ctx, `
 		SELECT display_name, jsonb_array_length(runes), jsonb_array_length(properties)
-		FROM d2.runewords
+		FROM `+d2.QualifiedTable("runewords")+`
 		WHERE display_name = 'Enigma'`).Scan(&rwName, &rwRunes, &rwProps)
 	if err != nil {
 		PrintError("Enigma runeword not found")
@@ -149,7 +150,7 @@ func verifyD2(ctx context.Context, db *database.DB) error {
 	var setPartial, setFull int
 	err = pool.QueryRow(ctx, `
 		SELECT name, jsonb_array_length(partial_bonuses), jsonb_array_length(full_bonuses)
-		FROM d2.set_bonuses
+		FROM `+d2.QualifiedTable("set_bonuses")+`
 		WHERE name = 'Tal Rasha''s Wrappings'`).Scan(&setName, &setPartial, &setFull)
 	if err != nil {
 		PrintError("Tal Rasha's Wrappings set not found")
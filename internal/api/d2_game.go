@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/handlers"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// d2Game serves the Diablo II catalog
+type d2Game struct {
+	repo    *d2.Repository
+	config  *Config
+	counter middleware.RateCounter
+	apiKeys middleware.APIKeys
+}
+
+// NewD2Game returns the Diablo II game backed by repo. Routes are configured
+// from config.
+func NewD2Game(repo *d2.Repository, config *Config) games.Game {
+	g := &d2Game{repo: repo, config: config, apiKeys: middleware.NewAPIKeys(config.APIKeys)}
+	if config.Cache != nil {
+		g.counter = config.Cache
+	} else {
+		g.counter = middleware.NewMemoryRateCounter(nil)
+	}
+	return g
+}
+
+// Name returns the URL segment of the D2 routes
+func (g *d2Game) Name() string {
+	return "d2"
+}
+
+// SchemaName returns the Postgres schema of the D2 catalog
+func (g *d2Game) SchemaName() string {
+	return d2.SchemaName
+}
+
+// Import runs a full HTML import from a catalog directory without image
+// uploads. The seed command drives the importer directly for its extra options.
+func (g *d2Game) Import(ctx context.Context, path string) error {
+	statRegistry := d2.NewStatRegistry(g.repo)
+	if err := statRegistry.Load(ctx); err != nil {
+		return fmt.Errorf("load stat registry: %w", err)
+	}
+	_, err := d2.NewHTMLImporterV2(g.repo, statRegistry, nil, false).ImportAll(ctx, path, d2.ImportOptions{})
+	return err
+}
+
+// RegisterRoutes mounts the D2 catalog endpoints
+func (g *d2Game) RegisterRoutes(router fiber.Router) {
+	itemHandler := handlers.NewItemHandler(g.repo, handlers.ItemHandlerConfig{
+		ImagePlaceholderURL: g.config.ImagePlaceholderURL,
+		Breakpoints:         g.config.Breakpoints,
+		Cache:               g.config.Cache,
+	})
+
+	// Affix text resolves numeric skill params with the class skill trees'
+	// spelling, loaded once on the first request
+	router.Use(itemHandler.LoadSkillNames)
+
+	// Catalog data only changes on import; dynamic routes opt out with NoStore
+	router.Use(middleware.CacheControl(g.config.CacheMaxAge))
+
+	// Search routes are the expensive ones and get a tighter limit on top of
+	// the catalog-wide one
+	router.Use(g.rateLimit("catalog", g.config.CatalogRateLimit))
+	searchLimit := g.rateLimit("search", g.config.SearchRateLimit)
+
+	// Routes rendering item affixes honor ?lang= and Accept-Language
+	localized := itemHandler.Localized
+
+	// Item routes
+	items := router.Group("/items")
+
+	// Search endpoint
+	items.Get("/search", searchLimit, middleware.NoStore(), itemHandler.Search)
+	items.Get("/by-stats", searchLimit, middleware.NoStore(), itemHandler.SearchByStats)
+	items.Post("/optimize", searchLimit, middleware.NoStore(), itemHandler.Optimize)
+	items.Post("/batch", searchLimit, middleware.NoStore(), localized((*handlers.ItemHandler).GetItemsBatch))
+	items.Get("/compare", localized((*handlers.ItemHandler).CompareItems))
+
+	// Generic item lookup by type and ID
+	items.Get("/:type/:id", localized((*handlers.ItemHandler).GetItem))
+	items.Get("/:type/:id/same-base", localized((*handlers.ItemHandler).GetSameBase))
+	items.Get("/:type/:id/placeholder.png", itemHandler.GetItemPlaceholder)
+
+	// Specific type endpoints (for convenience)
+	items.Get("/unique/:id", localized((*handlers.ItemHandler).GetUniqueItem))
+	items.Get("/set/:id", localized((*handlers.ItemHandler).GetSetItem))
+	items.Get("/runeword/:id", localized((*handlers.ItemHandler).GetRuneword))
+	items.Get("/runeword/:id/bases", itemHandler.GetRunewordBases)
+	items.Get("/runeword/:id/bases/:baseId/preview", itemHandler.GetRunewordPreview)
+	items.Get("/rune/:id", localized((*handlers.ItemHandler).GetRune))
+	items.Get("/rune/:id/upgrade", itemHandler.GetRuneUpgradePath)
+	items.Get("/gem/:id", localized((*handlers.ItemHandler).GetGem))
+	items.Get("/gem/:id/progression", localized((*handlers.ItemHandler).GetGemProgression))
+	items.Get("/base/:id", itemHandler.GetBase)
+	items.Get("/base/:id/breakpoints", itemHandler.GetBaseBreakpoints)
+	items.Get("/base/:id/runewords", itemHandler.GetBaseRunewords)
+	items.Get("/quest/:id", itemHandler.GetQuestItem)
+
+	// Collection endpoints - list all items by type
+	router.Get("/runes", localized((*handlers.ItemHandler).GetAllRunes))
+	router.Get("/runes/:id/full", localized((*handlers.ItemHandler).GetRuneFull))
+	router.Get("/gems", localized((*handlers.ItemHandler).GetAllGems))
+	router.Get("/socketables", localized((*handlers.ItemHandler).GetAllSocketables))
+	router.Get("/bases", itemHandler.GetAllBases)
+	router.Get("/uniques", localized((*handlers.ItemHandler).GetAllUniques))
+	router.Get("/sets", localized((*handlers.ItemHandler).GetAllSets))
+	router.Get("/sets/bonuses", localized((*handlers.ItemHandler).GetAllSetBonuses))
+	router.Get("/runewords", localized((*handlers.ItemHandler).GetAllRunewords))
+	router.Get("/quests", itemHandler.GetAllQuestItems)
+
+	// Single-set endpoints live under /set/:name, so no set name can collide
+	// with a /sets collection route
+	set := router.Group("/set/:name")
+	set.Get("/", localized((*handlers.ItemHandler).GetSetBonus))
+	set.Get("/bonuses", localized((*handlers.ItemHandler).GetSetBonuses))
+	set.Get("/combined", localized((*handlers.ItemHandler).GetSetCombined))
+	set.Get("/full", localized((*handlers.ItemHandler).GetFullSet))
+	router.Get("/classes", itemHandler.GetAllClasses)
+	router.Get("/breakpoints", itemHandler.GetBreakpoint)
+
+	// Reference data endpoints - for marketplace filtering
+	router.Get("/stats", itemHandler.GetAllStats)
+	router.Get("/stats/categories", itemHandler.GetStatCategories)
+	router.Get("/stats/:code/range", itemHandler.GetStatRange)
+	router.Get("/categories", itemHandler.GetAllCategories)
+	router.Get("/item-types", itemHandler.GetAllItemTypes)
+	router.Get("/item-types/:code", itemHandler.GetItemTypeByCode)
+	router.Get("/rarities", itemHandler.GetAllRarities)
+	router.Get("/changes", middleware.NoStore(), itemHandler.GetChanges)
+
+	// Spreadsheet exports
+	router.Get("/export/uniques.csv", localized((*handlers.ItemHandler).ExportUniquesCSV))
+	router.Get("/export/sets.csv", localized((*handlers.ItemHandler).ExportSetsCSV))
+	router.Get("/export/runewords.csv", localized((*handlers.ItemHandler).ExportRunewordsCSV))
+}
+
+// RegisterAdminRoutes mounts the D2 catalog management endpoints
+func (g *d2Game) RegisterAdminRoutes(router fiber.Router) {
+	adminHandler := handlers.NewAdminHandler(g.repo, g.config.Cache)
+
+	router.Post("/classes", adminHandler.CreateClass)
+	router.Put("/classes/:classId", adminHandler.UpdateClass)
+
+	router.Post("/purge", adminHandler.PurgeItemType)
+	router.Get("/integrity", adminHandler.GetIntegrity)
+	router.Post("/images", adminHandler.UpdateImageURLs)
+	router.Get("/images/missing", adminHandler.GetMissingImages)
+	router.Get("/stats/unmapped", adminHandler.GetUnmappedStats)
+	router.Post("/translate", adminHandler.Translate)
+	router.Get("/export", adminHandler.ExportCatalog)
+
+	router.Post("/runewords", adminHandler.CreateRuneword)
+	router.Delete("/runewords/:id", adminHandler.DeleteRuneword)
+
+	itemHandler := handlers.NewItemHandler(g.repo, handlers.ItemHandlerConfig{
+		ImagePlaceholderURL: g.config.ImagePlaceholderURL,
+	})
+	router.Get("/uniques", itemHandler.Localized((*handlers.ItemHandler).GetAllUniquesAdmin))
+
+	items := router.Group("/items")
+	items.Post("/:type", adminHandler.CreateItem)
+	items.Put("/:type/:id", adminHandler.UpdateItem)
+	items.Delete("/:type/:id", adminHandler.DeleteItem)
+}
+
+// rateLimit returns the limiter for one route group of the D2 routes
+func (g *d2Game) rateLimit(group string, limit int) fiber.Handler {
+	return middleware.RateLimit(middleware.RateLimitConfig{
+		Group:   "d2:" + group,
+		Limit:   limit,
+		Counter: g.counter,
+		APIKeys: g.apiKeys,
+	})
+}
@@ -2,12 +2,14 @@ package dto
 
 // ItemSearchResult represents a single item in search autocomplete results
 type ItemSearchResult struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`     // "unique", "set", "runeword", "rune", "base", "gem"
-	Category string `json:"category"` // "Helms", "Armor", "Weapons", etc.
-	ImageURL string `json:"imageUrl,omitempty"`
-	BaseName string `json:"baseName,omitempty"` // For uniques/sets: "Shako", "Diadem", etc.
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`     // "unique", "set", "runeword", "rune", "base", "gem"
+	Category  string  `json:"category"` // "Helms", "Armor", "Weapons", etc.
+	ImageURL  string  `json:"imageUrl,omitempty"`
+	BaseName  string  `json:"baseName,omitempty"` // For uniques/sets: "Shako", "Diadem", etc.
+	MatchType string  `json:"matchType"`          // "name", "code", "base", "stat" or "fuzzy"
+	Score     float64 `json:"score,omitempty"`    // Relevance 0-1: 1 exact, 0.75 prefix, 0.5 substring, 0.25 base name; similarity for fuzzy
 }
 
 // SearchResponse wraps search results with pagination info
@@ -17,6 +19,92 @@ type SearchResponse struct {
 	Query      string             `json:"query"`
 }
 
+// StatSearchResponse represents the response for a search by stats
+type StatSearchResponse struct {
+	Items      []ItemSearchResult `json:"items"`
+	TotalCount int                `json:"totalCount"`
+	Stats      []string           `json:"stats"`
+	Match      string             `json:"match"` // "all" or "any"
+	Thresholds []StatThreshold    `json:"thresholds,omitempty"`
+	Value      string             `json:"value,omitempty"` // Roll bound thresholds compare: "max" or "min"
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
+
+// StatThreshold is a stat value bound applied by a stat search
+type StatThreshold struct {
+	Code string `json:"code"`
+	Min  *int   `json:"min,omitempty"`
+	Max  *int   `json:"max,omitempty"`
+}
+
+// OptimizeStatRequirement is a minimum stat value wanted by an optimize request
+type OptimizeStatRequirement struct {
+	Code string `json:"code"` // Stat code (e.g., "fcr", "mf")
+	Min  int    `json:"min"`  // Minimum wanted value
+}
+
+// OptimizeRequest represents a request to find gear meeting stat minimums
+type OptimizeRequest struct {
+	Stats  []OptimizeStatRequirement `json:"stats"`
+	Slot   string                    `json:"slot,omitempty"`  // Gear slot (e.g., "helm", "weapon")
+	Types  []string                  `json:"types,omitempty"` // unique, set, runeword (default all)
+	Limit  int                       `json:"limit,omitempty"`
+	Offset int                       `json:"offset,omitempty"`
+}
+
+// BatchItemRef identifies one item in a batch lookup
+type BatchItemRef struct {
+	Type string `json:"type"` // unique, set, runeword, rune, gem, base, quest
+	ID   int    `json:"id"`
+}
+
+// BatchItemsRequest represents a request for several item details at once
+type BatchItemsRequest struct {
+	Items []BatchItemRef `json:"items"`
+}
+
+// AffixDelta pairs an affix present on both compared items. Deltas are B
+// minus A and are nil when either side has no value.
+type AffixDelta struct {
+	Code     string    `json:"code"`
+	A        ItemAffix `json:"a"`
+	B        ItemAffix `json:"b"`
+	MinDelta *int      `json:"minDelta,omitempty"`
+	MaxDelta *int      `json:"maxDelta,omitempty"`
+}
+
+// AffixComparison is a stat diff between two items, matched by affix code
+type AffixComparison struct {
+	OnlyA  []ItemAffix  `json:"onlyA"`
+	OnlyB  []ItemAffix  `json:"onlyB"`
+	Common []AffixDelta `json:"common"`
+}
+
+// CompareItemsResponse is the side-by-side comparison of two items
+type CompareItemsResponse struct {
+	A          UnifiedItemDetail `json:"a"`
+	B          UnifiedItemDetail `json:"b"`
+	Comparison AffixComparison   `json:"comparison"`
+}
+
+// OptimizeResult represents an item ranked against stat requirements
+type OptimizeResult struct {
+	ItemSearchResult
+	RequirementsMet int            `json:"requirementsMet"`
+	Score           float64        `json:"score"`  // Sum of value/min ratios across requirements
+	Values          map[string]int `json:"values"` // Best value per requested stat
+}
+
+// OptimizeResponse represents the ranked results of an optimize request
+type OptimizeResponse struct {
+	Items        []OptimizeResult `json:"items"`
+	TotalCount   int              `json:"totalCount"`
+	Requirements int              `json:"requirements"`
+	Limit        int              `json:"limit"`
+	Offset       int              `json:"offset"`
+}
+
 // AffixOption represents a selectable option for an affix
 type AffixOption struct {
 	Value string `json:"value"` // Internal value (e.g., "amazon", "sorceress")
@@ -45,6 +133,10 @@ type ItemAffix struct {
 	HasRange    bool          `json:"hasRange"`          // true if min != max
 	Code        string        `json:"code"`              // Internal code for filtering
 	Options     []AffixOption `json:"options,omitempty"` // For special affixes like randclassskill
+
+	// Alternatives are the other options of an "X or Y" set bonus, this
+	// affix being the first; render as "X OR Y". Only one applies.
+	Alternatives []ItemAffix `json:"alternatives,omitempty"`
 }
 
 // ItemRequirements represents level and stat requirements
@@ -56,15 +148,17 @@ type ItemRequirements struct {
 
 // ItemBaseInfo represents the base item information
 type ItemBaseInfo struct {
-	Code       string        `json:"code"`
-	Name       string        `json:"name"`
-	Category   string        `json:"category"` // "armor", "weapon", "misc"
-	ItemType   string        `json:"itemType"` // "helm", "body armor", etc.
-	Defense    *DefenseRange `json:"defense,omitempty"`
-	MinDamage  *int          `json:"minDamage,omitempty"`
-	MaxDamage  *int          `json:"maxDamage,omitempty"`
-	MaxSockets int           `json:"maxSockets,omitempty"`
-	Durability int           `json:"durability,omitempty"`
+	Code             string        `json:"code"`
+	Name             string        `json:"name"`
+	Category         string        `json:"category"` // "armor", "weapon", "misc"
+	ItemType         string        `json:"itemType"` // "helm", "body armor", etc.
+	ClassSpecific    string        `json:"classSpecific,omitempty"`    // "sorceress", etc.
+	ClassRestriction string        `json:"classRestriction,omitempty"` // "Sorceress Only", etc.
+	Defense          *DefenseRange `json:"defense,omitempty"`
+	MinDamage        *int          `json:"minDamage,omitempty"`
+	MaxDamage        *int          `json:"maxDamage,omitempty"`
+	MaxSockets       int           `json:"maxSockets,omitempty"`
+	Durability       int           `json:"durability,omitempty"`
 }
 
 // ItemQuality represents item quality flags
@@ -85,6 +179,9 @@ type UniqueItemDetail struct {
 	Affixes      []ItemAffix      `json:"affixes"`
 	LadderOnly   bool             `json:"ladderOnly"`
 	ImageURL     string           `json:"imageUrl,omitempty"`
+	HasImage     bool             `json:"hasImage"`
+	VendorValue  int              `json:"vendorValue,omitempty"` // NPC value in gold
+	Disabled     bool             `json:"disabled,omitempty"`    // Hidden from lists and search (quest bases)
 }
 
 // SetItemDetail represents a set item with all its information
@@ -99,6 +196,10 @@ type SetItemDetail struct {
 	Affixes         []ItemAffix      `json:"affixes"`      // Always active
 	BonusAffixes    []ItemAffix      `json:"bonusAffixes"` // Partial set bonuses
 	ImageURL        string           `json:"imageUrl,omitempty"`
+	HasImage        bool             `json:"hasImage"`
+	VendorValue     int              `json:"vendorValue,omitempty"` // NPC value in gold
+	SetItemCount    int              `json:"setItemCount"`          // Items in the whole set
+	SetSiblings     []string         `json:"setSiblings"`           // Names of the set's other items
 }
 
 // SetBonusDetail represents a complete set with its bonuses
@@ -106,17 +207,88 @@ type SetBonusDetail struct {
 	ID             int         `json:"id"`
 	Name           string      `json:"name"`
 	Items          []string    `json:"items"` // Names of items in the set
+	SetItemCount   int         `json:"setItemCount"`
 	PartialBonuses []ItemAffix `json:"partialBonuses"` // 2-4 items bonuses
 	FullBonuses    []ItemAffix `json:"fullBonuses"`    // Complete set bonuses
 }
 
-// RunewordBaseItem represents a valid base item for a runeword
+// SetCombinedStats represents the aggregated stats of a complete set
+type SetCombinedStats struct {
+	SetName string      `json:"setName"`
+	Items   []string    `json:"items"`   // Names of items in the set
+	Affixes []ItemAffix `json:"affixes"` // Combined stats with all pieces equipped
+}
+
+// FullSetResponse is a set with its bonuses and every member item's detail
+type FullSetResponse struct {
+	SetName string           `json:"setName"`
+	Bonus   *SetBonusDetail  `json:"bonus,omitempty"` // Omitted when the set has no bonuses
+	Tiers   []SetBonusTier   `json:"tiers"`           // Bonuses grouped by pieces equipped, ascending
+	Items   []*SetItemDetail `json:"items"`
+}
+
+// SetBonusTier represents the set bonuses activated at an equipped count
+type SetBonusTier struct {
+	ItemCount int         `json:"itemCount"` // Pieces equipped to activate
+	Full      bool        `json:"full"`      // Full set completion tier
+	Affixes   []ItemAffix `json:"affixes"`
+}
+
+// SetBonusProgress represents the set bonuses active at an equipped count
+type SetBonusProgress struct {
+	SetName    string         `json:"setName"`
+	Equipped   int            `json:"equipped"`
+	TotalItems int            `json:"totalItems"`
+	Active     []SetBonusTier `json:"active"` // Tiers unlocked at this count (cumulative)
+	Locked     []SetBonusTier `json:"locked"` // Higher tiers still to unlock
+}
+
+// SameBaseItems lists the other uniques and set items built on the same base
+type SameBaseItems struct {
+	Base     ItemBaseInfo        `json:"base"`
+	Uniques  []*UniqueItemDetail `json:"uniques"`
+	SetItems []*SetItemDetail    `json:"setItems"`
+}
+
+// RunewordBaseItem represents a valid base item for a runeword. The base must
+// be rolled with exactly RequiredSockets sockets, which may be fewer than
+// MaxSockets.
 type RunewordBaseItem struct {
-	ID         int    `json:"id"`
-	Code       string `json:"code"`
-	Name       string `json:"name"`
-	Category   string `json:"category"`
-	MaxSockets int    `json:"maxSockets"`
+	ID              int    `json:"id"`
+	Code            string `json:"code"`
+	Name            string `json:"name"`
+	Category        string `json:"category"`
+	MaxSockets      int    `json:"maxSockets"`
+	RequiredSockets int    `json:"requiredSockets"`
+}
+
+// BaseRunewordItem represents a runeword a base item can host
+type BaseRunewordItem struct {
+	ID              int            `json:"id"`
+	Name            string         `json:"name"`
+	DisplayName     string         `json:"displayName"`
+	LadderOnly      bool           `json:"ladderOnly"`
+	Runes           []RunewordRune `json:"runes"`
+	RuneOrder       string         `json:"runeOrder"` // e.g. "JahIthBer"
+	RequiredSockets int            `json:"requiredSockets"`
+	ImageURL        string         `json:"imageUrl,omitempty"`
+}
+
+// RunewordPreviewStats is a runeword's final defense and damage on one base
+type RunewordPreviewStats struct {
+	Defense *DefenseRange `json:"defense,omitempty"`
+	Damage  *DamageRange  `json:"damage,omitempty"`
+}
+
+// RunewordPreview shows a runeword's computed stats on a specific base
+type RunewordPreview struct {
+	RunewordID      int                   `json:"runewordId"`
+	RunewordName    string                `json:"runewordName"`
+	Base            RunewordBaseItem      `json:"base"`
+	RequiredSockets int                   `json:"requiredSockets"`
+	Fits            bool                  `json:"fits"` // Base can hold enough sockets for every rune
+	Normal          RunewordPreviewStats  `json:"normal"`
+	Ethereal        *RunewordPreviewStats `json:"ethereal,omitempty"` // Absent when the base can't be ethereal
 }
 
 // RunewordRune represents a rune in a runeword with display info
@@ -127,6 +299,15 @@ type RunewordRune struct {
 	ImageURL string `json:"imageUrl,omitempty"`
 }
 
+// RuneSequenceEntry is one socket of a runeword's rune sequence
+type RuneSequenceEntry struct {
+	Position   int    `json:"position"`   // 1-based socket position
+	RuneNumber int    `json:"runeNumber"` // 1-33, 0 if the rune is unknown
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	ImageURL   string `json:"imageUrl,omitempty"`
+}
+
 // RunewordValidType represents a valid item type for a runeword
 type RunewordValidType struct {
 	Code string `json:"code"`
@@ -135,19 +316,22 @@ type RunewordValidType struct {
 
 // RunewordDetail represents a runeword with all its information
 type RunewordDetail struct {
-	ID             int                 `json:"id"`
-	Name           string              `json:"name"`
-	DisplayName    string              `json:"displayName"` // Properly formatted name
-	Type           string              `json:"type"`        // Always "runeword"
-	Rarity         string              `json:"rarity"`      // "runeword"
-	Runes          []RunewordRune      `json:"runes"`       // Runes with names and icons
-	RuneOrder      string              `json:"runeOrder"`   // "JahIthBer"
-	ValidTypes     []RunewordValidType `json:"validTypes"`  // Item types with names
-	ValidBaseItems []RunewordBaseItem  `json:"validBaseItems,omitempty"` // Actual base items
-	Requirements   ItemRequirements    `json:"requirements"`
-	Affixes        []ItemAffix         `json:"affixes"`
-	LadderOnly     bool                `json:"ladderOnly"`
-	ImageURL       string              `json:"imageUrl,omitempty"`
+	ID              int                 `json:"id"`
+	Name            string              `json:"name"`
+	DisplayName     string              `json:"displayName"`              // Properly formatted name
+	Type            string              `json:"type"`                     // Always "runeword"
+	Rarity          string              `json:"rarity"`                   // "runeword"
+	Runes           []RunewordRune      `json:"runes"`                    // Runes with names and icons
+	RuneOrder       string              `json:"runeOrder"`                // "JahIthBer"
+	RuneSequence    []RuneSequenceEntry `json:"runeSequence"`             // Socketing order, duplicates kept
+	RequiredSockets int                 `json:"requiredSockets"`          // Always len(runes)
+	ValidTypes      []RunewordValidType `json:"validTypes"`               // Item types with names
+	ValidBaseItems  []RunewordBaseItem  `json:"validBaseItems,omitempty"` // Actual base items
+	Requirements    ItemRequirements    `json:"requirements"`
+	Affixes         []ItemAffix         `json:"affixes"`
+	LadderOnly      bool                `json:"ladderOnly"`
+	ImageURL        string              `json:"imageUrl,omitempty"`
+	HasImage        bool                `json:"hasImage"`
 }
 
 // RuneDetail represents a rune with all its information
@@ -163,6 +347,65 @@ type RuneDetail struct {
 	ArmorMods    []ItemAffix     `json:"armorMods"`  // Helm/Shield mods are same as armor
 	ShieldMods   []ItemAffix     `json:"shieldMods"`
 	ImageURL     string          `json:"imageUrl,omitempty"`
+	HasImage     bool            `json:"hasImage"`
+	VendorValue  int             `json:"vendorValue,omitempty"` // NPC value in gold
+}
+
+// RuneFullDetail represents a rune with its socket mods and the runewords that use it
+type RuneFullDetail struct {
+	Rune      *RuneDetail       `json:"rune"`
+	Runewords []*RunewordDetail `json:"runewords"`
+}
+
+// RuneRef identifies a rune in an upgrade chain
+type RuneRef struct {
+	ID         int    `json:"id"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RuneNumber int    `json:"runeNumber"`
+	ImageURL   string `json:"imageUrl,omitempty"`
+}
+
+// RuneRecipe is the Horadric Cube recipe for upgrading a rune
+type RuneRecipe struct {
+	Quantity int    `json:"quantity"`      // Runes of the lower tier consumed
+	Gem      string `json:"gem,omitempty"` // Gem added to the cube, if any
+}
+
+// RuneUpgradeStep is one cube upgrade in a rune chain
+type RuneUpgradeStep struct {
+	From   RuneRef    `json:"from"`
+	To     RuneRef    `json:"to"`
+	Recipe RuneRecipe `json:"recipe"`
+}
+
+// RuneUpgradePath represents a rune's next upgrade and the chain up to Zod
+type RuneUpgradePath struct {
+	Rune   RuneRef           `json:"rune"`
+	Next   *RuneRef          `json:"next"`   // Null for Zod
+	Recipe *RuneRecipe       `json:"recipe"` // Null for Zod
+	Chain  []RuneUpgradeStep `json:"chain"`
+}
+
+// GemProgression represents every quality tier of one gem type
+type GemProgression struct {
+	GemType   string       `json:"gemType"`
+	CurrentID int          `json:"currentId"` // ID of the requested gem
+	Tiers     []*GemDetail `json:"tiers"`     // Chipped to perfect
+}
+
+// SocketableItem represents a rune, gem or skull in the merged socketables list
+type SocketableItem struct {
+	ID         int         `json:"id"`
+	Kind       string      `json:"kind"` // "rune", "gem" or "skull"
+	Code       string      `json:"code"`
+	Name       string      `json:"name"`
+	LevelReq   int         `json:"levelReq,omitempty"`
+	WeaponMods []ItemAffix `json:"weaponMods,omitempty"`
+	ArmorMods  []ItemAffix `json:"armorMods,omitempty"` // Helm/body armor mods
+	ShieldMods []ItemAffix `json:"shieldMods,omitempty"`
+	ImageURL   string      `json:"imageUrl,omitempty"`
+	HasImage   bool        `json:"hasImage"`
 }
 
 // GemDetail represents a gem with all its information
@@ -172,35 +415,54 @@ type GemDetail struct {
 	Name       string      `json:"name"`      // "Perfect Ruby", "Flawless Sapphire"
 	GemType    string      `json:"gemType"`   // "ruby", "sapphire", etc.
 	Quality    string      `json:"quality"`   // "chipped", "flawed", "normal", "flawless", "perfect"
+	IsSkull    bool        `json:"isSkull"`   // Skulls aren't gems but socket like one
 	Type       string      `json:"type"`      // Always "gem"
 	Rarity     string      `json:"rarity"`    // "gem"
 	WeaponMods []ItemAffix `json:"weaponMods"`
 	ArmorMods  []ItemAffix `json:"armorMods"`
 	ShieldMods []ItemAffix `json:"shieldMods"`
 	ImageURL   string      `json:"imageUrl,omitempty"`
+	HasImage   bool        `json:"hasImage"`
 }
 
 // BaseItemDetail represents a base item (armor, weapon, misc)
 type BaseItemDetail struct {
-	ID            int              `json:"id"`
-	Code          string           `json:"code"`
-	Name          string           `json:"name"`
-	Type          string           `json:"type"`     // Always "base"
-	Rarity        string           `json:"rarity"`   // "normal"
-	Category      string           `json:"category"` // "armor", "weapon", "misc"
-	ItemType      string           `json:"itemType"` // "helm", "body armor", etc.
-	Tier          string           `json:"tier,omitempty"`
-	TypeTags      []string         `json:"typeTags,omitempty"`
-	ClassSpecific string           `json:"classSpecific,omitempty"`
-	Requirements  ItemRequirements `json:"requirements"`
-	Defense       *DefenseRange    `json:"defense,omitempty"`
-	Damage        *DamageRange     `json:"damage,omitempty"`
-	Speed         int              `json:"speed,omitempty"`
-	MaxSockets    int              `json:"maxSockets"`
-	Durability    int              `json:"durability"`
-	QualityTiers  QualityTiers     `json:"qualityTiers,omitempty"`
-	ImageURL      string           `json:"imageUrl,omitempty"`
-	IconVariants  []string         `json:"iconVariants,omitempty"`
+	ID               int              `json:"id"`
+	Code             string           `json:"code"`
+	Name             string           `json:"name"`
+	Type             string           `json:"type"`     // Always "base"
+	Rarity           string           `json:"rarity"`   // "normal"
+	Category         string           `json:"category"` // "armor", "weapon", "misc"
+	ItemType         string           `json:"itemType"` // "helm", "body armor", etc.
+	Tier             string           `json:"tier,omitempty"`
+	TypeTags         []string         `json:"typeTags,omitempty"`
+	ClassSpecific    string           `json:"classSpecific,omitempty"`    // "sorceress", etc.
+	ClassRestriction string           `json:"classRestriction,omitempty"` // "Sorceress Only", etc.
+	CanBeMagic       bool             `json:"canBeMagic"`
+	CanBeRare        bool             `json:"canBeRare"`
+	CanBeUnique      bool             `json:"canBeUnique"`
+	Requirements     ItemRequirements `json:"requirements"`
+	Defense          *DefenseRange    `json:"defense,omitempty"`
+	Damage           *DamageRange     `json:"damage,omitempty"`
+	Speed            int              `json:"speed,omitempty"`
+	MaxSockets       int              `json:"maxSockets"`
+	Durability       int              `json:"durability"`
+	QualityTiers     QualityTiers     `json:"qualityTiers,omitempty"`
+	ImageURL         string           `json:"imageUrl,omitempty"`
+	HasImage         bool             `json:"hasImage"`
+	IconVariants     []string         `json:"iconVariants,omitempty"`
+	VendorValue      int              `json:"vendorValue,omitempty"` // NPC value in gold
+	CanBeEthereal    bool             `json:"canBeEthereal"`
+	Ethereal         *EtherealStats   `json:"ethereal,omitempty"`    // Stats in ethereal form
+	Placeholder      bool             `json:"placeholder,omitempty"` // "Expansion"/"Not Used" style row kept by the import
+}
+
+// EtherealStats represents a base item's stats in ethereal form
+// (1.5x defense and damage, -10 strength/dexterity requirements)
+type EtherealStats struct {
+	Requirements ItemRequirements `json:"requirements"`
+	Defense      *DefenseRange    `json:"defense,omitempty"`
+	Damage       *DamageRange     `json:"damage,omitempty"`
 }
 
 // DefenseRange represents armor defense values
@@ -233,6 +495,7 @@ type QuestItemDetail struct {
 	Type        string `json:"type"`   // "quest"
 	Rarity      string `json:"rarity"` // "quest"
 	ImageURL    string `json:"imageUrl,omitempty"`
+	HasImage    bool   `json:"hasImage"`
 }
 
 // ClassDetail represents a character class with skill trees
@@ -289,11 +552,57 @@ type MarketplaceFilters struct {
 	AskingForItems []string `json:"askingForItems,omitempty"` // ["Ist", "Ber"] - filter by what sellers want
 }
 
-// ErrorResponse represents an API error
+// ChangeEntry is one catalog item in the changes feed
+type ChangeEntry struct {
+	Type      string `json:"type"` // unique, set, runeword, rune, gem, base, quest
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	UpdatedAt string `json:"updatedAt"` // RFC 3339 with sub-second precision
+}
+
+// ChangesResponse is one page of the changes feed. Pass NextSince and
+// NextAfter back as since and after to get the following page, or to poll
+// for later changes once HasMore is false.
+type ChangesResponse struct {
+	Changes   []ChangeEntry `json:"changes"`
+	NextSince string        `json:"nextSince"`
+	NextAfter string        `json:"nextAfter,omitempty"` // "type:id" of the last entry
+	HasMore   bool          `json:"hasMore"`
+}
+
+// Machine-readable error codes carried in ErrorResponse.Error
+const (
+	ErrCodeBadRequest       = "bad_request"
+	ErrCodeUnauthorized     = "unauthorized"
+	ErrCodeForbidden        = "forbidden"
+	ErrCodeNotFound         = "not_found"
+	ErrCodeValidationFailed = "validation_failed"
+	ErrCodeRateLimited      = "rate_limited"
+	ErrCodeInternal         = "internal_error"
+)
+
+// ErrorResponse represents an API error. RequestID matches the X-Request-ID
+// response header and the request's log line.
 type ErrorResponse struct {
-	Error   string `json:"error"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// FieldError describes one invalid field in a request body
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Code    int    `json:"code"`
+}
+
+// ValidationErrorResponse represents a 422 response with per-field errors
+type ValidationErrorResponse struct {
+	Error     string       `json:"error"`
+	Message   string       `json:"message"`
+	Code      int          `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Fields    []FieldError `json:"fields"`
 }
 
 // StatCode represents a filterable stat code for marketplace filtering
@@ -306,6 +615,23 @@ type StatCode struct {
 	IsVariable  bool     `json:"isVariable"`            // Whether this stat typically has variable rolls on items
 }
 
+// StatCategory represents a stat category in canonical display order
+type StatCategory struct {
+	Name      string `json:"name"`           // Category name (e.g., "Speed", "Resistances")
+	SortIndex int    `json:"sortIndex"`      // Position in the display order (0-based)
+	Icon      string `json:"icon,omitempty"` // Icon hint for the UI (e.g., "speed")
+	StatCount int    `json:"statCount"`      // Number of stats in this category
+}
+
+// StatRange is the range a stat actually takes across the catalog, for
+// sizing range sliders. Min and Max are omitted when no item has the stat.
+type StatRange struct {
+	Code  string `json:"code"`          // Primary stat code (aliases are resolved)
+	Min   *int   `json:"min,omitempty"` // Lowest observed value
+	Max   *int   `json:"max,omitempty"` // Highest observed value
+	Count int    `json:"count"`         // Number of item properties carrying the stat
+}
+
 // Category represents an item category for filtering
 type Category struct {
 	Code        string `json:"code"`                  // Internal code for filtering (e.g., "helm", "armor", "weapon")
@@ -321,6 +647,23 @@ type Rarity struct {
 	Description string `json:"description"` // Brief description of this rarity type
 }
 
+// ItemTypeSockets holds an item type's socket maxima per difficulty
+type ItemTypeSockets struct {
+	Normal    int `json:"normal"`
+	Nightmare int `json:"nightmare"`
+	Hell      int `json:"hell"`
+}
+
+// ItemTypeInfo represents an item type and its place in the type hierarchy
+type ItemTypeInfo struct {
+	Code             string          `json:"code"`
+	Name             string          `json:"name"`
+	Parents          []string        `json:"parents"`            // Ancestor type codes, nearest first
+	Children         []string        `json:"children,omitempty"` // Direct child type codes (detail only)
+	MaxSockets       ItemTypeSockets `json:"maxSockets"`
+	ClassRestriction string          `json:"classRestriction,omitempty"`
+}
+
 // Admin request DTOs
 
 // PropertyInput represents a property in create/update requests
@@ -430,3 +773,139 @@ type UpdateClassRequest struct {
 	SkillSuffix string         `json:"skillSuffix"`
 	SkillTrees  []SkillTreeDTO `json:"skillTrees"`
 }
+
+// PurgeResponse reports the result of an admin purge of one item type
+type PurgeResponse struct {
+	Type         string `json:"type"`
+	RowsAffected int    `json:"rowsAffected"`
+}
+
+// OrphanedItem is a unique or set item whose base code matches no item base
+type OrphanedItem struct {
+	Table    string `json:"table"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	BaseCode string `json:"baseCode"`
+	BaseName string `json:"baseName,omitempty"`
+}
+
+// IntegrityResponse lists catalog rows with dangling references
+type IntegrityResponse struct {
+	Orphans []OrphanedItem `json:"orphans"`
+	Count   int            `json:"count"`
+}
+
+// ImageURLInput is one row of a bulk image URL update
+type ImageURLInput struct {
+	Type string `json:"type"` // "unique", "set", "runeword", "rune", "gem", "base" or "quest"
+	ID   int    `json:"id"`
+	URL  string `json:"url"`
+}
+
+// ImageURLResult reports the outcome of one bulk image URL row
+type ImageURLResult struct {
+	Type    string `json:"type"`
+	ID      int    `json:"id"`
+	Updated bool   `json:"updated"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ImageURLsResponse is the result of a bulk image URL update, one entry per
+// request row in request order
+type ImageURLsResponse struct {
+	Results []ImageURLResult `json:"results"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+}
+
+// UnmappedStat is a stat code found in the registry or item data that the
+// stat filters don't cover
+type UnmappedStat struct {
+	Code        string `json:"code"`
+	Name        string `json:"name,omitempty"`
+	DisplayText string `json:"displayText,omitempty"`
+	ItemCount   int    `json:"itemCount"`
+	Registered  bool   `json:"registered"` // Persisted in the stat registry
+}
+
+// UnmappedStatsResponse lists the stat codes missing from the stat filters
+type UnmappedStatsResponse struct {
+	Stats []UnmappedStat `json:"stats"`
+	Count int            `json:"count"`
+}
+
+// TranslateRequest is a raw property and/or a display line to run through the
+// property translators. At least one of code and text is required.
+type TranslateRequest struct {
+	Code  string `json:"code,omitempty"`
+	Param string `json:"param,omitempty"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max"`
+	Text  string `json:"text,omitempty"` // Display line to reverse-translate
+}
+
+// TranslateResponse reports what the translators make of a TranslateRequest
+type TranslateResponse struct {
+	DisplayText string         `json:"displayText,omitempty"` // Rendered text of the raw property
+	DisplayName string         `json:"displayName,omitempty"` // Short stat name of the raw property's code
+	Reverse     *ReverseResult `json:"reverse,omitempty"`     // Present when text was given
+}
+
+// ReverseResult is the property a display line resolves to, rendered back to
+// text so mismatches between the two directions show up
+type ReverseResult struct {
+	Property    PropertyInput `json:"property"`
+	Matched     bool          `json:"matched"`     // False when the line fell back to a "raw" property
+	DisplayText string        `json:"displayText"` // The resolved property translated again
+	RoundTrip   bool          `json:"roundTrip"`   // displayText equals the input line, ignoring case
+}
+
+// MissingImageCategory counts the items of one type that have no image
+type MissingImageCategory struct {
+	Type  string   `json:"type"` // "unique", "set", "runeword", "base", "rune", "gem"
+	Count int      `json:"count"`
+	Names []string `json:"names"` // First names only, see ?limit=
+}
+
+// MissingImagesResponse summarizes items without images across the catalog
+type MissingImagesResponse struct {
+	Categories []MissingImageCategory `json:"categories"`
+	Total      int                    `json:"total"`
+}
+
+// BreakpointEntry represents a single speed breakpoint
+type BreakpointEntry struct {
+	Value  int `json:"value"`  // Minimum stat value
+	Frames int `json:"frames"` // Animation frames at this value
+}
+
+// BreakpointResponse represents the breakpoints for a class's speed stat
+type BreakpointResponse struct {
+	Class         string            `json:"class"`
+	Stat          string            `json:"stat"` // "fcr" or "fhr"
+	Current       int               `json:"current"`
+	CurrentFrames int               `json:"currentFrames"`
+	Next          *BreakpointEntry  `json:"next"` // Null when past the last breakpoint
+	Breakpoints   []BreakpointEntry `json:"breakpoints"`
+}
+
+// BaseBreakpointsResponse lists attack speed breakpoints for a weapon base.
+// Applicable is false, with a Reason, for bases that can't attack.
+type BaseBreakpointsResponse struct {
+	BaseID      int                   `json:"baseId"`
+	Name        string                `json:"name"`
+	WeaponClass string                `json:"weaponClass,omitempty"` // Attack animation: "1hs", "2ht", "bow", ...
+	Speed       int                   `json:"speed"`                 // Weapon speed modifier
+	IAS         int                   `json:"ias"`
+	Applicable  bool                  `json:"applicable"`
+	Reason      string                `json:"reason,omitempty"`
+	Classes     []ClassIASBreakpoints `json:"classes"`
+}
+
+// ClassIASBreakpoints holds one class's IAS breakpoints on a weapon base
+type ClassIASBreakpoints struct {
+	Class         string            `json:"class"`
+	CurrentFrames int               `json:"currentFrames"` // Frames per attack at the requested IAS
+	Next          *BreakpointEntry  `json:"next"`          // Null when past the last breakpoint
+	Breakpoints   []BreakpointEntry `json:"breakpoints"`
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
 )
 
@@ -12,16 +18,30 @@ import (
 type AdminHandler struct {
 	repo       *d2.Repository
 	translator *d2.PropertyTranslator
+	reverse    *d2.ReverseTranslator
+	cache      *cache.RedisCache
 }
 
-// NewAdminHandler creates a new admin handler
-func NewAdminHandler(repo *d2.Repository) *AdminHandler {
+// NewAdminHandler creates a new admin handler. itemCache may be nil when
+// item detail caching is disabled.
+func NewAdminHandler(repo *d2.Repository, itemCache *cache.RedisCache) *AdminHandler {
 	return &AdminHandler{
 		repo:       repo,
-		translator: d2.DefaultTranslator,
+		translator: d2.DefaultTranslator.WithSkillNames(repo.SkillNames()),
+		reverse:    d2.NewReverseTranslator(),
+		cache:      itemCache,
 	}
 }
 
+// invalidateItemCache drops cached item details matching pattern after a
+// successful write. Failures are ignored; entries expire on their own TTL.
+func (h *AdminHandler) invalidateItemCache(c *fiber.Ctx, pattern string) {
+	if h.cache == nil || c.Response().StatusCode() >= fiber.StatusMultipleChoices {
+		return
+	}
+	_ = h.cache.DeleteByPattern(c.UserContext(), pattern)
+}
+
 // CreateItem handles creating items of any type
 // POST /admin/d2/items/:type
 func (h *AdminHandler) CreateItem(c *fiber.Ctx) error {
@@ -43,11 +63,7 @@ func (h *AdminHandler) CreateItem(c *fiber.Ctx) error {
 	case "quest":
 		return h.createQuestItem(c)
 	default:
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest")
 	}
 }
 
@@ -57,13 +73,13 @@ func (h *AdminHandler) UpdateItem(c *fiber.Ctx) error {
 	itemType := c.Params("type")
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
+	// Details embed related rows (a unique shows its base, a runeword its
+	// runes), so any update drops every cached detail
+	defer h.invalidateItemCache(c, cache.D2ItemDetailsPattern())
+
 	switch itemType {
 	case "unique":
 		return h.updateUniqueItem(c, id)
@@ -80,11 +96,7 @@ func (h *AdminHandler) UpdateItem(c *fiber.Ctx) error {
 	case "quest":
 		return h.updateQuestItem(c, id)
 	default:
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest")
 	}
 }
 
@@ -94,42 +106,304 @@ func (h *AdminHandler) DeleteItem(c *fiber.Ctx) error {
 	itemType := c.Params("type")
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
 	if itemType != "quest" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Delete is only supported for quest items",
-			Code:    400,
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Delete is only supported for quest items")
+	}
+
+	if err := h.repo.DeleteQuestItem(c.UserContext(), id); err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Quest item not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PurgeItemType soft-deletes every row of one item type so a targeted re-import
+// can repopulate it cleanly. The confirm parameter must repeat the type.
+// POST /admin/d2/purge?type=gems&confirm=gems
+func (h *AdminHandler) PurgeItemType(c *fiber.Ctx) error {
+	itemType := strings.ToLower(c.Query("type"))
+
+	valid := false
+	for _, t := range d2.PurgeableItemTypes() {
+		if t == itemType {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid type. Must be one of: "+strings.Join(d2.PurgeableItemTypes(), ", "))
+	}
+
+	if c.Query("confirm") != itemType {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Purge requires confirm="+itemType)
+	}
+
+	defer h.invalidateItemCache(c, cache.D2ItemDetailsPattern())
+
+	affected, err := h.repo.PurgeItemType(c.UserContext(), itemType)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to purge items")
+	}
+
+	return c.JSON(dto.PurgeResponse{
+		Type:         itemType,
+		RowsAffected: affected,
+	})
+}
+
+// GetIntegrity reports uniques and set items whose base code matches no item base
+// GET /admin/d2/integrity
+func (h *AdminHandler) GetIntegrity(c *fiber.Ctx) error {
+	orphans, err := h.repo.FindOrphanedItems(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to check item integrity")
+	}
+
+	result := make([]dto.OrphanedItem, 0, len(orphans))
+	for _, o := range orphans {
+		result = append(result, dto.OrphanedItem{
+			Table:    o.Table,
+			ID:       o.ID,
+			Name:     o.Name,
+			BaseCode: o.BaseCode,
+			BaseName: o.BaseName,
 		})
 	}
 
-	if err := h.repo.DeleteQuestItem(c.Context(), id); err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Quest item not found",
-			Code:    404,
+	return c.JSON(dto.IntegrityResponse{
+		Orphans: result,
+		Count:   len(result),
+	})
+}
+
+// maxImageURLUpdates bounds the rows of one bulk image URL request
+const maxImageURLUpdates = 1000
+
+// UpdateImageURLs sets the image URL of many items at once, for icons hosted
+// outside the configured storage. Rows are validated first; the valid ones
+// are written in one transaction. Each row reports whether it was updated.
+// POST /admin/d2/images
+func (h *AdminHandler) UpdateImageURLs(c *fiber.Ctx) error {
+	var req []dto.ImageURLInput
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+	if len(req) == 0 || len(req) > maxImageURLUpdates {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, fmt.Sprintf("Send between 1 and %d rows", maxImageURLUpdates))
+	}
+
+	result := dto.ImageURLsResponse{Results: make([]dto.ImageURLResult, len(req))}
+	updates := make([]d2.ImageURLUpdate, 0, len(req))
+	rows := make([]int, 0, len(req)) // request index of each update
+	for i, in := range req {
+		result.Results[i] = dto.ImageURLResult{Type: in.Type, ID: in.ID}
+		if msg := validateImageURLInput(in); msg != "" {
+			result.Results[i].Error = msg
+			continue
+		}
+		updates = append(updates, d2.ImageURLUpdate{Type: in.Type, ID: in.ID, URL: in.URL})
+		rows = append(rows, i)
+	}
+
+	if len(updates) > 0 {
+		found, err := h.repo.UpdateImageURLs(c.UserContext(), updates)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update image URLs, no rows were changed")
+		}
+		for j, i := range rows {
+			if found[j] {
+				result.Results[i].Updated = true
+			} else {
+				result.Results[i].Error = "item not found"
+			}
+		}
+	}
+
+	for _, r := range result.Results {
+		if r.Updated {
+			result.Updated++
+		} else {
+			result.Failed++
+		}
+	}
+	if result.Updated > 0 {
+		h.invalidateItemCache(c, cache.D2ItemDetailsPattern())
+	}
+
+	return c.JSON(result)
+}
+
+// validateImageURLInput returns why a bulk image URL row is invalid, or ""
+func validateImageURLInput(in dto.ImageURLInput) string {
+	if !d2.IsImageItemType(in.Type) {
+		return "invalid type, must be one of: unique, set, runeword, rune, gem, base, quest"
+	}
+	if in.ID <= 0 {
+		return "invalid id"
+	}
+	u, err := url.Parse(in.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "invalid url, must be an absolute http(s) URL"
+	}
+	return ""
+}
+
+// GetUnmappedStats reports stat codes in the stat registry or in item data
+// that are neither filterable nor parametric, with the number of items using
+// each, so the filter list can be kept in sync with imported data
+// GET /admin/d2/stats/unmapped
+func (h *AdminHandler) GetUnmappedStats(c *fiber.Ctx) error {
+	registered, err := h.repo.GetAllStats(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get stats")
+	}
+	usage, err := h.repo.GetStatUsage(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to count stat usage")
+	}
+
+	unmapped := d2.UnmappedStats(registered, usage)
+	result := make([]dto.UnmappedStat, 0, len(unmapped))
+	for _, u := range unmapped {
+		result = append(result, dto.UnmappedStat{
+			Code:        u.Code,
+			Name:        u.Name,
+			DisplayText: u.DisplayText,
+			ItemCount:   u.ItemCount,
+			Registered:  u.Registered,
 		})
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	return c.JSON(dto.UnmappedStatsResponse{
+		Stats: result,
+		Count: len(result),
+	})
+}
+
+// Translate runs a raw property through the property translator and/or a
+// display line through the reverse translator, for debugging mismatched
+// affix text
+// POST /admin/d2/translate
+func (h *AdminHandler) Translate(c *fiber.Ctx) error {
+	var req dto.TranslateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Code == "" && req.Text == "" {
+		var errs fieldErrors
+		errs.add("code", "code or text is required")
+		return respondValidationErrors(c, errs)
+	}
+
+	var result dto.TranslateResponse
+	if req.Code != "" {
+		prop := d2.Property{Code: req.Code, Param: req.Param, Min: req.Min, Max: req.Max}
+		result.DisplayText = h.translator.Translate(prop)
+		result.DisplayName = h.translator.GetDisplayName(req.Code)
+	}
+	if req.Text != "" {
+		prop := h.reverse.ReverseTranslate(req.Text)
+		text := h.translator.Translate(prop)
+		result.Reverse = &dto.ReverseResult{
+			Property: dto.PropertyInput{
+				Code:  prop.Code,
+				Param: prop.Param,
+				Min:   prop.Min,
+				Max:   prop.Max,
+			},
+			Matched:     prop.Code != "raw",
+			DisplayText: text,
+			RoundTrip:   strings.EqualFold(text, req.Text),
+		}
+	}
+
+	return c.JSON(result)
+}
+
+// GetMissingImages reports how many items of each type have no image, with
+// the first ?limit= names per type (default 10, max 100)
+// GET /admin/d2/images/missing?limit=10
+func (h *AdminHandler) GetMissingImages(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	ctx := c.UserContext()
+	sources := []struct {
+		itemType string
+		load     func(context.Context) ([]d2.ItemWithoutImage, error)
+	}{
+		{"unique", h.repo.GetUniqueItemsWithoutImages},
+		{"set", h.repo.GetSetItemsWithoutImages},
+		{"base", h.repo.GetItemBasesWithoutImages},
+		{"rune", h.repo.GetRunesWithoutImages},
+		{"gem", h.repo.GetGemsWithoutImages},
+	}
+
+	result := dto.MissingImagesResponse{
+		Categories: make([]dto.MissingImageCategory, 0, len(sources)+1),
+	}
+	addCategory := func(itemType string, names []string) {
+		category := dto.MissingImageCategory{
+			Type:  itemType,
+			Count: len(names),
+			Names: names,
+		}
+		if len(names) > limit {
+			category.Names = names[:limit]
+		}
+		result.Categories = append(result.Categories, category)
+		result.Total += category.Count
+	}
+
+	for _, src := range sources {
+		items, err := src.load(ctx)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to list "+src.itemType+" items without images")
+		}
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		addCategory(src.itemType, names)
+	}
+
+	runewords, err := h.repo.GetRunewordsWithoutImages(ctx)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to list runewords without images")
+	}
+	names := make([]string, 0, len(runewords))
+	for _, rw := range runewords {
+		name := rw.DisplayName
+		if name == "" {
+			name = rw.Name
+		}
+		names = append(names, name)
+	}
+	addCategory("runeword", names)
+
+	return c.JSON(result)
 }
 
 // convertInputProperties converts PropertyInput DTOs to d2.Property entities
 func convertInputProperties(inputs []dto.PropertyInput) []d2.Property {
 	props := make([]d2.Property, 0, len(inputs))
 	for _, p := range inputs {
-		props = append(props, d2.Property{
+		props = append(props, d2.NormalizeReductionSign(d2.Property{
 			Code:  p.Code,
 			Param: p.Param,
 			Min:   p.Min,
 			Max:   p.Max,
-		})
+		}))
 	}
 	return props
 }
@@ -139,23 +413,19 @@ func convertInputProperties(inputs []dto.PropertyInput) []d2.Property {
 func (h *AdminHandler) createUniqueItem(c *fiber.Ctx) error {
 	var req dto.CreateUniqueItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateUniqueItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Name == "" || req.BaseCode == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Name and baseCode are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Name and baseCode are required")
 	}
 
 	// Get next index ID
-	maxIndex, _ := h.repo.GetMaxIndexID(c.Context(), "unique_items")
+	maxIndex, _ := h.repo.GetMaxIndexID(c.UserContext(), "unique_items")
 
 	item := &d2.UniqueItem{
 		IndexID:    maxIndex + 1,
@@ -174,16 +444,12 @@ func (h *AdminHandler) createUniqueItem(c *fiber.Ctx) error {
 		item.Properties[i].HasRange = item.Properties[i].Min != item.Properties[i].Max
 	}
 
-	if err := h.repo.UpsertUniqueItem(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create unique item",
-			Code:    500,
-		})
+	if err := h.repo.UpsertUniqueItem(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create unique item")
 	}
 
 	// Fetch the created item to return
-	created, err := h.repo.GetUniqueItemByName(c.Context(), req.Name)
+	created, err := h.repo.GetUniqueItemByName(c.UserContext(), req.Name)
 	if err != nil {
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Unique item created"})
 	}
@@ -194,11 +460,11 @@ func (h *AdminHandler) createUniqueItem(c *fiber.Ctx) error {
 func (h *AdminHandler) updateUniqueItem(c *fiber.Ctx, id int) error {
 	var req dto.CreateUniqueItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateUniqueItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	props := convertInputProperties(req.Properties)
@@ -216,15 +482,11 @@ func (h *AdminHandler) updateUniqueItem(c *fiber.Ctx, id int) error {
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := h.repo.UpdateUniqueItemFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update unique item",
-			Code:    500,
-		})
+	if err := h.repo.UpdateUniqueItemFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update unique item")
 	}
 
-	updated, err := h.repo.GetUniqueItem(c.Context(), id)
+	updated, err := h.repo.GetUniqueItem(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Unique item updated"})
 	}
@@ -237,22 +499,18 @@ func (h *AdminHandler) updateUniqueItem(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) createSetItem(c *fiber.Ctx) error {
 	var req dto.CreateSetItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateSetItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Name == "" || req.SetName == "" || req.BaseCode == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Name, setName, and baseCode are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Name, setName, and baseCode are required")
 	}
 
-	maxIndex, _ := h.repo.GetMaxIndexID(c.Context(), "set_items")
+	maxIndex, _ := h.repo.GetMaxIndexID(c.UserContext(), "set_items")
 
 	props := convertInputProperties(req.Properties)
 	for i := range props {
@@ -277,12 +535,8 @@ func (h *AdminHandler) createSetItem(c *fiber.Ctx) error {
 		ImageURL:        req.ImageURL,
 	}
 
-	if err := h.repo.UpsertSetItem(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create set item",
-			Code:    500,
-		})
+	if err := h.repo.UpsertSetItem(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create set item")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Set item created"})
@@ -291,11 +545,11 @@ func (h *AdminHandler) createSetItem(c *fiber.Ctx) error {
 func (h *AdminHandler) updateSetItem(c *fiber.Ctx, id int) error {
 	var req dto.CreateSetItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateSetItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	props := convertInputProperties(req.Properties)
@@ -320,15 +574,11 @@ func (h *AdminHandler) updateSetItem(c *fiber.Ctx, id int) error {
 		ImageURL:        req.ImageURL,
 	}
 
-	if err := h.repo.UpdateSetItemFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update set item",
-			Code:    500,
-		})
+	if err := h.repo.UpdateSetItemFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update set item")
 	}
 
-	updated, err := h.repo.GetSetItem(c.Context(), id)
+	updated, err := h.repo.GetSetItem(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Set item updated"})
 	}
@@ -341,19 +591,22 @@ func (h *AdminHandler) updateSetItem(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) createRuneword(c *fiber.Ctx) error {
 	var req dto.CreateRunewordRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateRunewordRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
+	}
+	errs, err := h.validateRunewordRefs(c.UserContext(), &req)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to validate runeword")
+	}
+	if len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Name == "" || req.DisplayName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Name and displayName are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Name and displayName are required")
 	}
 
 	props := convertInputProperties(req.Properties)
@@ -373,25 +626,37 @@ func (h *AdminHandler) createRuneword(c *fiber.Ctx) error {
 		ImageURL:       req.ImageURL,
 	}
 
-	if err := h.repo.UpsertRuneword(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create runeword",
-			Code:    500,
-		})
+	if err := h.repo.UpsertRuneword(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create runeword")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Runeword created"})
+	id, err := h.repo.GetRunewordIDByName(c.UserContext(), item.Name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to load created runeword")
+	}
+	bases, err := h.repo.ComputeBasesForRuneword(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Runeword created but computing its bases failed")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Runeword created", "id": id, "bases": bases})
 }
 
 func (h *AdminHandler) updateRuneword(c *fiber.Ctx, id int) error {
 	var req dto.CreateRunewordRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateRunewordRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
+	}
+	errs, err := h.validateRunewordRefs(c.UserContext(), &req)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to validate runeword")
+	}
+	if len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	props := convertInputProperties(req.Properties)
@@ -410,15 +675,15 @@ func (h *AdminHandler) updateRuneword(c *fiber.Ctx, id int) error {
 		ImageURL:       req.ImageURL,
 	}
 
-	if err := h.repo.UpdateRunewordFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update runeword",
-			Code:    500,
-		})
+	if err := h.repo.UpdateRunewordFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update runeword")
+	}
+	// Runes and valid item types decide the bases
+	if _, err := h.repo.ComputeBasesForRuneword(c.UserContext(), id); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Runeword updated but computing its bases failed")
 	}
 
-	updated, err := h.repo.GetRuneword(c.Context(), id)
+	updated, err := h.repo.GetRuneword(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Runeword updated"})
 	}
@@ -426,24 +691,76 @@ func (h *AdminHandler) updateRuneword(c *fiber.Ctx, id int) error {
 	return c.JSON(updated)
 }
 
+// CreateRuneword creates a custom runeword (e.g. a new ladder season's) and
+// computes its valid bases, without a full re-import
+// POST /admin/d2/runewords
+func (h *AdminHandler) CreateRuneword(c *fiber.Ctx) error {
+	defer h.invalidateItemCache(c, cache.D2ItemDetailsPattern())
+	return h.createRuneword(c)
+}
+
+// DeleteRuneword deletes a runeword and its base mappings
+// DELETE /admin/d2/runewords/:id
+func (h *AdminHandler) DeleteRuneword(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
+	}
+
+	if err := h.repo.DeleteRuneword(c.UserContext(), id); err != nil {
+		if errors.Is(err, d2.ErrRunewordNotFound) {
+			return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Runeword not found")
+		}
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to delete runeword")
+	}
+
+	h.invalidateItemCache(c, cache.D2ItemDetailsPattern())
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// validateRunewordRefs checks that a runeword's rune codes and valid item
+// types exist in the catalog. The error is for failed lookups only.
+func (h *AdminHandler) validateRunewordRefs(ctx context.Context, req *dto.CreateRunewordRequest) (fieldErrors, error) {
+	var errs fieldErrors
+	if len(req.Runes) == 0 {
+		errs.add("runes", "must have at least one rune")
+	}
+	for i, code := range req.Runes {
+		exists, err := h.repo.RuneExists(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			errs.add(fmt.Sprintf("runes[%d]", i), "unknown rune code %q", code)
+		}
+	}
+
+	if len(req.ValidItemTypes) > 0 {
+		unknown, err := h.repo.UnknownTypeTags(ctx, req.ValidItemTypes)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range unknown {
+			errs.add("validItemTypes", "no base item has type %q", tag)
+		}
+	}
+	return errs, nil
+}
+
 // Rune CRUD
 
 func (h *AdminHandler) createRune(c *fiber.Ctx) error {
 	var req dto.CreateRuneRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateRuneRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Code == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Code and name are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Code and name are required")
 	}
 
 	item := &d2.Rune{
@@ -457,12 +774,8 @@ func (h *AdminHandler) createRune(c *fiber.Ctx) error {
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := h.repo.UpsertRune(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create rune",
-			Code:    500,
-		})
+	if err := h.repo.UpsertRune(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create rune")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Rune created"})
@@ -471,11 +784,11 @@ func (h *AdminHandler) createRune(c *fiber.Ctx) error {
 func (h *AdminHandler) updateRune(c *fiber.Ctx, id int) error {
 	var req dto.CreateRuneRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateRuneRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	item := &d2.Rune{
@@ -489,15 +802,11 @@ func (h *AdminHandler) updateRune(c *fiber.Ctx, id int) error {
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := h.repo.UpdateRuneFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update rune",
-			Code:    500,
-		})
+	if err := h.repo.UpdateRuneFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update rune")
 	}
 
-	updated, err := h.repo.GetRune(c.Context(), id)
+	updated, err := h.repo.GetRune(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Rune updated"})
 	}
@@ -510,19 +819,15 @@ func (h *AdminHandler) updateRune(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) createGem(c *fiber.Ctx) error {
 	var req dto.CreateGemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateGemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Code == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Code and name are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Code and name are required")
 	}
 
 	item := &d2.Gem{
@@ -530,18 +835,15 @@ func (h *AdminHandler) createGem(c *fiber.Ctx) error {
 		Name:       req.Name,
 		GemType:    req.GemType,
 		Quality:    req.Quality,
+		IsSkull:    d2.IsSkull(req.GemType),
 		WeaponMods: convertInputProperties(req.WeaponMods),
 		HelmMods:   convertInputProperties(req.ArmorMods),
 		ShieldMods: convertInputProperties(req.ShieldMods),
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := h.repo.UpsertGem(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create gem",
-			Code:    500,
-		})
+	if err := h.repo.UpsertGem(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create gem")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Gem created"})
@@ -550,11 +852,11 @@ func (h *AdminHandler) createGem(c *fiber.Ctx) error {
 func (h *AdminHandler) updateGem(c *fiber.Ctx, id int) error {
 	var req dto.CreateGemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateGemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	item := &d2.Gem{
@@ -562,21 +864,18 @@ func (h *AdminHandler) updateGem(c *fiber.Ctx, id int) error {
 		Name:       req.Name,
 		GemType:    req.GemType,
 		Quality:    req.Quality,
+		IsSkull:    d2.IsSkull(req.GemType),
 		WeaponMods: convertInputProperties(req.WeaponMods),
 		HelmMods:   convertInputProperties(req.ArmorMods),
 		ShieldMods: convertInputProperties(req.ShieldMods),
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := h.repo.UpdateGemFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update gem",
-			Code:    500,
-		})
+	if err := h.repo.UpdateGemFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update gem")
 	}
 
-	updated, err := h.repo.GetGem(c.Context(), id)
+	updated, err := h.repo.GetGem(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Gem updated"})
 	}
@@ -589,19 +888,15 @@ func (h *AdminHandler) updateGem(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) createBaseItem(c *fiber.Ctx) error {
 	var req dto.CreateBaseItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateBaseItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	if req.Code == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Code and name are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Code and name are required")
 	}
 
 	item := &d2.ItemBase{
@@ -625,12 +920,8 @@ func (h *AdminHandler) createBaseItem(c *fiber.Ctx) error {
 		Spawnable:     true,
 	}
 
-	if err := h.repo.UpsertItemBase(c.Context(), item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create base item",
-			Code:    500,
-		})
+	if err := h.repo.UpsertItemBase(c.UserContext(), item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create base item")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Base item created"})
@@ -639,11 +930,11 @@ func (h *AdminHandler) createBaseItem(c *fiber.Ctx) error {
 func (h *AdminHandler) updateBaseItem(c *fiber.Ctx, id int) error {
 	var req dto.CreateBaseItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	if errs := validateBaseItemRequest(&req); len(errs) > 0 {
+		return respondValidationErrors(c, errs)
 	}
 
 	item := &d2.ItemBase{
@@ -666,15 +957,11 @@ func (h *AdminHandler) updateBaseItem(c *fiber.Ctx, id int) error {
 		ImageURL:      req.ImageURL,
 	}
 
-	if err := h.repo.UpdateItemBaseFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update base item",
-			Code:    500,
-		})
+	if err := h.repo.UpdateItemBaseFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update base item")
 	}
 
-	updated, err := h.repo.GetItemBase(c.Context(), id)
+	updated, err := h.repo.GetItemBase(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Base item updated"})
 	}
@@ -687,19 +974,11 @@ func (h *AdminHandler) updateBaseItem(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) createQuestItem(c *fiber.Ctx) error {
 	var req dto.CreateQuestItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
 	}
 
 	if req.Code == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Code and name are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Code and name are required")
 	}
 
 	item := &d2.ItemBase{
@@ -710,16 +989,12 @@ func (h *AdminHandler) createQuestItem(c *fiber.Ctx) error {
 		QuestItem:   true,
 	}
 
-	id, err := h.repo.CreateQuestItem(c.Context(), item)
+	id, err := h.repo.CreateQuestItem(c.UserContext(), item)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create quest item",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create quest item")
 	}
 
-	created, err := h.repo.GetItemBase(c.Context(), id)
+	created, err := h.repo.GetItemBase(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Quest item created", "id": id})
 	}
@@ -738,21 +1013,13 @@ func (h *AdminHandler) createQuestItem(c *fiber.Ctx) error {
 func (h *AdminHandler) updateQuestItem(c *fiber.Ctx, id int) error {
 	var req dto.CreateQuestItemRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
 	}
 
 	// Verify this is actually a quest item
-	existing, err := h.repo.GetItemBase(c.Context(), id)
+	existing, err := h.repo.GetItemBase(c.UserContext(), id)
 	if err != nil || !existing.QuestItem {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Quest item not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Quest item not found")
 	}
 
 	item := &d2.ItemBase{
@@ -764,15 +1031,11 @@ func (h *AdminHandler) updateQuestItem(c *fiber.Ctx, id int) error {
 		ImageURL:    req.ImageURL,
 	}
 
-	if err := h.repo.UpdateItemBaseFields(c.Context(), id, item); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update quest item",
-			Code:    500,
-		})
+	if err := h.repo.UpdateItemBaseFields(c.UserContext(), id, item); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update quest item")
 	}
 
-	updated, err := h.repo.GetItemBase(c.Context(), id)
+	updated, err := h.repo.GetItemBase(c.UserContext(), id)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Quest item updated"})
 	}
@@ -795,19 +1058,11 @@ func (h *AdminHandler) updateQuestItem(c *fiber.Ctx, id int) error {
 func (h *AdminHandler) CreateClass(c *fiber.Ctx) error {
 	var req dto.CreateClassRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
 	}
 
 	if req.ID == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "ID and name are required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "ID and name are required")
 	}
 
 	skillTrees := make([]d2.SkillTree, 0, len(req.SkillTrees))
@@ -825,15 +1080,12 @@ func (h *AdminHandler) CreateClass(c *fiber.Ctx) error {
 		SkillTrees:  skillTrees,
 	}
 
-	if err := h.repo.UpsertClass(c.Context(), cls); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create class",
-			Code:    500,
-		})
+	if err := h.repo.UpsertClass(c.UserContext(), cls); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to create class")
 	}
+	h.reloadClassSkills(c.UserContext())
 
-	created, err := h.repo.GetClass(c.Context(), req.ID)
+	created, err := h.repo.GetClass(c.UserContext(), req.ID)
 	if err != nil {
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Class created"})
 	}
@@ -841,6 +1093,12 @@ func (h *AdminHandler) CreateClass(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(convertClassToDTO(created))
 }
 
+// reloadClassSkills refreshes the skill names affix text resolves skill ids
+// to. On failure the previously loaded names stay in use.
+func (h *AdminHandler) reloadClassSkills(ctx context.Context) {
+	h.repo.LoadSkillNames(ctx, true)
+}
+
 // UpdateClass handles updating an existing class
 // PUT /admin/d2/classes/:classId
 func (h *AdminHandler) UpdateClass(c *fiber.Ctx) error {
@@ -848,21 +1106,13 @@ func (h *AdminHandler) UpdateClass(c *fiber.Ctx) error {
 
 	var req dto.UpdateClassRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid request body",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
 	}
 
 	// Verify class exists
-	_, err := h.repo.GetClass(c.Context(), classID)
+	_, err := h.repo.GetClass(c.UserContext(), classID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Class not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Class not found")
 	}
 
 	skillTrees := make([]d2.SkillTree, 0, len(req.SkillTrees))
@@ -880,15 +1130,12 @@ func (h *AdminHandler) UpdateClass(c *fiber.Ctx) error {
 		SkillTrees:  skillTrees,
 	}
 
-	if err := h.repo.UpsertClass(c.Context(), cls); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to update class",
-			Code:    500,
-		})
+	if err := h.repo.UpsertClass(c.UserContext(), cls); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to update class")
 	}
+	h.reloadClassSkills(c.UserContext())
 
-	updated, err := h.repo.GetClass(c.Context(), classID)
+	updated, err := h.repo.GetClass(c.UserContext(), classID)
 	if err != nil {
 		return c.JSON(fiber.Map{"message": "Class updated"})
 	}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+func TestDeleteRuneword(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         *dbtest.Fake
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "deleted",
+			db:         dbtest.NewFake().On("DELETE FROM d2.runewords WHERE id = $1", []interface{}{}),
+			wantStatus: fiber.StatusNoContent,
+		},
+		{
+			name:       "missing runeword",
+			db:         dbtest.NewFake(),
+			wantStatus: fiber.StatusNotFound,
+			wantError:  dto.ErrCodeNotFound,
+		},
+		{
+			name:       "database error",
+			db:         dbtest.NewFake().OnError("DELETE FROM d2.runeword_bases", errors.New("connection reset")),
+			wantStatus: fiber.StatusInternalServerError,
+			wantError:  dto.ErrCodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdminHandler(d2.NewRepository(tt.db), nil)
+			app := fiber.New()
+			app.Delete("/runewords/:id", h.DeleteRuneword)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/runewords/7", nil))
+			if err != nil {
+				t.Fatalf("DELETE: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantError == "" {
+				return
+			}
+			var body dto.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if body.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantError)
+			}
+		})
+	}
+}
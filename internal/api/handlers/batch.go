@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+)
+
+// maxBatchItems caps how many items one batch lookup may request
+const maxBatchItems = 100
+
+// batchItemTypes are the item types accepted by a batch lookup
+var batchItemTypes = map[string]bool{
+	"unique": true, "set": true, "runeword": true, "rune": true,
+	"gem": true, "base": true, "quest": true,
+}
+
+// batchDetails holds loaded item details by type, then ID
+type batchDetails map[string]map[int]*dto.UnifiedItemDetail
+
+func (b batchDetails) put(itemType string, id int, detail *dto.UnifiedItemDetail) {
+	if b[itemType] == nil {
+		b[itemType] = make(map[int]*dto.UnifiedItemDetail)
+	}
+	b[itemType][id] = detail
+}
+
+// GetItemsBatch returns the details of several items in request order.
+// Items are fetched per type with one query each, so the number of queries
+// doesn't grow with the batch. Items that don't exist come back as null.
+// POST /api/d2/items/batch
+func (h *ItemHandler) GetItemsBatch(c *fiber.Ctx) error {
+	var req dto.BatchItemsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+	if len(req.Items) == 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "At least one item is required")
+	}
+	if len(req.Items) > maxBatchItems {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, fmt.Sprintf("A batch can request at most %d items", maxBatchItems))
+	}
+
+	idsByType := make(map[string][]int)
+	for i, ref := range req.Items {
+		itemType := strings.ToLower(ref.Type)
+		if !batchItemTypes[itemType] {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, fmt.Sprintf("items[%d]: invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest", i))
+		}
+		req.Items[i].Type = itemType
+		idsByType[itemType] = append(idsByType[itemType], ref.ID)
+	}
+
+	details, err := h.loadBatchDetails(c.UserContext(), idsByType)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get items")
+	}
+
+	results := make([]*dto.UnifiedItemDetail, len(req.Items))
+	for i, ref := range req.Items {
+		results[i] = details[ref.Type][ref.ID]
+	}
+	return c.JSON(results)
+}
+
+// loadBatchDetails fetches every requested item with one query per item type,
+// plus one query per kind of related data (bases, runes, item types)
+func (h *ItemHandler) loadBatchDetails(ctx context.Context, idsByType map[string][]int) (batchDetails, error) {
+	details := make(batchDetails)
+
+	uniques, err := h.repo.GetUniqueItemsByIDs(ctx, idsByType["unique"])
+	if err != nil {
+		return nil, err
+	}
+	sets, err := h.repo.GetSetItemsByIDs(ctx, idsByType["set"])
+	if err != nil {
+		return nil, err
+	}
+
+	// Uniques and set items share one base lookup
+	baseCodes := make([]string, 0, len(uniques)+len(sets))
+	for _, item := range uniques {
+		baseCodes = append(baseCodes, item.BaseCode)
+	}
+	for _, item := range sets {
+		baseCodes = append(baseCodes, item.BaseCode)
+	}
+	// A failed lookup leaves bases nil, same as a missing base per item
+	bases, _ := h.repo.GetItemBasesByCodes(ctx, baseCodes)
+	itemTypes := h.loadItemTypes(ctx, bases)
+	var setNames map[string][]string
+	if len(sets) > 0 {
+		setNames, _ = h.repo.GetSetItemNames(ctx)
+	}
+
+	for id, item := range uniques {
+		details.put("unique", id, &dto.UnifiedItemDetail{
+			ItemType: "unique",
+			Unique:   h.convertUniqueToDTO(item, bases[item.BaseCode], itemTypeOf(itemTypes, bases[item.BaseCode])),
+		})
+	}
+	for id, item := range sets {
+		detail := h.convertSetItemToDTO(item, bases[item.BaseCode], itemTypeOf(itemTypes, bases[item.BaseCode]))
+		setSiblings(detail, setNames)
+		details.put("set", id, &dto.UnifiedItemDetail{
+			ItemType: "set",
+			SetItem:  detail,
+		})
+	}
+
+	if err := h.loadBatchRunewords(ctx, idsByType["runeword"], details); err != nil {
+		return nil, err
+	}
+
+	runes, err := h.repo.GetRunesByIDs(ctx, idsByType["rune"])
+	if err != nil {
+		return nil, err
+	}
+	for id, item := range runes {
+		details.put("rune", id, &dto.UnifiedItemDetail{
+			ItemType: "rune",
+			Rune:     h.convertRuneToDTO(item),
+		})
+	}
+
+	gems, err := h.repo.GetGemsByIDs(ctx, idsByType["gem"])
+	if err != nil {
+		return nil, err
+	}
+	for id, item := range gems {
+		details.put("gem", id, &dto.UnifiedItemDetail{
+			ItemType: "gem",
+			Gem:      h.convertGemToDTO(item),
+		})
+	}
+
+	if err := h.loadBatchBases(ctx, idsByType["base"], idsByType["quest"], details); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// loadBatchRunewords adds runeword details, fetching valid bases, rune info
+// and item type names for all runewords at once
+func (h *ItemHandler) loadBatchRunewords(ctx context.Context, ids []int, details batchDetails) error {
+	runewords, err := h.repo.GetRunewordsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(runewords) == 0 {
+		return nil
+	}
+
+	runewordIDs := make([]int, 0, len(runewords))
+	runeCodes := make([]string, 0)
+	typeCodes := make([]string, 0)
+	for id, item := range runewords {
+		runewordIDs = append(runewordIDs, id)
+		runeCodes = append(runeCodes, item.Runes...)
+		typeCodes = append(typeCodes, item.ValidItemTypes...)
+	}
+
+	// Failed lookups degrade to codes and no bases, as in the detail endpoint
+	basesByRuneword, _ := h.repo.GetBasesForRunewords(ctx, runewordIDs)
+	runeInfoMap, _ := h.repo.GetRunesByCodes(ctx, runeCodes)
+	typeInfoMap, _ := h.repo.GetItemTypesByCodes(ctx, typeCodes)
+
+	for id, item := range runewords {
+		details.put("runeword", id, &dto.UnifiedItemDetail{
+			ItemType: "runeword",
+			Runeword: h.convertRunewordToDTO(item, basesByRuneword[id], runeInfoMap, typeInfoMap),
+		})
+	}
+	return nil
+}
+
+// loadBatchBases adds base and quest item details. Both live in item_bases,
+// so they share one query; quest lookups only match quest items.
+func (h *ItemHandler) loadBatchBases(ctx context.Context, baseIDs, questIDs []int, details batchDetails) error {
+	ids := make([]int, 0, len(baseIDs)+len(questIDs))
+	ids = append(ids, baseIDs...)
+	ids = append(ids, questIDs...)
+
+	items, err := h.repo.GetItemBasesByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	typeCodes := make([]string, 0, len(baseIDs))
+	for _, id := range baseIDs {
+		if item, ok := items[id]; ok {
+			typeCodes = append(typeCodes, item.ItemType)
+		}
+	}
+	itemTypes, _ := h.repo.GetFullItemTypesByCodes(ctx, typeCodes)
+
+	for _, id := range baseIDs {
+		if item, ok := items[id]; ok {
+			details.put("base", id, &dto.UnifiedItemDetail{
+				ItemType: "base",
+				Base:     h.convertBaseToDTO(item, itemTypes[item.ItemType]),
+			})
+		}
+	}
+	for _, id := range questIDs {
+		if item, ok := items[id]; ok && item.QuestItem {
+			details.put("quest", id, &dto.UnifiedItemDetail{
+				ItemType: "quest",
+				Quest:    h.convertQuestToDTO(item),
+			})
+		}
+	}
+	return nil
+}
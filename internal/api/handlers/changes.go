@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// Page sizes for the changes feed
+const (
+	defaultChangesLimit = 500
+	maxChangesLimit     = 1000
+)
+
+// GetChanges lists catalog items updated after since, oldest first, so
+// downstream caches can pull deltas instead of full snapshots. Deleted items
+// are not reported.
+// GET /api/d2/changes?since=<rfc3339>&after=<type:id>&limit=500
+func (h *ItemHandler) GetChanges(c *fiber.Ctx) error {
+	since, err := time.Parse(time.RFC3339Nano, c.Query("since"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameter 'since' must be an RFC 3339 timestamp")
+	}
+
+	var after *d2.ChangeCursor
+	if raw := c.Query("after"); raw != "" {
+		itemType, rawID, ok := strings.Cut(raw, ":")
+		id, err := strconv.Atoi(rawID)
+		if !ok || itemType == "" || err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameter 'after' must be a cursor like unique:5")
+		}
+		after = &d2.ChangeCursor{Type: itemType, ID: id}
+	}
+
+	limit := c.QueryInt("limit", defaultChangesLimit)
+	if limit < 1 || limit > maxChangesLimit {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameter 'limit' must be between 1 and "+strconv.Itoa(maxChangesLimit))
+	}
+
+	// One extra row tells whether another page follows
+	entries, err := h.repo.GetChangedSince(c.UserContext(), since, after, limit+1)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get changes")
+	}
+
+	result := dto.ChangesResponse{
+		Changes:   make([]dto.ChangeEntry, 0, min(len(entries), limit)),
+		NextSince: c.Query("since"),
+		HasMore:   len(entries) > limit,
+	}
+	if after != nil {
+		result.NextAfter = c.Query("after")
+	}
+	if result.HasMore {
+		entries = entries[:limit]
+	}
+	for _, e := range entries {
+		result.Changes = append(result.Changes, dto.ChangeEntry{
+			Type:      e.Type,
+			ID:        e.ID,
+			Name:      e.Name,
+			UpdatedAt: e.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	if n := len(result.Changes); n > 0 {
+		last := result.Changes[n-1]
+		result.NextSince = last.UpdatedAt
+		result.NextAfter = last.Type + ":" + strconv.Itoa(last.ID)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+)
+
+// CompareItems returns two item details and a diff of their translated stats
+// GET /api/d2/items/compare?a=unique:5&b=runeword:12
+func (h *ItemHandler) CompareItems(c *fiber.Ctx) error {
+	a, errResp := h.loadCompareItem(c, "a")
+	if errResp != nil {
+		return respondError(c, errResp.Code, errResp.Error, errResp.Message)
+	}
+	b, errResp := h.loadCompareItem(c, "b")
+	if errResp != nil {
+		return respondError(c, errResp.Code, errResp.Error, errResp.Message)
+	}
+
+	return c.JSON(dto.CompareItemsResponse{
+		A:          *a,
+		B:          *b,
+		Comparison: CompareAffixes(detailAffixes(a), detailAffixes(b)),
+	})
+}
+
+// loadCompareItem loads the item named by a "type:id" query param
+func (h *ItemHandler) loadCompareItem(c *fiber.Ctx, param string) (*dto.UnifiedItemDetail, *dto.ErrorResponse) {
+	itemType, rawID, ok := strings.Cut(c.Query(param), ":")
+	itemType = strings.ToLower(strings.TrimSpace(itemType))
+	id, err := strconv.Atoi(strings.TrimSpace(rawID))
+	if !ok || err != nil {
+		return nil, &dto.ErrorResponse{
+			Error:   dto.ErrCodeBadRequest,
+			Message: "Query param '" + param + "' must be an item reference like unique:5",
+			Code:    400,
+		}
+	}
+
+	// Only these details carry translated affixes
+	var load itemDetailLoader
+	switch itemType {
+	case "unique":
+		load = h.loadUniqueDetail
+	case "set":
+		load = h.loadSetItemDetail
+	case "runeword":
+		load = h.loadRunewordDetail
+	}
+	if load == nil {
+		return nil, &dto.ErrorResponse{
+			Error:   dto.ErrCodeBadRequest,
+			Message: "Query param '" + param + "': invalid item type. Must be one of: unique, set, runeword",
+			Code:    400,
+		}
+	}
+
+	detail, err := h.cachedItemDetail(c.UserContext(), itemType, id, load)
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Error:   dto.ErrCodeNotFound,
+			Message: "Item '" + param + "' not found",
+			Code:    404,
+		}
+	}
+	return detail, nil
+}
+
+// detailAffixes returns the always-active affixes of an item detail. Set
+// bonuses are left out since they depend on the other pieces worn.
+func detailAffixes(detail *dto.UnifiedItemDetail) []dto.ItemAffix {
+	switch {
+	case detail.Unique != nil:
+		return detail.Unique.Affixes
+	case detail.SetItem != nil:
+		return detail.SetItem.Affixes
+	case detail.Runeword != nil:
+		return detail.Runeword.Affixes
+	}
+	return nil
+}
+
+// CompareAffixes diffs two affix lists by affix code, so "fcr" matches "fcr"
+// whatever its display text. When a code appears several times on an item
+// (e.g. one "skill" affix per skill) the occurrences pair up in order and
+// the leftovers count as present on one side only.
+func CompareAffixes(a, b []dto.ItemAffix) dto.AffixComparison {
+	result := dto.AffixComparison{
+		OnlyA:  []dto.ItemAffix{},
+		OnlyB:  []dto.ItemAffix{},
+		Common: []dto.AffixDelta{},
+	}
+
+	byCode := make(map[string][]dto.ItemAffix)
+	for _, affix := range b {
+		code := strings.ToLower(affix.Code)
+		byCode[code] = append(byCode[code], affix)
+	}
+
+	paired := make(map[string]int)
+	for _, affixA := range a {
+		code := strings.ToLower(affixA.Code)
+		n := paired[code]
+		if n >= len(byCode[code]) {
+			result.OnlyA = append(result.OnlyA, affixA)
+			continue
+		}
+		affixB := byCode[code][n]
+		paired[code] = n + 1
+		result.Common = append(result.Common, dto.AffixDelta{
+			Code:     code,
+			A:        affixA,
+			B:        affixB,
+			MinDelta: valueDelta(affixA.MinValue, affixB.MinValue),
+			MaxDelta: valueDelta(affixA.MaxValue, affixB.MaxValue),
+		})
+	}
+
+	// Occurrences past the paired ones are only on B, kept in B's order
+	seen := make(map[string]int)
+	for _, affix := range b {
+		code := strings.ToLower(affix.Code)
+		if seen[code] >= paired[code] {
+			result.OnlyB = append(result.OnlyB, affix)
+		}
+		seen[code]++
+	}
+
+	return result
+}
+
+// valueDelta returns b minus a, or nil when either value is missing
+func valueDelta(a, b *int) *int {
+	if a == nil || b == nil {
+		return nil
+	}
+	d := *b - *a
+	return &d
+}
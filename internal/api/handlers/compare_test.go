@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+func TestCompareAffixes(t *testing.T) {
+	value := func(v int) *int { return &v }
+	affix := func(code string, min, max int) dto.ItemAffix {
+		return dto.ItemAffix{Code: code, MinValue: value(min), MaxValue: value(max)}
+	}
+	skillA := dto.ItemAffix{Code: "skill-teleport", Name: "+1 To Teleport"}
+	skillB := dto.ItemAffix{Code: "SKILL-TELEPORT", Name: "+1 em Teleport"}
+
+	a := []dto.ItemAffix{affix("fcr", 10, 20), affix("res-all", 5, 5), skillA, affix("str", 10, 10), affix("str", 5, 5)}
+	b := []dto.ItemAffix{affix("str", 15, 15), affix("fcr", 35, 35), skillB, affix("mag%", 25, 50)}
+
+	got := CompareAffixes(a, b)
+
+	codes := func(affixes []dto.ItemAffix) []string {
+		out := make([]string, 0, len(affixes))
+		for _, a := range affixes {
+			out = append(out, a.Code)
+		}
+		return out
+	}
+	if only := codes(got.OnlyA); len(only) != 2 || only[0] != "res-all" || only[1] != "str" || *got.OnlyA[1].MinValue != 5 {
+		t.Errorf("onlyA = %v, want res-all and the second str", only)
+	}
+	if only := codes(got.OnlyB); len(only) != 1 || only[0] != "mag%" {
+		t.Errorf("onlyB = %v, want mag%%", only)
+	}
+
+	want := []struct {
+		code               string
+		minDelta, maxDelta *int
+	}{
+		{"fcr", value(25), value(15)},
+		{"skill-teleport", nil, nil},
+		{"str", value(5), value(5)},
+	}
+	if len(got.Common) != len(want) {
+		t.Fatalf("common = %+v, want %d affixes", got.Common, len(want))
+	}
+	for i, w := range want {
+		c := got.Common[i]
+		if c.Code != w.code || !sameDelta(c.MinDelta, w.minDelta) || !sameDelta(c.MaxDelta, w.maxDelta) {
+			t.Errorf("common[%d] = %s %v/%v, want %s %v/%v", i, c.Code, deref(c.MinDelta), deref(c.MaxDelta), w.code, deref(w.minDelta), deref(w.maxDelta))
+		}
+	}
+	if got.Common[1].A.Name != skillA.Name || got.Common[1].B.Name != skillB.Name {
+		t.Errorf("skill pair = %+v, want both sides kept", got.Common[1])
+	}
+
+	empty := CompareAffixes(nil, nil)
+	if empty.OnlyA == nil || empty.OnlyB == nil || empty.Common == nil {
+		t.Errorf("empty comparison = %+v, want empty lists rather than null", empty)
+	}
+}
+
+func sameDelta(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func deref(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// compareDB serves unique 5 and runeword 12
+func compareDB() *dbtest.Fake {
+	unique := uniqueRow(5, "Harlequin Crest", "uap", "Shako")
+	unique[12] = dbtest.JSON([]d2.Property{
+		{Code: "fcr", Min: 10, Max: 20},
+		{Code: "res-all", Min: 5, Max: 5},
+	})
+	runeword := runewordRow(12, "Spirit", "r07", "r10", "r09", "r11")
+	runeword[10] = dbtest.JSON([]d2.Property{
+		{Code: "fcr", Min: 25, Max: 35},
+		{Code: "mana", Min: 89, Max: 112},
+	})
+	return dbtest.NewFake().
+		On("unique_items WHERE id = $1", unique).
+		On("runewords WHERE id = $1", runeword)
+}
+
+func TestCompareItems(t *testing.T) {
+	h := NewItemHandler(d2.NewRepository(compareDB()), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/items/compare", h.Localized((*ItemHandler).CompareItems))
+
+	var got dto.CompareItemsResponse
+	if resp := getJSON(t, app, "/items/compare?a=unique:5&b=runeword:12", &got); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got.A.Unique == nil || got.A.Unique.Name != "Harlequin Crest" || got.B.Runeword == nil || got.B.Runeword.DisplayName != "Spirit" {
+		t.Fatalf("a = %+v, b = %+v; want Harlequin Crest and Spirit", got.A.Unique, got.B.Runeword)
+	}
+
+	cmp := got.Comparison
+	if len(cmp.OnlyA) != 1 || cmp.OnlyA[0].Code != "res-all" {
+		t.Errorf("onlyA = %+v, want res-all", cmp.OnlyA)
+	}
+	if len(cmp.OnlyB) != 1 || cmp.OnlyB[0].Code != "mana" {
+		t.Errorf("onlyB = %+v, want mana", cmp.OnlyB)
+	}
+	if len(cmp.Common) != 1 || cmp.Common[0].Code != "fcr" || deref(cmp.Common[0].MinDelta) != 15 || deref(cmp.Common[0].MaxDelta) != 15 {
+		t.Errorf("common = %+v, want fcr up 15/15", cmp.Common)
+	}
+}
+
+func TestCompareItemsErrors(t *testing.T) {
+	// Stubs match on SQL rather than ids, so a missing item is a missing stub
+	onlyUnique := dbtest.NewFake().On("unique_items WHERE id = $1", uniqueRow(5, "Harlequin Crest", "uap", "Shako"))
+
+	tests := []struct {
+		name string
+		db   *dbtest.Fake
+		url  string
+		want int
+	}{
+		{"unknown unique", dbtest.NewFake(), "/items/compare?a=unique:99&b=runeword:12", fiber.StatusNotFound},
+		{"unknown runeword", onlyUnique, "/items/compare?a=unique:5&b=runeword:99", fiber.StatusNotFound},
+		{"missing param", compareDB(), "/items/compare?a=unique:5", fiber.StatusBadRequest},
+		{"non-numeric id", compareDB(), "/items/compare?a=unique:abc&b=runeword:12", fiber.StatusBadRequest},
+		{"type without affixes", compareDB(), "/items/compare?a=rune:30&b=runeword:12", fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewItemHandler(d2.NewRepository(tt.db), ItemHandlerConfig{})
+			app := fiber.New()
+			app.Get("/items/compare", h.Localized((*ItemHandler).CompareItems))
+
+			var got dto.ErrorResponse
+			resp := getJSON(t, app, tt.url, &got)
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d (%s)", resp.StatusCode, tt.want, got.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// csvAffixSeparator joins translated affixes into a single CSV cell
+const csvAffixSeparator = "; "
+
+// ExportUniquesCSV returns every unique as a spreadsheet-friendly CSV
+// GET /api/d2/export/uniques.csv
+func (h *ItemHandler) ExportUniquesCSV(c *fiber.Ctx) error {
+	return h.streamCSV(c, "uniques", "uniques.csv",
+		[]string{"name", "base", "base_code", "level_req", "ladder_only", "affixes"},
+		func(ctx context.Context, w *csv.Writer) error {
+			return h.repo.StreamUniqueItems(ctx, func(item *d2.UniqueItem) error {
+				return w.Write([]string{
+					item.Name,
+					csvBaseName(item.BaseName, item.BaseCode),
+					item.BaseCode,
+					strconv.Itoa(item.LevelReq),
+					strconv.FormatBool(item.LadderOnly),
+					h.csvAffixes(item.Properties),
+				})
+			})
+		})
+}
+
+// ExportSetsCSV returns every set item as a spreadsheet-friendly CSV
+// GET /api/d2/export/sets.csv
+func (h *ItemHandler) ExportSetsCSV(c *fiber.Ctx) error {
+	return h.streamCSV(c, "sets", "sets.csv",
+		[]string{"name", "set", "base", "base_code", "level_req", "affixes", "bonus_affixes"},
+		func(ctx context.Context, w *csv.Writer) error {
+			return h.repo.StreamSetItems(ctx, func(item *d2.SetItem) error {
+				return w.Write([]string{
+					item.Name,
+					item.SetName,
+					csvBaseName(item.BaseName, item.BaseCode),
+					item.BaseCode,
+					strconv.Itoa(item.LevelReq),
+					h.csvAffixes(item.Properties),
+					h.csvAffixes(item.BonusProperties),
+				})
+			})
+		})
+}
+
+// ExportRunewordsCSV returns every runeword as a spreadsheet-friendly CSV.
+// Runes are listed in socketing order.
+// GET /api/d2/export/runewords.csv
+func (h *ItemHandler) ExportRunewordsCSV(c *fiber.Ctx) error {
+	return h.streamCSV(c, "runewords", "runewords.csv",
+		[]string{"name", "runes", "sockets", "item_types", "ladder_only", "affixes"},
+		func(ctx context.Context, w *csv.Writer) error {
+			names := newCSVNames(h.repo)
+			return h.repo.StreamRunewords(ctx, func(item *d2.Runeword) error {
+				name := item.DisplayName
+				if name == "" {
+					name = item.Name
+				}
+				return w.Write([]string{
+					name,
+					strings.Join(names.runes(ctx, item.Runes), " "),
+					strconv.Itoa(len(item.Runes)),
+					strings.Join(names.itemTypes(ctx, item.ValidItemTypes), csvAffixSeparator),
+					strconv.FormatBool(item.LadderOnly),
+					h.csvAffixes(item.Properties),
+				})
+			})
+		})
+}
+
+// streamCSV checks that section is readable, sets CSV download headers and
+// streams the header row followed by the rows write produces. A failure once
+// streaming has started cuts the file short, since the status is already sent.
+func (h *ItemHandler) streamCSV(c *fiber.Ctx, section, filename string, header []string, write func(ctx context.Context, w *csv.Writer) error) error {
+	if err := h.repo.CheckExportSections(c.UserContext(), []string{section}); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to export "+section)
+	}
+
+	// The stream writer runs after the fiber.Ctx has been released
+	ctx := c.UserContext()
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		w := csv.NewWriter(bw)
+		w.Write(header)
+		if err := write(ctx, w); err != nil {
+			fmt.Printf("CSV export of %s aborted: %v\n", section, err)
+		}
+		w.Flush()
+		bw.Flush()
+	})
+	return nil
+}
+
+// csvAffixes joins properties into one cell using the translated stat text
+func (h *ItemHandler) csvAffixes(props []d2.Property) string {
+	return strings.Join(h.translator.TranslateProperties(props), csvAffixSeparator)
+}
+
+// csvBaseName falls back to the base code when the base name wasn't resolved
+func csvBaseName(name, code string) string {
+	if name != "" {
+		return name
+	}
+	return code
+}
+
+// csvNames resolves rune and item type codes to display names during a
+// streamed export, querying only for codes it hasn't seen yet. Failed
+// lookups fall back to raw codes.
+type csvNames struct {
+	repo      *d2.Repository
+	runeNames map[string]string
+	typeNames map[string]string
+}
+
+func newCSVNames(repo *d2.Repository) *csvNames {
+	return &csvNames{repo: repo, runeNames: map[string]string{}, typeNames: map[string]string{}}
+}
+
+// runes returns rune names without the " Rune" suffix, in the order of codes
+func (n *csvNames) runes(ctx context.Context, codes []string) []string {
+	if missing := csvMissing(n.runeNames, codes); len(missing) > 0 {
+		infos, _ := n.repo.GetRunesByCodes(ctx, missing)
+		for _, code := range missing {
+			n.runeNames[code] = code
+			if info, ok := infos[code]; ok {
+				n.runeNames[code] = strings.TrimSuffix(info.Name, " Rune")
+			}
+		}
+	}
+	return csvLookup(n.runeNames, codes)
+}
+
+// itemTypes returns item type names in the order of codes
+func (n *csvNames) itemTypes(ctx context.Context, codes []string) []string {
+	if missing := csvMissing(n.typeNames, codes); len(missing) > 0 {
+		infos, _ := n.repo.GetItemTypesByCodes(ctx, missing)
+		for _, code := range missing {
+			n.typeNames[code] = code
+			if info, ok := infos[code]; ok {
+				n.typeNames[code] = info.Name
+			}
+		}
+	}
+	return csvLookup(n.typeNames, codes)
+}
+
+func csvMissing(names map[string]string, codes []string) []string {
+	var missing []string
+	for _, code := range codes {
+		if _, ok := names[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+	return missing
+}
+
+func csvLookup(names map[string]string, codes []string) []string {
+	out := make([]string, 0, len(codes))
+	for _, code := range codes {
+		out = append(out, names[code])
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// csvTestProps translate to "20% Chance Of Crushing Blow" and "Damage Reduced By 8%"
+var csvTestProps = dbtest.JSON([]d2.Property{{Code: "crush", Min: 20, Max: 20}, {Code: "red-dmg%", Min: 8, Max: 8}})
+
+const csvTestAffixes = "20% Chance Of Crushing Blow; Damage Reduced By 8%"
+
+// exportCSV serves one CSV export route over db and returns the response and
+// its parsed records
+func exportCSV(t *testing.T, db *dbtest.Fake, path string, route func(*ItemHandler, *fiber.Ctx) error) (*http.Response, [][]string) {
+	t.Helper()
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get(path, h.Localized(route))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		return resp, nil
+	}
+	records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	if err != nil {
+		t.Fatalf("%s is not valid CSV: %v\n%s", path, err, body)
+	}
+	return resp, records
+}
+
+func TestExportCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		db    *dbtest.Fake
+		path  string
+		route func(*ItemHandler, *fiber.Ctx) error
+		want  [][]string
+	}{
+		{
+			name: "uniques",
+			db: dbtest.NewFake().On("FROM d2.unique_items WHERE enabled = true", []interface{}{
+				1, 1, "The Gnasher", "hax", "Hand Axe", 7, 5, 1,
+				true, false, nil, nil,
+				csvTestProps, nil, nil, nil, nil,
+				0, 0, nil, nil,
+			}),
+			path:  "/export/uniques.csv",
+			route: (*ItemHandler).ExportUniquesCSV,
+			want: [][]string{
+				{"name", "base", "base_code", "level_req", "ladder_only", "affixes"},
+				{"The Gnasher", "Hand Axe", "hax", "5", "false", csvTestAffixes},
+			},
+		},
+		{
+			name: "sets",
+			db: dbtest.NewFake().On("FROM d2.set_items WHERE enabled IS NOT FALSE", []interface{}{
+				2, 2, "Civerb's Ward", "Civerb's Vestments", "lrg", nil, 13, 9, 1,
+				csvTestProps, dbtest.JSON([]d2.Property{{Code: "crush", Min: 5, Max: 5}}), nil, nil, nil, nil,
+				0, 0, nil, nil,
+			}),
+			path:  "/export/sets.csv",
+			route: (*ItemHandler).ExportSetsCSV,
+			want: [][]string{
+				{"name", "set", "base", "base_code", "level_req", "affixes", "bonus_affixes"},
+				{"Civerb's Ward", "Civerb's Vestments", "lrg", "lrg", "9", csvTestAffixes, "5% Chance Of Crushing Blow"},
+			},
+		},
+		{
+			name: "runewords",
+			db: dbtest.NewFake().
+				On("FROM d2.runewords WHERE complete = true", []interface{}{
+					3, "Runeword1", "Steel", true, false, nil, nil,
+					dbtest.JSON([]string{"swor", "axe"}), nil, dbtest.JSON([]string{"r13", "r12"}), csvTestProps, nil,
+					nil, nil,
+				}).
+				On("rune_number",
+					[]interface{}{13, "r13", "Tir Rune", 3, ""},
+					[]interface{}{12, "r12", "El Rune", 1, ""},
+				).
+				On("FROM d2.item_types", []interface{}{"swor", "Sword"}, []interface{}{"axe", "Axe"}),
+			path:  "/export/runewords.csv",
+			route: (*ItemHandler).ExportRunewordsCSV,
+			want: [][]string{
+				{"name", "runes", "sockets", "item_types", "ladder_only", "affixes"},
+				{"Steel", "Tir El", "2", "Sword; Axe", "false", csvTestAffixes},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, records := exportCSV(t, tt.db, tt.path, tt.route)
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(got, "text/csv") {
+				t.Errorf("Content-Type = %q, want text/csv", got)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("got %d records, want %d: %v", len(records), len(tt.want), records)
+			}
+			for i := range tt.want {
+				if strings.Join(records[i], "|") != strings.Join(tt.want[i], "|") {
+					t.Errorf("record %d = %q, want %q", i, records[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportCSVUnreadableTable(t *testing.T) {
+	db := dbtest.NewFake().OnError("SELECT 1 FROM d2.unique_items", errors.New("connection refused"))
+	resp, _ := exportCSV(t, db, "/export/uniques.csv", (*ItemHandler).ExportUniquesCSV)
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 before streaming", resp.StatusCode)
+	}
+}
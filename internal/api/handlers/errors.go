@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/middleware"
+)
+
+// respondError writes an error response with a machine-readable code (one of
+// the dto.ErrCode constants) and the request ID
+func respondError(c *fiber.Ctx, status int, code, message string) error {
+	return middleware.RespondError(c, status, code, message)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// runewordListApp serves a list guarded by notModified over a runewords table
+// whose version is updatedAt and rows
+func runewordListApp(updatedAt time.Time, rows int64) *fiber.App {
+	db := dbtest.NewFake().On("MAX(updated_at), COUNT(*) FROM d2.runewords", []interface{}{updatedAt, rows})
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/runewords", func(c *fiber.Ctx) error {
+		if h.notModified(c, "runewords") {
+			return nil
+		}
+		return c.SendString("[]")
+	})
+	return app
+}
+
+// listRunewords requests the list, sending etag as If-None-Match when set
+func listRunewords(t *testing.T, app *fiber.App, etag string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/runewords", nil)
+	if etag != "" {
+		req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("GET /runewords: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestNotModifiedETagChangesOnHardDelete(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	before := listRunewords(t, runewordListApp(updatedAt, 10), "")
+	etag := before.Header.Get(fiber.HeaderETag)
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	if resp := listRunewords(t, runewordListApp(updatedAt, 10), etag); resp.StatusCode != fiber.StatusNotModified {
+		t.Errorf("unchanged table: status = %d, want 304", resp.StatusCode)
+	}
+
+	// Deleting an older row leaves MAX(updated_at) where it was
+	after := listRunewords(t, runewordListApp(updatedAt, 9), etag)
+	if after.StatusCode != fiber.StatusOK {
+		t.Errorf("after delete: status = %d, want 200", after.StatusCode)
+	}
+	if got := after.Header.Get(fiber.HeaderETag); got == etag {
+		t.Errorf("ETag %s unchanged after a row was deleted", got)
+	}
+}
+
+func TestNotModifiedSkipsETagOnLookupFailure(t *testing.T) {
+	h := NewItemHandler(d2.NewRepository(dbtest.NewFake()), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/runewords", func(c *fiber.Ctx) error {
+		if h.notModified(c, "runewords") {
+			return nil
+		}
+		return c.SendString("[]")
+	})
+
+	resp := listRunewords(t, app, `W/"0-0"`)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderETag); got != "" {
+		t.Errorf("ETag = %q, want none when the version lookup fails", got)
+	}
+}
+
+func TestListETagChangesWithItemTypes(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		path   string
+		tables []string
+		route  func(*ItemHandler, *fiber.Ctx) error
+	}{
+		{"/uniques", []string{"unique_items", "item_bases"}, (*ItemHandler).GetAllUniques},
+		{"/sets", []string{"set_items", "item_bases"}, (*ItemHandler).GetAllSets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Class restrictions come from item_types, so an edit there has to
+			// change the tag even when the listed tables are untouched
+			etag := func(itemTypesUpdatedAt time.Time) string {
+				db := dbtest.NewFake().On("COUNT(*) FROM d2.item_types", []interface{}{itemTypesUpdatedAt, int64(40)})
+				for _, table := range tt.tables {
+					db.On("COUNT(*) FROM d2."+table, []interface{}{updatedAt, int64(10)})
+				}
+				h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+				app := fiber.New()
+				app.Get(tt.path, h.Localized(tt.route))
+
+				resp := getJSON(t, app, tt.path, nil)
+				if resp.StatusCode != fiber.StatusOK {
+					t.Fatalf("status = %d, want 200", resp.StatusCode)
+				}
+				return resp.Header.Get(fiber.HeaderETag)
+			}
+
+			before := etag(updatedAt.Add(-time.Hour))
+			if before == "" {
+				t.Fatal("response has no ETag")
+			}
+			if after := etag(updatedAt.Add(time.Hour)); after == before {
+				t.Errorf("ETag %s unchanged after an item_types edit", after)
+			}
+		})
+	}
+}
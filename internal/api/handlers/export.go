@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// ExportCatalog streams the catalog as one JSON document with a top-level
+// version and generated_at, followed by one array per section. Rows are
+// written as they are read, so the export never sits in memory. Unknown
+// sections and unreadable tables are rejected before streaming starts. A
+// failure mid-stream can't change the status code, so the document is left
+// unterminated: clients see invalid JSON rather than a complete-looking export.
+// GET /admin/d2/export?sections=uniques,runewords
+func (h *AdminHandler) ExportCatalog(c *fiber.Ctx) error {
+	sections := d2.ExportSections()
+	if raw := c.Query("sections"); raw != "" {
+		valid := make(map[string]bool, len(sections))
+		for _, s := range sections {
+			valid[s] = true
+		}
+		seen := make(map[string]bool, len(sections))
+		sections = sections[:0:0]
+		for _, s := range strings.Split(raw, ",") {
+			s = strings.TrimSpace(strings.ToLower(s))
+			if !valid[s] {
+				return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid section. Must be any of: "+strings.Join(d2.ExportSections(), ", "))
+			}
+			if !seen[s] {
+				seen[s] = true
+				sections = append(sections, s)
+			}
+		}
+	}
+
+	if err := h.repo.CheckExportSections(c.UserContext(), sections); err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to export catalog")
+	}
+
+	// The stream writer runs after the handler returns, once the fiber.Ctx
+	// has been released, so capture everything it needs up front
+	ctx := c.UserContext()
+	repo := h.repo
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="d2-catalog.json"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+
+		w.WriteString(`{"version":`)
+		enc.Encode(d2.ExportFormatVersion)
+		w.WriteString(`,"generated_at":`)
+		enc.Encode(time.Now().UTC())
+
+		for _, section := range sections {
+			w.WriteString(",")
+			enc.Encode(section)
+			w.WriteString(":[")
+
+			first := true
+			err := repo.StreamExportSection(ctx, section, func(row json.RawMessage) error {
+				if !first {
+					w.WriteString(",")
+				}
+				first = false
+				_, err := w.Write(row)
+				return err
+			})
+			if err != nil {
+				fmt.Printf("Catalog export aborted in %s: %v\n", section, err)
+				w.Flush()
+				return
+			}
+			w.WriteString("]")
+
+			if err := w.Flush(); err != nil {
+				// Client went away
+				return
+			}
+		}
+
+		w.WriteString("}\n")
+		w.Flush()
+	})
+
+	return nil
+}
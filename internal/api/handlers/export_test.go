@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// exportCatalog serves GET /export over db and returns the response and body
+func exportCatalog(t *testing.T, db *dbtest.Fake, query string) (*http.Response, string) {
+	t.Helper()
+	h := NewAdminHandler(d2.NewRepository(db), nil)
+	app := fiber.New()
+	app.Get("/export", h.ExportCatalog)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/export"+query, nil))
+	if err != nil {
+		t.Fatalf("GET /export%s: %v", query, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return resp, string(body)
+}
+
+func TestExportCatalogSectionsFilter(t *testing.T) {
+	db := dbtest.NewFake().
+		On("FROM d2.unique_items t", []interface{}{[]byte(`{"id":1,"name":"The Gnasher"}`)}).
+		On("FROM d2.runewords t",
+			[]interface{}{[]byte(`{"id":33,"display_name":"Enigma"}`)},
+			[]interface{}{[]byte(`{"id":34,"display_name":"Infinity"}`)},
+		)
+
+	resp, body := exportCatalog(t, db, "?sections=runewords,%20Uniques,runewords")
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", resp.StatusCode, body)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, body)
+	}
+	if len(doc) != 4 {
+		t.Errorf("export has keys %v, want version, generated_at, runewords and uniques", keysOf(doc))
+	}
+
+	var version int
+	if err := json.Unmarshal(doc["version"], &version); err != nil || version != d2.ExportFormatVersion {
+		t.Errorf("version = %s, want %d", doc["version"], d2.ExportFormatVersion)
+	}
+	var generatedAt time.Time
+	if err := json.Unmarshal(doc["generated_at"], &generatedAt); err != nil || generatedAt.IsZero() {
+		t.Errorf("generated_at = %s, want a timestamp", doc["generated_at"])
+	}
+
+	var runewords, uniques []map[string]interface{}
+	json.Unmarshal(doc["runewords"], &runewords)
+	json.Unmarshal(doc["uniques"], &uniques)
+	if len(runewords) != 2 || runewords[0]["display_name"] != "Enigma" {
+		t.Errorf("runewords = %v, want Enigma and Infinity", runewords)
+	}
+	if len(uniques) != 1 || uniques[0]["name"] != "The Gnasher" {
+		t.Errorf("uniques = %v, want The Gnasher", uniques)
+	}
+	if strings.Index(body, `"runewords"`) > strings.Index(body, `"uniques"`) {
+		t.Error("sections not written in the requested order")
+	}
+	if n := db.Count("FROM d2.runewords t"); n != 1 {
+		t.Errorf("streamed runewords %d times, want once", n)
+	}
+}
+
+func TestExportCatalogRejectsBeforeStreaming(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         *dbtest.Fake
+		query      string
+		wantStatus int
+	}{
+		{"unknown section", dbtest.NewFake(), "?sections=uniques,items", fiber.StatusBadRequest},
+		{"empty section name", dbtest.NewFake(), "?sections=uniques,", fiber.StatusBadRequest},
+		{
+			"unreadable table",
+			dbtest.NewFake().OnError("SELECT 1 FROM d2.unique_items", errors.New("connection refused")),
+			"?sections=uniques",
+			fiber.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := exportCatalog(t, tt.db, tt.query)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if strings.Contains(body, `"version"`) {
+				t.Errorf("streaming started: %s", body)
+			}
+		})
+	}
+}
+
+func TestExportCatalogFailureMidStreamIsInvalidJSON(t *testing.T) {
+	db := dbtest.NewFake().
+		On("FROM d2.unique_items t", []interface{}{[]byte(`{"id":1,"name":"The Gnasher"}`)}).
+		OnError("FROM d2.runewords t", errors.New("connection reset"))
+
+	resp, body := exportCatalog(t, db, "?sections=uniques,runewords")
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200 (sent before streaming)", resp.StatusCode)
+	}
+	if json.Valid([]byte(body)) {
+		t.Errorf("interrupted export parses as valid JSON: %s", body)
+	}
+}
+
+func keysOf(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
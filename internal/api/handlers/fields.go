@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+)
+
+// parseFieldsParam splits the ?fields= query value into a set of requested
+// top-level field names. Returns nil when no fields were requested.
+func parseFieldsParam(raw string) map[string]bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	fields := make(map[string]bool)
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			fields[f] = true
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// jsonFieldNames returns the JSON names of the top-level fields of a struct
+// (or pointer to struct), used to validate requested sparse fields.
+func jsonFieldNames(v interface{}) map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// filterFields marshals v and keeps only the requested top-level fields.
+// "id" is always kept so clients can correlate responses. Unknown field
+// names are returned so the caller can report them.
+func filterFields(v interface{}, fields map[string]bool) (map[string]json.RawMessage, []string, error) {
+	known := jsonFieldNames(v)
+	unknown := make([]string, 0)
+	for f := range fields {
+		if !known[f] {
+			unknown = append(unknown, f)
+		}
+	}
+	sort.Strings(unknown)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, nil, err
+	}
+
+	filtered := make(map[string]json.RawMessage, len(fields)+1)
+	for k, raw := range all {
+		if k == "id" || fields[k] {
+			filtered[k] = raw
+		}
+	}
+	return filtered, unknown, nil
+}
+
+// respondItemDetail writes a unified item detail response, applying the
+// optional ?fields=name,requirements,affixes sparse fieldset to the inner
+// item detail. Unknown field names are ignored and reported in a Warning header.
+func respondItemDetail(c *fiber.Ctx, detail dto.UnifiedItemDetail) error {
+	fields := parseFieldsParam(c.Query("fields"))
+	if fields == nil {
+		return c.JSON(detail)
+	}
+
+	var inner interface{}
+	switch {
+	case detail.Unique != nil:
+		inner = detail.Unique
+	case detail.SetItem != nil:
+		inner = detail.SetItem
+	case detail.Runeword != nil:
+		inner = detail.Runeword
+	case detail.Rune != nil:
+		inner = detail.Rune
+	case detail.Gem != nil:
+		inner = detail.Gem
+	case detail.Base != nil:
+		inner = detail.Base
+	case detail.Quest != nil:
+		inner = detail.Quest
+	default:
+		return c.JSON(detail)
+	}
+
+	filtered, unknown, err := filterFields(inner, fields)
+	if err != nil {
+		return c.JSON(detail)
+	}
+	if len(unknown) > 0 {
+		c.Set(fiber.HeaderWarning, fmt.Sprintf(`299 - "Unknown fields ignored: %s"`, strings.Join(unknown, ",")))
+	}
+
+	// Re-use the unified wrapper's key for the populated item type
+	key := detail.ItemType
+	if key == "set" {
+		key = "setItem"
+	}
+
+	return c.JSON(fiber.Map{
+		"itemType": detail.ItemType,
+		key:        filtered,
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// itemDetailTTL bounds how long a cached item detail can outlive a change
+// that skipped invalidation
+const itemDetailTTL = time.Hour
+
+// itemDetailLoader builds an item detail from the database
+type itemDetailLoader func(ctx context.Context, id int) (*dto.UnifiedItemDetail, error)
+
+// cachedItemDetail returns the detail for an item, serving it from Redis when
+// cached and populating the cache on a miss. Cache errors fall through to the
+// loader, and a nil cache always loads.
+func (h *ItemHandler) cachedItemDetail(ctx context.Context, itemType string, id int, load itemDetailLoader) (*dto.UnifiedItemDetail, error) {
+	if h.cache == nil {
+		return load(ctx, id)
+	}
+
+	key := cache.D2ItemDetailKey(itemType, id)
+	if locale := h.translator.Locale(); locale != d2.DefaultLocale {
+		key += ":" + locale
+	}
+	var detail dto.UnifiedItemDetail
+	if err := h.cache.Get(ctx, key, &detail); err == nil {
+		return &detail, nil
+	}
+
+	loaded, err := load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_ = h.cache.SetWithTTL(ctx, key, loaded, itemDetailTTL)
+	return loaded, nil
+}
+
+func (h *ItemHandler) loadUniqueDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetUniqueItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	base, _ := h.repo.GetItemBaseByCode(ctx, item.BaseCode)
+	return &dto.UnifiedItemDetail{
+		ItemType: "unique",
+		Unique:   h.convertUniqueToDTO(item, base, h.loadItemType(ctx, base)),
+	}, nil
+}
+
+func (h *ItemHandler) loadSetItemDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetSetItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	base, _ := h.repo.GetItemBaseByCode(ctx, item.BaseCode)
+	setNames, _ := h.repo.GetSetItemNames(ctx)
+	detail := h.convertSetItemToDTO(item, base, h.loadItemType(ctx, base))
+	setSiblings(detail, setNames)
+	return &dto.UnifiedItemDetail{
+		ItemType: "set",
+		SetItem:  detail,
+	}, nil
+}
+
+func (h *ItemHandler) loadRunewordDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetRuneword(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	bases, _ := h.repo.GetBasesForRuneword(ctx, id)
+	runeInfoMap, _ := h.repo.GetRunesByCodes(ctx, item.Runes)
+	typeInfoMap, _ := h.repo.GetItemTypesByCodes(ctx, item.ValidItemTypes)
+	return &dto.UnifiedItemDetail{
+		ItemType: "runeword",
+		Runeword: h.convertRunewordToDTO(item, bases, runeInfoMap, typeInfoMap),
+	}, nil
+}
+
+func (h *ItemHandler) loadRuneDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetRune(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnifiedItemDetail{
+		ItemType: "rune",
+		Rune:     h.convertRuneToDTO(item),
+	}, nil
+}
+
+func (h *ItemHandler) loadGemDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetGem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnifiedItemDetail{
+		ItemType: "gem",
+		Gem:      h.convertGemToDTO(item),
+	}, nil
+}
+
+func (h *ItemHandler) loadBaseDetail(ctx context.Context, id int) (*dto.UnifiedItemDetail, error) {
+	item, err := h.repo.GetItemBase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	itemType, _ := h.repo.GetItemType(ctx, item.ItemType)
+	return &dto.UnifiedItemDetail{
+		ItemType: "base",
+		Base:     h.convertBaseToDTO(item, itemType),
+	}, nil
+}
@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
 )
 
@@ -14,6 +19,33 @@ import (
 type ItemHandler struct {
 	repo       *d2.Repository
 	translator *d2.PropertyTranslator
+	config     ItemHandlerConfig
+	statRanges *statRangeCache
+	cache      *cache.RedisCache
+}
+
+// statRangeCache holds observed stat ranges for one catalog import. Entries
+// are dropped when the catalog's latest update time changes.
+type statRangeCache struct {
+	mu        sync.Mutex
+	updatedAt time.Time
+	ranges    map[string]*d2.StatRange
+}
+
+// ItemHandlerConfig holds optional behavior for the item handler
+type ItemHandlerConfig struct {
+	// ImagePlaceholderURL is the base URL for fallback images. When set, items
+	// without an image get "<ImagePlaceholderURL>/<category>.png" instead of an
+	// empty imageUrl. Empty disables the fallback.
+	ImagePlaceholderURL string
+
+	// Breakpoints are the per-class speed breakpoint tables. Nil uses
+	// d2.DefaultBreakpointTables.
+	Breakpoints d2.BreakpointTables
+
+	// Cache stores item detail responses under d2:item:<type>:<id>. Nil
+	// disables caching and every request reads from Postgres.
+	Cache *cache.RedisCache
 }
 
 // slugifyParam lowercases and replaces spaces with hyphens for composite stat codes.
@@ -29,38 +61,219 @@ func capitalize(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-// resolveItemTypeName looks up the item type by code and returns the parent
-// type name if available, falling back to the type's own name. This ensures
-// sub-types like "mcha" (Medium Charm) resolve to "Charm" instead of "Mcha".
-func (h *ItemHandler) resolveItemTypeName(code string) string {
-	if code == "" {
-		return ""
+// resolveItemTypeName returns the display name of an item type, falling back
+// to the capitalized code when the type was not found
+func resolveItemTypeName(code string, itemType *d2.ItemType) string {
+	if itemType != nil {
+		return capitalize(itemType.Name)
+	}
+	return capitalize(code)
+}
+
+// resolveClassRestriction returns the lowercase class a base is restricted to
+// and its display label ("Sorceress Only"), checking the base's own class tag
+// first and falling back to its item type, which may be nil.
+func resolveClassRestriction(base *d2.ItemBase, itemType *d2.ItemType) (string, string) {
+	if base == nil {
+		return "", ""
+	}
+	class := d2.ResolveClassRestriction(base, itemType)
+	return class, d2.ClassRestrictionLabel(class)
+}
+
+// loadItemType looks up the item type of a single base. Returns nil when the
+// base is nil or the lookup fails, which resolves the same as an unknown type.
+func (h *ItemHandler) loadItemType(ctx context.Context, base *d2.ItemBase) *d2.ItemType {
+	if base == nil || base.ItemType == "" {
+		return nil
 	}
-	it, err := h.repo.GetItemType(context.Background(), code)
+	itemType, _ := h.repo.GetItemType(ctx, base.ItemType)
+	return itemType
+}
+
+// loadItemTypes batch-loads the item types of bases in one query, keyed by
+// code. A failed lookup returns nil, which resolves the same as unknown types.
+func (h *ItemHandler) loadItemTypes(ctx context.Context, bases map[string]*d2.ItemBase) map[string]*d2.ItemType {
+	codes := make([]string, 0, len(bases))
+	for _, base := range bases {
+		if base != nil && base.ItemType != "" {
+			codes = append(codes, base.ItemType)
+		}
+	}
+	itemTypes, _ := h.repo.GetFullItemTypesByCodes(ctx, codes)
+	return itemTypes
+}
+
+// itemTypeOf returns the item type of base from a loadItemTypes result
+func itemTypeOf(itemTypes map[string]*d2.ItemType, base *d2.ItemBase) *d2.ItemType {
+	if base == nil {
+		return nil
+	}
+	return itemTypes[base.ItemType]
+}
+
+// classFilterMode selects which listing filter parseClassFilter reads and
+// whether it keeps unrestricted items
+type classFilterMode int
+
+const (
+	// classRestricted is ?class=: only items restricted to the class
+	classRestricted classFilterMode = iota
+	// classUsable is ?usable_by=: the class's own items plus unrestricted ones
+	classUsable
+)
+
+// classFilter is a parsed class listing filter. The zero value matches
+// every item.
+type classFilter struct {
+	class string
+	mode  classFilterMode
+}
+
+// matches reports whether an item restricted to restriction ("" for none)
+// passes the filter
+func (f classFilter) matches(restriction string) bool {
+	if f.class == "" || (f.mode == classUsable && restriction == "") {
+		return true
+	}
+	return restriction == f.class
+}
+
+// parseClassFilter validates the optional class listing filter for mode,
+// accepting class names ("sorceress") or codes ("sor"). ?usable_by= must also
+// be a class ID from the classes table. Returns ok=false for unknown classes;
+// err is set only when the classes lookup fails.
+func (h *ItemHandler) parseClassFilter(c *fiber.Ctx, mode classFilterMode) (f classFilter, ok bool, err error) {
+	f.mode = mode
+	param := "class"
+	if mode == classUsable {
+		param = "usable_by"
+	}
+	raw := strings.TrimSpace(c.Query(param))
+	if raw == "" {
+		return f, true, nil
+	}
+	if mode == classUsable {
+		if raw, err = h.lookupClassID(c.UserContext(), raw); err != nil || raw == "" {
+			return f, false, err
+		}
+	}
+	f.class = d2.NormalizeClassName(raw)
+	if f.class == "" && mode == classUsable {
+		// Classes added through the admin API have no short code
+		f.class = strings.ToLower(raw)
+	}
+	return f, f.class != "", nil
+}
+
+// lookupClassID returns the classes table ID matching id case-insensitively,
+// or "" when there is none
+func (h *ItemHandler) lookupClassID(ctx context.Context, id string) (string, error) {
+	classes, err := h.repo.GetAllClasses(ctx)
 	if err != nil {
-		return capitalize(code)
+		return "", err
 	}
-	return capitalize(it.Name)
+	for _, cl := range classes {
+		if strings.EqualFold(cl.ID, id) {
+			return cl.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// invalidClassFilter responds to a failed parseClassFilter
+func invalidClassFilter(c *fiber.Ctx, mode classFilterMode, err error) error {
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get classes")
+	}
+	if mode == classUsable {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid usable_by class. Must be a class ID from /classes")
+	}
+	return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid class")
+}
+
+// parseLadderFilter reads the optional ?ladder=true|false and ?season=<n>
+// listing filters. Returns ok=false for malformed values.
+func parseLadderFilter(c *fiber.Ctx) (d2.LadderFilter, bool) {
+	var filter d2.LadderFilter
+	if raw := c.Query("ladder"); raw != "" {
+		ladder, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, false
+		}
+		filter.Ladder = &ladder
+	}
+	if raw := c.Query("season"); raw != "" {
+		season, err := strconv.Atoi(raw)
+		if err != nil || season < 1 {
+			return filter, false
+		}
+		filter.Season = &season
+	}
+	return filter, true
+}
+
+// parseSocketRange reads the optional ?min_sockets= and ?max_sockets= base
+// filters. A missing bound defaults to 0 or 6; returns nil when neither is
+// set and ok=false when a bound is outside 0-6 or min exceeds max.
+func parseSocketRange(c *fiber.Ctx) (*d2.SocketRange, bool) {
+	minRaw, maxRaw := c.Query("min_sockets"), c.Query("max_sockets")
+	if minRaw == "" && maxRaw == "" {
+		return nil, true
+	}
+	sockets := &d2.SocketRange{Min: 0, Max: maxItemSockets}
+	var err error
+	if minRaw != "" {
+		if sockets.Min, err = strconv.Atoi(minRaw); err != nil {
+			return nil, false
+		}
+	}
+	if maxRaw != "" {
+		if sockets.Max, err = strconv.Atoi(maxRaw); err != nil {
+			return nil, false
+		}
+	}
+	if sockets.Min < 0 || sockets.Max > maxItemSockets || sockets.Min > sockets.Max {
+		return nil, false
+	}
+	return sockets, true
+}
+
+// invalidLadderFilter responds to a malformed ladder or season parameter
+func invalidLadderFilter(c *fiber.Ctx) error {
+	return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid ladder or season parameter")
 }
 
 // NewItemHandler creates a new item handler
-func NewItemHandler(repo *d2.Repository) *ItemHandler {
+func NewItemHandler(repo *d2.Repository, config ItemHandlerConfig) *ItemHandler {
 	return &ItemHandler{
 		repo:       repo,
-		translator: d2.DefaultTranslator,
+		translator: d2.DefaultTranslator.WithSkillNames(repo.SkillNames()),
+		config:     config,
+		statRanges: &statRangeCache{ranges: make(map[string]*d2.StatRange)},
+		cache:      config.Cache,
+	}
+}
+
+// resolveImageURL returns the image URL to expose for an item and whether the
+// item has a real image. Items without an image fall back to a deterministic
+// per-category placeholder when one is configured.
+func (h *ItemHandler) resolveImageURL(imageURL, category string) (string, bool) {
+	if imageURL != "" {
+		return imageURL, true
+	}
+	if h.config.ImagePlaceholderURL == "" {
+		return "", false
 	}
+	return strings.TrimRight(h.config.ImagePlaceholderURL, "/") + "/" + category + ".png", false
 }
 
 // Search handles item search requests
-// GET /api/d2/items/search?q=<query>&limit=<limit>
+// GET /api/d2/items/search?q=<query>&limit=<limit>&include_quest=true&include_description=true
 func (h *ItemHandler) Search(c *fiber.Ctx) error {
 	query := c.Query("q")
 	if query == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Query parameter 'q' is required",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameter 'q' is required")
 	}
 
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
@@ -71,16 +284,249 @@ func (h *ItemHandler) Search(c *fiber.Ctx) error {
 		limit = 100
 	}
 
-	results, err := h.repo.SearchItems(c.Context(), query, limit)
+	opts := d2.SearchOptions{
+		IncludeQuest:       c.QueryBool("include_quest", false),
+		IncludeDescription: c.QueryBool("include_description", false),
+	}
+
+	results, err := h.repo.SearchItems(c.UserContext(), query, limit, opts)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to search items",
-			Code:    500,
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to search items")
+	}
+
+	// Get total count
+	totalCount, _ := h.repo.CountSearchResults(c.UserContext(), query, opts)
+
+	// Fall back to typo-tolerant matching when nothing matched by name or code
+	if len(results) == 0 && c.QueryBool("fuzzy", false) {
+		results, err = h.repo.SearchItemsFuzzy(c.UserContext(), query, limit, opts)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to search items")
+		}
+		totalCount = len(results)
+	}
+
+	return c.JSON(dto.SearchResponse{
+		Items:      convertSearchResults(results),
+		TotalCount: totalCount,
+		Query:      query,
+	})
+}
+
+// SearchByStats finds uniques, set items and runewords carrying the given stats.
+// match=all (default) requires every stat; match=any requires at least one.
+// Repeated stat/min/max triples (paired by position) add value thresholds that
+// must all hold, compared against each property's max roll (value=max, the
+// default) or min roll (value=min).
+// GET /api/d2/items/by-stats?stats=crush,deadly&match=any&stat=fcr&min=30&limit=20&offset=0
+func (h *ItemHandler) SearchByStats(c *fiber.Ctx) error {
+	codes := make([]string, 0)
+	for _, code := range strings.Split(c.Query("stats"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	thresholds, ok := parseStatThresholds(c)
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid stat thresholds. Pair each 'stat' with optional integer 'min' and 'max' values")
+	}
+
+	if len(codes) == 0 && len(thresholds) == 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameter 'stats' or 'stat' is required")
+	}
+
+	value := strings.ToLower(c.Query("value", "max"))
+	if value != "max" && value != "min" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid value. Must be one of: max, min")
+	}
+
+	match := strings.ToLower(c.Query("match", "all"))
+	if match != "all" && match != "any" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid match. Must be one of: all, any")
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	opts := d2.StatSearchOptions{
+		Codes:      codes,
+		MatchAny:   match == "any",
+		Limit:      limit,
+		Offset:     offset,
+		CompareMin: value == "min",
+	}
+	for _, t := range thresholds {
+		opts.Thresholds = append(opts.Thresholds, d2.StatThreshold{Code: t.Code, Min: t.Min, Max: t.Max})
+	}
+
+	results, total, err := h.repo.SearchItemsByStats(c.UserContext(), opts)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to search items by stats")
+	}
+
+	return c.JSON(dto.StatSearchResponse{
+		Items:      convertSearchResults(results),
+		TotalCount: total,
+		Stats:      codes,
+		Match:      match,
+		Thresholds: thresholds,
+		Value:      value,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// parseStatThresholds pairs repeated ?stat= params with the ?min= and ?max=
+// params at the same position. Empty min/max values skip that bound.
+// Returns ok=false when there are more bounds than stats or a bound isn't an
+// integer.
+func parseStatThresholds(c *fiber.Ctx) ([]dto.StatThreshold, bool) {
+	args := c.Context().QueryArgs()
+	stats, mins, maxs := args.PeekMulti("stat"), args.PeekMulti("min"), args.PeekMulti("max")
+	if len(mins) > len(stats) || len(maxs) > len(stats) {
+		return nil, false
+	}
+
+	bound := func(values [][]byte, i int) (*int, bool) {
+		if i >= len(values) || len(values[i]) == 0 {
+			return nil, true
+		}
+		v, err := strconv.Atoi(string(values[i]))
+		if err != nil {
+			return nil, false
+		}
+		return &v, true
+	}
+
+	thresholds := make([]dto.StatThreshold, 0, len(stats))
+	for i, stat := range stats {
+		code := strings.TrimSpace(string(stat))
+		if code == "" {
+			return nil, false
+		}
+		lower, ok := bound(mins, i)
+		if !ok {
+			return nil, false
+		}
+		upper, ok := bound(maxs, i)
+		if !ok {
+			return nil, false
+		}
+		thresholds = append(thresholds, dto.StatThreshold{Code: code, Min: lower, Max: upper})
+	}
+	return thresholds, true
+}
+
+// Optimize ranks uniques, set items and runewords by how well they meet a set
+// of stat minimums, optionally restricted to a gear slot. The ranking is a
+// heuristic (requirements met, then magnitude), not a build solver.
+// POST /api/d2/items/optimize
+func (h *ItemHandler) Optimize(c *fiber.Ctx) error {
+	var req dto.OptimizeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid request body")
+	}
+
+	reqs := make([]d2.StatRequirement, 0, len(req.Stats))
+	codes := make([]string, 0, len(req.Stats))
+	for _, s := range req.Stats {
+		code := strings.TrimSpace(s.Code)
+		if code == "" || s.Min < 0 {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Each stat requires a code and a non-negative min")
+		}
+		reqs = append(reqs, d2.StatRequirement{Code: code, Min: s.Min})
+		codes = append(codes, code)
+	}
+	if len(reqs) == 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "At least one stat requirement is required")
+	}
+
+	var itemTypes []string
+	if req.Slot != "" {
+		itemTypes = d2.SlotItemTypes(req.Slot)
+		if itemTypes == nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid slot. Must be one of: "+strings.Join(d2.OptimizeSlots(), ", "))
+		}
+	}
+
+	types := make([]string, 0, len(req.Types))
+	for _, t := range req.Types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "unique" && t != "set" && t != "runeword" {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid type. Must be one of: unique, set, runeword")
+		}
+		types = append(types, t)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	candidates, err := h.repo.GetStatCandidates(c.UserContext(), d2.StatCandidateOptions{
+		Codes:     codes,
+		ItemTypes: itemTypes,
+		Types:     types,
+	})
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to load items")
+	}
+
+	ranked := d2.RankStatCandidates(candidates, reqs)
+	total := len(ranked)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page := ranked[offset:end]
+
+	summaries := make([]d2.SearchResult, 0, len(page))
+	for _, r := range page {
+		summaries = append(summaries, r.SearchResult)
+	}
+	converted := convertSearchResults(summaries)
+
+	items := make([]dto.OptimizeResult, 0, len(page))
+	for i, r := range page {
+		items = append(items, dto.OptimizeResult{
+			ItemSearchResult: converted[i],
+			RequirementsMet:  r.Met,
+			Score:            r.Score,
+			Values:           r.Values,
 		})
 	}
 
-	// Convert to DTOs
+	return c.JSON(dto.OptimizeResponse{
+		Items:        items,
+		TotalCount:   total,
+		Requirements: len(reqs),
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
+
+// convertSearchResults converts repository search results to DTOs
+func convertSearchResults(results []d2.SearchResult) []dto.ItemSearchResult {
 	items := make([]dto.ItemSearchResult, 0, len(results))
 	for _, r := range results {
 		category := capitalize(r.Category)
@@ -90,23 +536,17 @@ func (h *ItemHandler) Search(c *fiber.Ctx) error {
 			baseName = ""
 		}
 		items = append(items, dto.ItemSearchResult{
-			ID:       strconv.Itoa(r.ID),
-			Name:     r.Name,
-			Type:     capitalize(r.Type),
-			Category: category,
-			ImageURL: r.ImageURL,
-			BaseName: baseName,
+			ID:        strconv.Itoa(r.ID),
+			Name:      r.Name,
+			Type:      capitalize(r.Type),
+			Category:  category,
+			ImageURL:  r.ImageURL,
+			BaseName:  baseName,
+			MatchType: r.MatchType,
+			Score:     r.Score,
 		})
 	}
-
-	// Get total count
-	totalCount, _ := h.repo.CountSearchResults(c.Context(), query)
-
-	return c.JSON(dto.SearchResponse{
-		Items:      items,
-		TotalCount: totalCount,
-		Query:      query,
-	})
+	return items
 }
 
 // GetUniqueItem handles unique item detail requests
@@ -114,31 +554,15 @@ func (h *ItemHandler) Search(c *fiber.Ctx) error {
 func (h *ItemHandler) GetUniqueItem(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
-	item, err := h.repo.GetUniqueItem(c.Context(), id)
+	detail, err := h.cachedItemDetail(c.UserContext(), "unique", id, h.loadUniqueDetail)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Unique item not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Unique item not found")
 	}
 
-	// Get base item info
-	base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
-
-	detail := h.convertUniqueToDTO(item, base)
-
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "unique",
-		Unique:   detail,
-	})
+	return respondItemDetail(c, *detail)
 }
 
 // GetSetItem handles set item detail requests
@@ -146,69 +570,287 @@ func (h *ItemHandler) GetUniqueItem(c *fiber.Ctx) error {
 func (h *ItemHandler) GetSetItem(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
-	item, err := h.repo.GetSetItem(c.Context(), id)
+	detail, err := h.cachedItemDetail(c.UserContext(), "set", id, h.loadSetItemDetail)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Set item not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Set item not found")
 	}
 
-	// Get base item info
-	base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
+	return respondItemDetail(c, *detail)
+}
 
-	detail := h.convertSetItemToDTO(item, base)
+// GetSetCombined returns the combined stats of a set with every piece equipped:
+// each item's own and bonus properties plus the partial and full set bonuses.
+// GET /api/d2/set/:name/combined
+func (h *ItemHandler) GetSetCombined(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil || strings.TrimSpace(name) == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid set name")
+	}
 
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "set",
-		SetItem:  detail,
-	})
+	items, err := h.repo.GetSetItemsBySetName(c.UserContext(), name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
+	}
+	if len(items) == 0 {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Set not found")
+	}
+
+	// Set bonuses are optional; items alone still combine
+	bonus, _ := h.repo.GetSetBonusByName(c.UserContext(), items[0].SetName)
+
+	result := dto.SetCombinedStats{
+		SetName: items[0].SetName,
+		Items:   make([]string, 0, len(items)),
+		Affixes: h.convertPropertiesToAffixes(d2.CombineSetProperties(h.translator, items, bonus)),
+	}
+	for _, item := range items {
+		result.Items = append(result.Items, item.Name)
+	}
+
+	return c.JSON(result)
 }
 
-// GetRuneword handles runeword detail requests
-// GET /api/d2/items/runeword/:id
-func (h *ItemHandler) GetRuneword(c *fiber.Ctx) error {
+// GetFullSet returns a set's partial and full bonuses, its graduated bonus
+// tiers and every member item's full detail in one response
+// GET /api/d2/set/:name/full
+func (h *ItemHandler) GetFullSet(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil || strings.TrimSpace(name) == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid set name")
+	}
+
+	set, err := h.repo.GetFullSet(c.UserContext(), name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set")
+	}
+	if set == nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Set not found")
+	}
+
+	names := make([]string, 0, len(set.Items))
+	for _, item := range set.Items {
+		names = append(names, item.Name)
+	}
+	setNames := map[string][]string{strings.ToLower(set.Name): names}
+
+	result := dto.FullSetResponse{
+		SetName: set.Name,
+		Tiers:   make([]dto.SetBonusTier, 0),
+		Items:   make([]*dto.SetItemDetail, 0, len(set.Items)),
+	}
+	if set.Bonus != nil {
+		result.Bonus = h.convertSetBonusToDTO(set.Bonus, names)
+		for _, tier := range d2.SetBonusTiers(set.Bonus, len(set.Items)) {
+			result.Tiers = append(result.Tiers, dto.SetBonusTier{
+				ItemCount: tier.ItemCount,
+				Full:      tier.Full,
+				Affixes:   h.convertPropertiesToAffixes(tier.Properties),
+			})
+		}
+	}
+	itemTypes := h.loadItemTypes(c.UserContext(), set.Bases)
+	for i := range set.Items {
+		base := set.Bases[set.Items[i].BaseCode]
+		detail := h.convertSetItemToDTO(&set.Items[i], base, itemTypeOf(itemTypes, base))
+		setSiblings(detail, setNames)
+		result.Items = append(result.Items, detail)
+	}
+
+	return c.JSON(result)
+}
+
+// GetSetBonuses returns the set bonus tiers active at an equipped piece count
+// (cumulative) and the higher tiers still locked
+// GET /api/d2/set/:name/bonuses?equipped=3
+func (h *ItemHandler) GetSetBonuses(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil || strings.TrimSpace(name) == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid set name")
+	}
+
+	items, err := h.repo.GetSetItemsBySetName(c.UserContext(), name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
+	}
+	bonus, err := h.repo.GetSetBonusByName(c.UserContext(), name)
+	if err != nil || len(items) == 0 {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Set not found")
+	}
+
+	equipped, err := strconv.Atoi(c.Query("equipped"))
+	if err != nil || equipped < 0 || equipped > len(items) {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, fmt.Sprintf("Query parameter 'equipped' must be between 0 and %d", len(items)))
+	}
+
+	result := dto.SetBonusProgress{
+		SetName:    bonus.Name,
+		Equipped:   equipped,
+		TotalItems: len(items),
+		Active:     make([]dto.SetBonusTier, 0),
+		Locked:     make([]dto.SetBonusTier, 0),
+	}
+	for _, tier := range d2.SetBonusTiers(bonus, len(items)) {
+		t := dto.SetBonusTier{
+			ItemCount: tier.ItemCount,
+			Full:      tier.Full,
+			Affixes:   h.convertPropertiesToAffixes(tier.Properties),
+		}
+		if tier.ItemCount <= equipped {
+			result.Active = append(result.Active, t)
+		} else {
+			result.Locked = append(result.Locked, t)
+		}
+	}
+
+	return c.JSON(result)
+}
+
+// GetSetBonus returns a set's partial and full bonuses with the names of the
+// items belonging to it
+// GET /api/d2/set/:name
+func (h *ItemHandler) GetSetBonus(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil || strings.TrimSpace(name) == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid set name")
+	}
+
+	bonus, err := h.repo.GetSetBonusByName(c.UserContext(), name)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Set not found")
+	}
+
+	items, err := h.repo.GetSetItemsBySetName(c.UserContext(), bonus.Name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+
+	return c.JSON(h.convertSetBonusToDTO(bonus, names))
+}
+
+// GetAllSetBonuses returns every set's partial and full bonuses
+// GET /api/d2/sets/bonuses
+func (h *ItemHandler) GetAllSetBonuses(c *fiber.Ctx) error {
+	if h.notModified(c, "set_bonuses") {
+		return nil
+	}
+
+	bonuses, err := h.repo.GetAllSetBonuses(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set bonuses")
+	}
+	itemNames, err := h.repo.GetSetItemNames(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
+	}
+
+	results := make([]*dto.SetBonusDetail, 0, len(bonuses))
+	for i := range bonuses {
+		names := itemNames[strings.ToLower(bonuses[i].Name)]
+		results = append(results, h.convertSetBonusToDTO(&bonuses[i], names))
+	}
+
+	return c.JSON(results)
+}
+
+// GetSameBase returns the other uniques and set items sharing the base of the
+// given unique or set item. The source item is excluded.
+// GET /api/d2/items/:type/:id/same-base
+func (h *ItemHandler) GetSameBase(c *fiber.Ctx) error {
+	itemType := strings.ToLower(c.Params("type"))
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid runeword ID",
-			Code:    400,
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
+	}
+
+	var baseCode, baseName string
+	switch itemType {
+	case "unique":
+		item, err := h.repo.GetUniqueItem(c.UserContext(), id)
+		if err != nil {
+			return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Item not found")
+		}
+		baseCode, baseName = item.BaseCode, item.BaseName
+	case "set":
+		item, err := h.repo.GetSetItem(c.UserContext(), id)
+		if err != nil {
+			return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Item not found")
+		}
+		baseCode, baseName = item.BaseCode, item.BaseName
+	default:
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item type. Must be one of: unique, set")
+	}
+
+	// An item whose base didn't resolve shares it with nothing; querying ""
+	// would match every other unresolved item
+	if baseCode == "" {
+		return c.JSON(dto.SameBaseItems{
+			Base:     dto.ItemBaseInfo{Name: baseName},
+			Uniques:  []*dto.UniqueItemDetail{},
+			SetItems: []*dto.SetItemDetail{},
 		})
 	}
 
-	item, err := h.repo.GetRuneword(c.Context(), id)
+	uniques, err := h.repo.GetUniqueItemsByBaseCode(c.UserContext(), baseCode)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Runeword not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get unique items")
+	}
+	setItems, err := h.repo.GetSetItemsByBaseCode(c.UserContext(), baseCode)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
 	}
 
-	// Get valid base items for this runeword
-	bases, _ := h.repo.GetBasesForRuneword(c.Context(), id)
+	// All results share one base, so a single lookup covers them
+	base, _ := h.repo.GetItemBaseByCode(c.UserContext(), baseCode)
+	baseType := h.loadItemType(c.UserContext(), base)
+	setNames, _ := h.repo.GetSetItemNames(c.UserContext())
 
-	// Get rune info for display
-	runeInfoMap, _ := h.repo.GetRunesByCodes(c.Context(), item.Runes)
+	result := dto.SameBaseItems{
+		Base:     dto.ItemBaseInfo{Code: baseCode, Name: baseName},
+		Uniques:  make([]*dto.UniqueItemDetail, 0, len(uniques)),
+		SetItems: make([]*dto.SetItemDetail, 0, len(setItems)),
+	}
+	for _, item := range uniques {
+		if itemType == "unique" && item.ID == id {
+			continue
+		}
+		detail := h.convertUniqueToDTO(&item, base, baseType)
+		result.Base = detail.Base
+		result.Uniques = append(result.Uniques, detail)
+	}
+	for _, item := range setItems {
+		if itemType == "set" && item.ID == id {
+			continue
+		}
+		detail := h.convertSetItemToDTO(&item, base, baseType)
+		setSiblings(detail, setNames)
+		result.Base = detail.Base
+		result.SetItems = append(result.SetItems, detail)
+	}
 
-	// Get item type names for display
-	typeInfoMap, _ := h.repo.GetItemTypesByCodes(c.Context(), item.ValidItemTypes)
+	return c.JSON(result)
+}
 
-	detail := h.convertRunewordToDTO(item, bases, runeInfoMap, typeInfoMap)
+// GetRuneword handles runeword detail requests
+// GET /api/d2/items/runeword/:id
+func (h *ItemHandler) GetRuneword(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid runeword ID")
+	}
 
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "runeword",
-		Runeword: detail,
-	})
+	detail, err := h.cachedItemDetail(c.UserContext(), "runeword", id, h.loadRunewordDetail)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Runeword not found")
+	}
+
+	return respondItemDetail(c, *detail)
 }
 
 // GetRunewordBases returns valid base items for a runeword
@@ -216,63 +858,221 @@ func (h *ItemHandler) GetRuneword(c *fiber.Ctx) error {
 func (h *ItemHandler) GetRunewordBases(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid runeword ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid runeword ID")
 	}
 
-	bases, err := h.repo.GetBasesForRuneword(c.Context(), id)
+	bases, err := h.repo.GetBasesForRuneword(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get runeword bases",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runeword bases")
 	}
 
 	results := make([]dto.RunewordBaseItem, 0, len(bases))
 	for _, b := range bases {
 		results = append(results, dto.RunewordBaseItem{
-			ID:         b.ItemBaseID,
-			Code:       b.ItemBaseCode,
-			Name:       b.ItemBaseName,
-			Category:   capitalize(b.Category),
-			MaxSockets: b.MaxSockets,
+			ID:              b.ItemBaseID,
+			Code:            b.ItemBaseCode,
+			Name:            b.ItemBaseName,
+			Category:        capitalize(b.Category),
+			MaxSockets:      b.MaxSockets,
+			RequiredSockets: b.RequiredSockets,
 		})
 	}
 
 	return c.JSON(results)
 }
 
+// GetRunewordPreview computes a runeword's final defense and damage on one of
+// its valid bases
+// GET /api/d2/items/runeword/:id/bases/:baseId/preview
+func (h *ItemHandler) GetRunewordPreview(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid runeword ID")
+	}
+	baseID, err := strconv.Atoi(c.Params("baseId"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid base ID")
+	}
+
+	rw, err := h.repo.GetRuneword(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Runeword not found")
+	}
+
+	base, err := h.repo.GetItemBase(c.UserContext(), baseID)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Base item not found")
+	}
+
+	validBases, err := h.repo.GetBasesForRuneword(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runeword bases")
+	}
+	valid := false
+	for _, b := range validBases {
+		if b.ItemBaseID == base.ID {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Base is not a valid base for this runeword")
+	}
+
+	preview := d2.ComputeRunewordOnBase(rw, base)
+
+	result := dto.RunewordPreview{
+		RunewordID:   rw.ID,
+		RunewordName: rw.DisplayName,
+		Base: dto.RunewordBaseItem{
+			ID:              base.ID,
+			Code:            base.Code,
+			Name:            base.Name,
+			Category:        capitalize(base.Category),
+			MaxSockets:      base.MaxSockets,
+			RequiredSockets: preview.RequiredSockets,
+		},
+		RequiredSockets: preview.RequiredSockets,
+		Fits:            preview.Fits,
+		Normal:          convertRunewordPreviewStats(preview.Normal),
+	}
+	if result.RunewordName == "" {
+		result.RunewordName = rw.Name
+	}
+	if preview.Ethereal != nil {
+		eth := convertRunewordPreviewStats(*preview.Ethereal)
+		result.Ethereal = &eth
+	}
+
+	return c.JSON(result)
+}
+
+// convertRunewordPreviewStats converts computed runeword stats, omitting
+// defense or damage when the base has none
+func convertRunewordPreviewStats(stats d2.RunewordStats) dto.RunewordPreviewStats {
+	var out dto.RunewordPreviewStats
+	if stats.MaxAC > 0 {
+		out.Defense = &dto.DefenseRange{Min: stats.MinAC, Max: stats.MaxAC}
+	}
+	if stats.MaxDam > 0 || stats.TwoHandMaxDam > 0 {
+		out.Damage = &dto.DamageRange{
+			OneHandMin: stats.MinDam,
+			OneHandMax: stats.MaxDam,
+			TwoHandMin: stats.TwoHandMinDam,
+			TwoHandMax: stats.TwoHandMaxDam,
+		}
+	}
+	return out
+}
+
 // GetRune handles rune detail requests
 // GET /api/d2/items/rune/:id
 func (h *ItemHandler) GetRune(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid rune ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid rune ID")
+	}
+
+	detail, err := h.cachedItemDetail(c.UserContext(), "rune", id, h.loadRuneDetail)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Rune not found")
 	}
 
-	item, err := h.repo.GetRune(c.Context(), id)
+	return respondItemDetail(c, *detail)
+}
+
+// GetRuneUpgradePath returns the next rune up, the cube recipe to make it and
+// the full upgrade chain to Zod
+// GET /api/d2/items/rune/:id/upgrade
+func (h *ItemHandler) GetRuneUpgradePath(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Rune not found",
-			Code:    404,
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid rune ID")
+	}
+
+	path, err := h.repo.GetRuneUpgradePath(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get rune upgrade path")
+	}
+	if len(path) == 0 {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Rune not found")
+	}
+
+	result := dto.RuneUpgradePath{
+		Rune:  convertRuneRef(&path[0]),
+		Chain: make([]dto.RuneUpgradeStep, 0, len(path)-1),
+	}
+	for i := 0; i+1 < len(path); i++ {
+		recipe, ok := d2.RuneUpgradeRecipe(path[i].RuneNumber)
+		if !ok || path[i+1].RuneNumber != path[i].RuneNumber+1 {
+			// Gap in the imported runes; the chain can't continue
+			break
+		}
+		result.Chain = append(result.Chain, dto.RuneUpgradeStep{
+			From:   convertRuneRef(&path[i]),
+			To:     convertRuneRef(&path[i+1]),
+			Recipe: dto.RuneRecipe{Quantity: recipe.Quantity, Gem: recipe.Gem},
 		})
 	}
+	if len(result.Chain) > 0 {
+		result.Next = &result.Chain[0].To
+		result.Recipe = &result.Chain[0].Recipe
+	}
 
-	detail := h.convertRuneToDTO(item)
+	return c.JSON(result)
+}
 
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "rune",
-		Rune:     detail,
-	})
+func convertRuneRef(rn *d2.Rune) dto.RuneRef {
+	return dto.RuneRef{
+		ID:         rn.ID,
+		Code:       rn.Code,
+		Name:       rn.Name,
+		RuneNumber: rn.RuneNumber,
+		ImageURL:   rn.ImageURL,
+	}
+}
+
+// GetRuneFull returns a rune with its socket mods and every runeword that uses it.
+// The rune can be resolved by ID or by code (e.g. "r30").
+// GET /api/d2/runes/:id/full
+func (h *ItemHandler) GetRuneFull(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+
+	var item *d2.Rune
+	var err error
+	if id, convErr := strconv.Atoi(idParam); convErr == nil {
+		item, err = h.repo.GetRune(c.UserContext(), id)
+	} else {
+		item, err = h.repo.GetRuneByCode(c.UserContext(), idParam)
+	}
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Rune not found")
+	}
+
+	runewords, err := h.repo.GetRunewordsContainingRune(c.UserContext(), item.Code)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runewords for rune")
+	}
+
+	// Batch fetch rune and type info for the runeword list
+	allRuneCodes := make([]string, 0)
+	allTypeCodes := make([]string, 0)
+	for _, rw := range runewords {
+		allRuneCodes = append(allRuneCodes, rw.Runes...)
+		allTypeCodes = append(allTypeCodes, rw.ValidItemTypes...)
+	}
+	runeInfoMap, _ := h.repo.GetRunesByCodes(c.UserContext(), allRuneCodes)
+	typeInfoMap, _ := h.repo.GetItemTypesByCodes(c.UserContext(), allTypeCodes)
+
+	result := dto.RuneFullDetail{
+		Rune:      h.convertRuneToDTO(item),
+		Runewords: make([]*dto.RunewordDetail, 0, len(runewords)),
+	}
+	for i := range runewords {
+		result.Runewords = append(result.Runewords, h.convertRunewordToDTO(&runewords[i], nil, runeInfoMap, typeInfoMap))
+	}
+
+	return c.JSON(result)
 }
 
 // GetGem handles gem detail requests
@@ -280,28 +1080,46 @@ func (h *ItemHandler) GetRune(c *fiber.Ctx) error {
 func (h *ItemHandler) GetGem(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid gem ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid gem ID")
 	}
 
-	item, err := h.repo.GetGem(c.Context(), id)
+	detail, err := h.cachedItemDetail(c.UserContext(), "gem", id, h.loadGemDetail)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Gem not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Gem not found")
 	}
 
-	detail := h.convertGemToDTO(item)
+	return respondItemDetail(c, *detail)
+}
 
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "gem",
-		Gem:      detail,
-	})
+// GetGemProgression returns every quality tier of a gem's type, chipped to
+// perfect, so the upgrade ladder and mod differences can be shown
+// GET /api/d2/items/gem/:id/progression
+func (h *ItemHandler) GetGemProgression(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid gem ID")
+	}
+
+	gem, err := h.repo.GetGem(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Gem not found")
+	}
+
+	tiers, err := h.repo.GetGemProgression(c.UserContext(), gem.GemType)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get gem progression")
+	}
+
+	result := dto.GemProgression{
+		GemType:   capitalize(gem.GemType),
+		CurrentID: gem.ID,
+		Tiers:     make([]*dto.GemDetail, 0, len(tiers)),
+	}
+	for i := range tiers {
+		result.Tiers = append(result.Tiers, h.convertGemToDTO(&tiers[i]))
+	}
+
+	return c.JSON(result)
 }
 
 // GetBase handles base item detail requests
@@ -309,31 +1127,149 @@ func (h *ItemHandler) GetGem(c *fiber.Ctx) error {
 func (h *ItemHandler) GetBase(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid base item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid base item ID")
+	}
+
+	detail, err := h.cachedItemDetail(c.UserContext(), "base", id, h.loadBaseDetail)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Base item not found")
+	}
+
+	return respondItemDetail(c, *detail)
+}
+
+// GetBaseRunewords returns the runewords a base item can host, fewest
+// required sockets first. The inverse of GetRunewordBases.
+// GET /api/d2/items/base/:id/runewords
+func (h *ItemHandler) GetBaseRunewords(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid base item ID")
+	}
+
+	if _, err := h.repo.GetItemBase(c.UserContext(), id); err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Base item not found")
+	}
+
+	runewords, err := h.repo.GetRunewordsForBase(c.UserContext(), id)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runewords for base")
+	}
+
+	runeCodes := make([]string, 0)
+	for _, rw := range runewords {
+		runeCodes = append(runeCodes, rw.Runes...)
+	}
+	// A failed lookup degrades to rune codes, as in the runeword detail
+	runeInfoMap, _ := h.repo.GetRunesByCodes(c.UserContext(), runeCodes)
+
+	results := make([]dto.BaseRunewordItem, 0, len(runewords))
+	for _, rw := range runewords {
+		item := dto.BaseRunewordItem{
+			ID:              rw.RunewordID,
+			Name:            rw.Name,
+			DisplayName:     rw.DisplayName,
+			LadderOnly:      rw.LadderOnly,
+			Runes:           make([]dto.RunewordRune, 0, len(rw.Runes)),
+			RequiredSockets: rw.RequiredSockets,
+		}
+		item.ImageURL, _ = h.resolveImageURL(rw.ImageURL, "runeword")
+		for _, runeCode := range rw.Runes {
+			rune := dto.RunewordRune{Code: runeCode, Name: runeCode}
+			if info, ok := runeInfoMap[runeCode]; ok {
+				rune.ID = info.ID
+				rune.Name = strings.TrimSuffix(info.Name, " Rune")
+				rune.ImageURL = info.ImageURL
+			}
+			item.RuneOrder += rune.Name
+			item.Runes = append(item.Runes, rune)
+		}
+		results = append(results, item)
+	}
+
+	return c.JSON(results)
+}
+
+// GetBaseBreakpoints returns the attack speed breakpoints of a weapon base for
+// each class (or only the ?class= given), with the frames per attack reached
+// at ?ias=. Class-restricted weapons default to their class. Non-weapon bases
+// return applicable=false instead of an error.
+// GET /api/d2/items/base/:id/breakpoints?ias=40&class=paladin
+func (h *ItemHandler) GetBaseBreakpoints(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid base item ID")
+	}
+
+	ias, err := strconv.Atoi(c.Query("ias", "0"))
+	if err != nil || ias < 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid ias value")
+	}
+
+	// ?class= never queries the classes table, so there is no lookup error
+	classOnly, ok, _ := h.parseClassFilter(c, classRestricted)
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Unknown class: "+c.Query("class"))
+	}
+	class := classOnly.class
+
+	ctx := c.UserContext()
+	base, err := h.repo.GetItemBase(ctx, id)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Base item not found")
+	}
+
+	result := dto.BaseBreakpointsResponse{
+		BaseID:      base.ID,
+		Name:        base.Name,
+		WeaponClass: d2.WeaponAnimationClass(base),
+		Speed:       base.Speed,
+		IAS:         ias,
+		Classes:     make([]dto.ClassIASBreakpoints, 0),
+	}
+	if result.WeaponClass == "" {
+		result.Reason = "Not applicable: " + base.Name + " is not a weapon with an attack animation"
+		return c.JSON(result)
+	}
+	result.Applicable = true
+
+	classes := d2.AttackSpeedClasses()
+	if class != "" {
+		classes = []string{class}
+	} else {
+		itemType, _ := h.repo.GetItemType(ctx, base.ItemType)
+		if restricted := d2.ResolveClassRestriction(base, itemType); restricted != "" {
+			classes = []string{restricted}
+		}
+	}
+
+	for _, name := range classes {
+		bps, err := d2.ComputeIASBreakpoints(base, name)
+		if err != nil {
+			// e.g. warlock, or claws for anyone but assassins
+			continue
+		}
+		entry := dto.ClassIASBreakpoints{
+			Class:       name,
+			Breakpoints: make([]dto.BreakpointEntry, 0, len(bps)),
+		}
+		for _, bp := range bps {
+			if bp.Value <= ias {
+				entry.CurrentFrames = bp.Frames
+			} else if entry.Next == nil {
+				entry.Next = &dto.BreakpointEntry{Value: bp.Value, Frames: bp.Frames}
+			}
+			entry.Breakpoints = append(entry.Breakpoints, dto.BreakpointEntry{Value: bp.Value, Frames: bp.Frames})
+		}
+		result.Classes = append(result.Classes, entry)
 	}
 
-	item, err := h.repo.GetItemBase(c.Context(), id)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Base item not found",
-			Code:    404,
-		})
+	if len(result.Classes) == 0 {
+		result.Applicable = false
+		result.Reason = "Not applicable: no known " + result.WeaponClass + " attack animation for the requested class"
 	}
 
-	// Get item type info
-	itemType, _ := h.repo.GetItemType(c.Context(), item.ItemType)
-
-	detail := h.convertBaseToDTO(item, itemType)
-
-	return c.JSON(dto.UnifiedItemDetail{
-		ItemType: "base",
-		Base:     detail,
-	})
+	return c.JSON(result)
 }
 
 // GetItem handles generic item detail requests by type and ID
@@ -342,137 +1278,129 @@ func (h *ItemHandler) GetItem(c *fiber.Ctx) error {
 	itemType := strings.ToLower(c.Params("type"))
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
+	var load itemDetailLoader
 	switch itemType {
 	case "unique":
-		item, err := h.repo.GetUniqueItem(c.Context(), id)
-		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+		load = h.loadUniqueDetail
+	case "set":
+		load = h.loadSetItemDetail
+	case "runeword":
+		load = h.loadRunewordDetail
+	case "rune":
+		load = h.loadRuneDetail
+	case "gem":
+		load = h.loadGemDetail
+	case "base":
+		load = h.loadBaseDetail
+	case "quest":
+		item, err := h.repo.GetItemBase(c.UserContext(), id)
+		if err != nil || !item.QuestItem {
+			return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Quest item not found")
 		}
-		base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "unique",
-			Unique:   h.convertUniqueToDTO(item, base),
+		return respondItemDetail(c, dto.UnifiedItemDetail{
+			ItemType: "quest",
+			Quest:    h.convertQuestToDTO(item),
 		})
 
+	default:
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest")
+	}
+
+	detail, err := h.cachedItemDetail(c.UserContext(), itemType, id, load)
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Item not found")
+	}
+
+	return respondItemDetail(c, *detail)
+}
+
+// lookupItemName returns the display name of an item by type and ID.
+// ok is false when the item type is not recognized.
+func (h *ItemHandler) lookupItemName(ctx context.Context, itemType string, id int) (name string, ok bool, err error) {
+	switch itemType {
+	case "unique":
+		item, err := h.repo.GetUniqueItem(ctx, id)
+		if err != nil {
+			return "", true, err
+		}
+		return item.Name, true, nil
 	case "set":
-		item, err := h.repo.GetSetItem(c.Context(), id)
+		item, err := h.repo.GetSetItem(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+			return "", true, err
 		}
-		base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "set",
-			SetItem:  h.convertSetItemToDTO(item, base),
-		})
-
+		return item.Name, true, nil
 	case "runeword":
-		item, err := h.repo.GetRuneword(c.Context(), id)
+		item, err := h.repo.GetRuneword(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+			return "", true, err
 		}
-		bases, _ := h.repo.GetBasesForRuneword(c.Context(), id)
-		runeInfoMap, _ := h.repo.GetRunesByCodes(c.Context(), item.Runes)
-		typeInfoMap, _ := h.repo.GetItemTypesByCodes(c.Context(), item.ValidItemTypes)
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "runeword",
-			Runeword: h.convertRunewordToDTO(item, bases, runeInfoMap, typeInfoMap),
-		})
-
+		return item.DisplayName, true, nil
 	case "rune":
-		item, err := h.repo.GetRune(c.Context(), id)
+		item, err := h.repo.GetRune(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+			return "", true, err
 		}
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "rune",
-			Rune:     h.convertRuneToDTO(item),
-		})
-
+		return item.Name, true, nil
 	case "gem":
-		item, err := h.repo.GetGem(c.Context(), id)
+		item, err := h.repo.GetGem(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+			return "", true, err
 		}
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "gem",
-			Gem:      h.convertGemToDTO(item),
-		})
-
-	case "base":
-		item, err := h.repo.GetItemBase(c.Context(), id)
+		return item.Name, true, nil
+	case "base", "quest":
+		item, err := h.repo.GetItemBase(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Item not found",
-				Code:    404,
-			})
+			return "", true, err
 		}
-		itemTypeInfo, _ := h.repo.GetItemType(c.Context(), item.ItemType)
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "base",
-			Base:     h.convertBaseToDTO(item, itemTypeInfo),
-		})
-
-	case "quest":
-		item, err := h.repo.GetItemBase(c.Context(), id)
-		if err != nil || !item.QuestItem {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "not_found",
-				Message: "Quest item not found",
-				Code:    404,
-			})
+		if itemType == "quest" && !item.QuestItem {
+			return "", true, fmt.Errorf("item %d is not a quest item", id)
 		}
-		return c.JSON(dto.UnifiedItemDetail{
-			ItemType: "quest",
-			Quest:    h.convertQuestToDTO(item),
-		})
+		return item.Name, true, nil
+	}
+	return "", false, nil
+}
 
-	default:
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest",
-			Code:    400,
-		})
+// GetItemPlaceholder returns a deterministic identicon-style PNG for an item,
+// so items without an icon still render a stable, distinct tile
+// GET /api/d2/items/:type/:id/placeholder.png
+func (h *ItemHandler) GetItemPlaceholder(c *fiber.Ctx) error {
+	itemType := strings.ToLower(c.Params("type"))
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
+	}
+
+	name, ok, err := h.lookupItemName(c.UserContext(), itemType, id)
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item type. Must be one of: unique, set, runeword, rune, gem, base, quest")
+	}
+	if err != nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Item not found")
 	}
+
+	data, err := d2.GeneratePlaceholderPNG(itemType, name)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to generate placeholder")
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(data)
 }
 
 // GetAllRunes returns all runes ordered by rune number
 // GET /api/d2/runes
 func (h *ItemHandler) GetAllRunes(c *fiber.Ctx) error {
-	runes, err := h.repo.GetAllRunes(c.Context())
+	if h.notModified(c, "runes") {
+		return nil
+	}
+
+	runes, err := h.repo.GetAllRunes(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get runes",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runes")
 	}
 
 	results := make([]*dto.RuneDetail, 0, len(runes))
@@ -486,13 +1414,13 @@ func (h *ItemHandler) GetAllRunes(c *fiber.Ctx) error {
 // GetAllGems returns all gems ordered by quality and type
 // GET /api/d2/gems
 func (h *ItemHandler) GetAllGems(c *fiber.Ctx) error {
-	gems, err := h.repo.GetAllGems(c.Context())
+	if h.notModified(c, "gems") {
+		return nil
+	}
+
+	gems, err := h.repo.GetAllGems(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get gems",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get gems")
 	}
 
 	results := make([]*dto.GemDetail, 0, len(gems))
@@ -503,39 +1431,182 @@ func (h *ItemHandler) GetAllGems(c *fiber.Ctx) error {
 	return c.JSON(results)
 }
 
-// GetAllBases returns all base items, optionally filtered by category or runeword
-// GET /api/d2/bases?category=armor|weapon|misc&runeword=5
+// GetAllSocketables returns runes and gems in one list with their per-slot
+// mods. When slot is given, only that slot's mods are returned.
+// GET /api/d2/socketables?slot=weapon|helm|shield
+func (h *ItemHandler) GetAllSocketables(c *fiber.Ctx) error {
+	slot := strings.ToLower(c.Query("slot"))
+	if slot != "" && slot != "weapon" && slot != "helm" && slot != "shield" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid slot. Must be one of: weapon, helm, shield")
+	}
+
+	runes, err := h.repo.GetAllRunes(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runes")
+	}
+	gems, err := h.repo.GetAllGems(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get gems")
+	}
+
+	results := make([]*dto.SocketableItem, 0, len(runes)+len(gems))
+	for _, r := range runes {
+		rn := h.convertRuneToDTO(&r)
+		results = append(results, &dto.SocketableItem{
+			ID:         rn.ID,
+			Kind:       "rune",
+			Code:       rn.Code,
+			Name:       rn.Name,
+			LevelReq:   rn.Requirements.Level,
+			WeaponMods: rn.WeaponMods,
+			ArmorMods:  rn.ArmorMods,
+			ShieldMods: rn.ShieldMods,
+			ImageURL:   rn.ImageURL,
+			HasImage:   rn.HasImage,
+		})
+	}
+	for _, g := range gems {
+		gem := h.convertGemToDTO(&g)
+		kind := "gem"
+		if gem.IsSkull {
+			kind = "skull"
+		}
+		results = append(results, &dto.SocketableItem{
+			ID:         gem.ID,
+			Kind:       kind,
+			Code:       gem.Code,
+			Name:       gem.Name,
+			WeaponMods: gem.WeaponMods,
+			ArmorMods:  gem.ArmorMods,
+			ShieldMods: gem.ShieldMods,
+			ImageURL:   gem.ImageURL,
+			HasImage:   gem.HasImage,
+		})
+	}
+
+	if slot == "" {
+		return c.JSON(results)
+	}
+
+	// Keep only the requested slot's mods, dropping socketables with none
+	filtered := make([]*dto.SocketableItem, 0, len(results))
+	for _, item := range results {
+		switch slot {
+		case "weapon":
+			item.ArmorMods, item.ShieldMods = nil, nil
+		case "helm":
+			item.WeaponMods, item.ShieldMods = nil, nil
+		case "shield":
+			item.WeaponMods, item.ArmorMods = nil, nil
+		}
+		if len(item.WeaponMods)+len(item.ArmorMods)+len(item.ShieldMods) == 0 {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+
+	return c.JSON(filtered)
+}
+
+// GetBreakpoint returns the breakpoint reached and the next one for a class's
+// speed stat (FCR or FHR), along with the full table.
+// GET /api/d2/breakpoints?class=sorceress&stat=fcr&current=63
+func (h *ItemHandler) GetBreakpoint(c *fiber.Ctx) error {
+	class := c.Query("class")
+	stat := c.Query("stat")
+	if class == "" || stat == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Query parameters 'class' and 'stat' are required")
+	}
+
+	current, err := strconv.Atoi(c.Query("current", "0"))
+	if err != nil || current < 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid current value")
+	}
+
+	tables := h.config.Breakpoints
+	if tables == nil {
+		tables = d2.DefaultBreakpointTables
+	}
+
+	table, err := tables.Table(class, stat)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, err.Error())
+	}
+	reached, next, _ := tables.Next(class, stat, current)
+
+	result := dto.BreakpointResponse{
+		Class:         d2.NormalizeClassName(class),
+		Stat:          d2.StatCodeGroup(stat)[0],
+		Current:       current,
+		CurrentFrames: reached.Frames,
+		Breakpoints:   make([]dto.BreakpointEntry, 0, len(table)),
+	}
+	if next != nil {
+		result.Next = &dto.BreakpointEntry{Value: next.Value, Frames: next.Frames}
+	}
+	for _, bp := range table {
+		result.Breakpoints = append(result.Breakpoints, dto.BreakpointEntry{Value: bp.Value, Frames: bp.Frames})
+	}
+
+	return c.JSON(result)
+}
+
+// GetAllBases returns all base items, optionally filtered by category, runeword,
+// class restriction, usable class or max socket count. class= lists only that
+// class's own bases; usable_by= adds the unrestricted ones. Quest items and
+// placeholder rows are excluded unless include_quest=true or
+// include_placeholders=true.
+// GET /api/d2/bases?category=armor|weapon|misc&runeword=5&class=sorceress&usable_by=sorceress&min_sockets=4&max_sockets=4&include_quest=true&include_placeholders=true
 func (h *ItemHandler) GetAllBases(c *fiber.Ctx) error {
 	category := c.Query("category")
 	runewordIDStr := c.Query("runeword")
 
+	classOnly, ok, err := h.parseClassFilter(c, classRestricted)
+	if !ok {
+		return invalidClassFilter(c, classRestricted, err)
+	}
+
+	usable, ok, err := h.parseClassFilter(c, classUsable)
+	if !ok {
+		return invalidClassFilter(c, classUsable, err)
+	}
+
 	// Validate category if provided
 	if category != "" && category != "armor" && category != "weapon" && category != "misc" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid category. Must be one of: armor, weapon, misc",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid category. Must be one of: armor, weapon, misc")
+	}
+
+	sockets, ok := parseSocketRange(c)
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid socket range. min_sockets and max_sockets must be 0-6 with min <= max")
+	}
+
+	if h.notModified(c, "item_bases", "runewords", "item_types") {
+		return nil
 	}
 
 	// If runeword filter is provided, return bases for that runeword
 	if runewordIDStr != "" {
 		runewordID, err := strconv.Atoi(runewordIDStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-				Error:   "bad_request",
-				Message: "Invalid runeword ID",
-				Code:    400,
-			})
+			return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid runeword ID")
 		}
 
-		runewordBases, err := h.repo.GetBasesForRuneword(c.Context(), runewordID)
+		runewordBases, err := h.repo.GetBasesForRuneword(c.UserContext(), runewordID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Failed to get base items for runeword",
-				Code:    500,
-			})
+			return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get base items for runeword")
+		}
+
+		// The class filters need each base's class tag and item type
+		var bases map[string]*d2.ItemBase
+		var itemTypes map[string]*d2.ItemType
+		if classOnly.class != "" || usable.class != "" {
+			codes := make([]string, 0, len(runewordBases))
+			for _, rb := range runewordBases {
+				codes = append(codes, rb.ItemBaseCode)
+			}
+			bases, _ = h.repo.GetItemBasesByCodes(c.UserContext(), codes)
+			itemTypes = h.loadItemTypes(c.UserContext(), bases)
 		}
 
 		results := make([]*dto.BaseItemDetail, 0, len(runewordBases))
@@ -544,6 +1615,19 @@ func (h *ItemHandler) GetAllBases(c *fiber.Ctx) error {
 			if category != "" && rb.Category != category {
 				continue
 			}
+			if sockets != nil && (rb.MaxSockets < sockets.Min || rb.MaxSockets > sockets.Max) {
+				continue
+			}
+			if classOnly.class != "" || usable.class != "" {
+				base, ok := bases[rb.ItemBaseCode]
+				if !ok {
+					continue
+				}
+				restricted, _ := resolveClassRestriction(base, itemTypeOf(itemTypes, base))
+				if !classOnly.matches(restricted) || !usable.matches(restricted) {
+					continue
+				}
+			}
 			results = append(results, &dto.BaseItemDetail{
 				ID:         rb.ItemBaseID,
 				Code:       rb.ItemBaseCode,
@@ -557,76 +1641,172 @@ func (h *ItemHandler) GetAllBases(c *fiber.Ctx) error {
 		return c.JSON(results)
 	}
 
-	bases, err := h.repo.GetAllItemBases(c.Context(), category)
+	bases, err := h.repo.GetAllItemBases(c.UserContext(), d2.ItemBaseListOptions{
+		Category:     category,
+		IncludeQuest: c.QueryBool("include_quest", false),
+		Sockets:      sockets,
+		UsableBy:     usable.class,
+
+		IncludePlaceholders: c.QueryBool("include_placeholders", false),
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get base items",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get base items")
+	}
+
+	typeCodes := make([]string, 0, len(bases))
+	for _, b := range bases {
+		typeCodes = append(typeCodes, b.ItemType)
 	}
+	// A failed lookup leaves itemTypes nil, same as a missing type per base
+	itemTypes, _ := h.repo.GetFullItemTypesByCodes(c.UserContext(), typeCodes)
 
 	results := make([]*dto.BaseItemDetail, 0, len(bases))
 	for _, b := range bases {
-		itemType, _ := h.repo.GetItemType(c.Context(), b.ItemType)
-		results = append(results, h.convertBaseToDTO(&b, itemType))
+		detail := h.convertBaseToDTO(&b, itemTypes[b.ItemType])
+		// The query only sees class_specific; the item type can restrict too
+		if !classOnly.matches(detail.ClassSpecific) || !usable.matches(detail.ClassSpecific) {
+			continue
+		}
+		results = append(results, detail)
 	}
 
 	return c.JSON(results)
 }
 
-// GetAllUniques returns all unique items
-// GET /api/d2/uniques
+// GetAllUniques returns all enabled unique items, optionally filtered by class
+// restriction, usable class and ladder availability. Disabled uniques are
+// only listed by GetAllUniquesAdmin.
+// GET /api/d2/uniques?class=sorceress&usable_by=sorceress&ladder=true&season=<n>
 func (h *ItemHandler) GetAllUniques(c *fiber.Ctx) error {
-	items, err := h.repo.GetAllUniqueItems(c.Context())
+	if !c.QueryBool("enabled", true) {
+		return respondError(c, fiber.StatusForbidden, dto.ErrCodeForbidden, "Disabled uniques are only listed by the admin API")
+	}
+	return h.listUniques(c, false)
+}
+
+// GetAllUniquesAdmin returns unique items like GetAllUniques; enabled=false
+// also lists disabled ones
+// GET /admin/d2/uniques?enabled=false&class=sorceress
+func (h *ItemHandler) GetAllUniquesAdmin(c *fiber.Ctx) error {
+	return h.listUniques(c, !c.QueryBool("enabled", true))
+}
+
+func (h *ItemHandler) listUniques(c *fiber.Ctx, includeDisabled bool) error {
+	classOnly, ok, err := h.parseClassFilter(c, classRestricted)
+	if !ok {
+		return invalidClassFilter(c, classRestricted, err)
+	}
+
+	usable, ok, err := h.parseClassFilter(c, classUsable)
+	if !ok {
+		return invalidClassFilter(c, classUsable, err)
+	}
+
+	ladder, ok := parseLadderFilter(c)
+	if !ok {
+		return invalidLadderFilter(c)
+	}
+
+	if h.notModified(c, "unique_items", "item_bases", "item_types") {
+		return nil
+	}
+
+	items, err := h.repo.GetAllUniqueItems(c.UserContext(), d2.UniqueListOptions{
+		Ladder:          ladder,
+		IncludeDisabled: includeDisabled,
+		UsableBy:        usable.class,
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get unique items",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get unique items")
 	}
 
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		codes = append(codes, item.BaseCode)
+	}
+	// A failed lookup leaves bases nil, same as a missing base per item
+	bases, _ := h.repo.GetItemBasesByCodes(c.UserContext(), codes)
+	itemTypes := h.loadItemTypes(c.UserContext(), bases)
+
 	results := make([]*dto.UniqueItemDetail, 0, len(items))
 	for _, item := range items {
-		base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
-		results = append(results, h.convertUniqueToDTO(&item, base))
+		base := bases[item.BaseCode]
+		detail := h.convertUniqueToDTO(&item, base, itemTypeOf(itemTypes, base))
+		if !classOnly.matches(detail.Base.ClassSpecific) || !usable.matches(detail.Base.ClassSpecific) {
+			continue
+		}
+		results = append(results, detail)
 	}
 
 	return c.JSON(results)
 }
 
-// GetAllSets returns all set items
-// GET /api/d2/sets
+// GetAllSets returns all set items, optionally filtered by class restriction.
+// Set items have no ladder restriction, so ladder=true returns nothing.
+// GET /api/d2/sets?class=sorceress&ladder=false
 func (h *ItemHandler) GetAllSets(c *fiber.Ctx) error {
-	items, err := h.repo.GetAllSetItems(c.Context())
+	classOnly, ok, err := h.parseClassFilter(c, classRestricted)
+	if !ok {
+		return invalidClassFilter(c, classRestricted, err)
+	}
+
+	ladder, ok := parseLadderFilter(c)
+	if !ok {
+		return invalidLadderFilter(c)
+	}
+
+	// Set items are never ladder-only and exist in every season
+	if ladder.Ladder != nil && *ladder.Ladder {
+		return c.JSON([]*dto.SetItemDetail{})
+	}
+
+	if h.notModified(c, "set_items", "item_bases", "item_types") {
+		return nil
+	}
+
+	items, err := h.repo.GetAllSetItems(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get set items",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get set items")
+	}
+
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		codes = append(codes, item.BaseCode)
 	}
+	// A failed lookup leaves bases nil, same as a missing base per item
+	bases, _ := h.repo.GetItemBasesByCodes(c.UserContext(), codes)
+	itemTypes := h.loadItemTypes(c.UserContext(), bases)
+	setNames, _ := h.repo.GetSetItemNames(c.UserContext())
 
 	results := make([]*dto.SetItemDetail, 0, len(items))
 	for _, item := range items {
-		base, _ := h.repo.GetItemBaseByCode(c.Context(), item.BaseCode)
-		results = append(results, h.convertSetItemToDTO(&item, base))
+		base := bases[item.BaseCode]
+		detail := h.convertSetItemToDTO(&item, base, itemTypeOf(itemTypes, base))
+		setSiblings(detail, setNames)
+		if !classOnly.matches(detail.Base.ClassSpecific) {
+			continue
+		}
+		results = append(results, detail)
 	}
 
 	return c.JSON(results)
 }
 
-// GetAllRunewords returns all runewords
-// GET /api/d2/runewords
+// GetAllRunewords returns all runewords, optionally filtered by ladder status
+// GET /api/d2/runewords?ladder=true&season=<n>
 func (h *ItemHandler) GetAllRunewords(c *fiber.Ctx) error {
-	items, err := h.repo.GetAllRunewordsForList(c.Context())
+	ladder, ok := parseLadderFilter(c)
+	if !ok {
+		return invalidLadderFilter(c)
+	}
+
+	if h.notModified(c, "runewords", "runes", "item_types") {
+		return nil
+	}
+
+	items, err := h.repo.GetAllRunewordsForList(c.UserContext(), ladder)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get runewords",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get runewords")
 	}
 
 	// Collect all rune codes and type codes for batch lookup
@@ -638,8 +1818,8 @@ func (h *ItemHandler) GetAllRunewords(c *fiber.Ctx) error {
 	}
 
 	// Batch fetch rune and type info
-	runeInfoMap, _ := h.repo.GetRunesByCodes(c.Context(), allRuneCodes)
-	typeInfoMap, _ := h.repo.GetItemTypesByCodes(c.Context(), allTypeCodes)
+	runeInfoMap, _ := h.repo.GetRunesByCodes(c.UserContext(), allRuneCodes)
+	typeInfoMap, _ := h.repo.GetItemTypesByCodes(c.UserContext(), allTypeCodes)
 
 	results := make([]*dto.RunewordDetail, 0, len(items))
 	for _, item := range items {
@@ -652,7 +1832,7 @@ func (h *ItemHandler) GetAllRunewords(c *fiber.Ctx) error {
 
 // Helper methods for DTO conversion
 
-func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase) *dto.UniqueItemDetail {
+func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase, itemType *d2.ItemType) *dto.UniqueItemDetail {
 	detail := &dto.UniqueItemDetail{
 		ID:     item.ID,
 		Name:   item.Name,
@@ -662,8 +1842,9 @@ func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase)
 			Level: item.LevelReq,
 		},
 		LadderOnly: item.LadderOnly,
-		ImageURL:   item.ImageURL,
+		Disabled:   !item.Enabled,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "unique")
 
 	// Add base info if available
 	if base != nil {
@@ -671,8 +1852,9 @@ func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase)
 			Code:     base.Code,
 			Name:     base.Name,
 			Category: capitalize(base.Category),
-			ItemType: h.resolveItemTypeName(base.ItemType),
+			ItemType: resolveItemTypeName(base.ItemType, itemType),
 		}
+		detail.Base.ClassSpecific, detail.Base.ClassRestriction = resolveClassRestriction(base, itemType)
 		if base.MaxAC > 0 {
 			detail.Base.Defense = &dto.DefenseRange{
 				Min: base.MinAC,
@@ -685,8 +1867,9 @@ func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase)
 		}
 		detail.Base.MaxSockets = base.MaxSockets
 		detail.Base.Durability = base.Durability
-		detail.Requirements.Strength = base.StrReq
-		detail.Requirements.Dexterity = base.DexReq
+		detail.Requirements.Strength = d2.ApplyRequirementReduction(base.StrReq, item.Properties)
+		detail.Requirements.Dexterity = d2.ApplyRequirementReduction(base.DexReq, item.Properties)
+		detail.VendorValue = d2.ComputeItemCost(base.Cost, item.CostMult, item.CostAdd)
 	} else if item.BaseName != "" {
 		detail.Base = dto.ItemBaseInfo{
 			Name: item.BaseName,
@@ -699,7 +1882,7 @@ func (h *ItemHandler) convertUniqueToDTO(item *d2.UniqueItem, base *d2.ItemBase)
 	return detail
 }
 
-func (h *ItemHandler) convertSetItemToDTO(item *d2.SetItem, base *d2.ItemBase) *dto.SetItemDetail {
+func (h *ItemHandler) convertSetItemToDTO(item *d2.SetItem, base *d2.ItemBase, itemType *d2.ItemType) *dto.SetItemDetail {
 	detail := &dto.SetItemDetail{
 		ID:      item.ID,
 		Name:    item.Name,
@@ -709,8 +1892,8 @@ func (h *ItemHandler) convertSetItemToDTO(item *d2.SetItem, base *d2.ItemBase) *
 		Requirements: dto.ItemRequirements{
 			Level: item.LevelReq,
 		},
-		ImageURL: item.ImageURL,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "set")
 
 	// Add base info if available
 	if base != nil {
@@ -718,8 +1901,9 @@ func (h *ItemHandler) convertSetItemToDTO(item *d2.SetItem, base *d2.ItemBase) *
 			Code:     base.Code,
 			Name:     base.Name,
 			Category: capitalize(base.Category),
-			ItemType: h.resolveItemTypeName(base.ItemType),
+			ItemType: resolveItemTypeName(base.ItemType, itemType),
 		}
+		detail.Base.ClassSpecific, detail.Base.ClassRestriction = resolveClassRestriction(base, itemType)
 		if base.MaxAC > 0 {
 			detail.Base.Defense = &dto.DefenseRange{
 				Min: base.MinAC,
@@ -732,8 +1916,9 @@ func (h *ItemHandler) convertSetItemToDTO(item *d2.SetItem, base *d2.ItemBase) *
 		}
 		detail.Base.MaxSockets = base.MaxSockets
 		detail.Base.Durability = base.Durability
-		detail.Requirements.Strength = base.StrReq
-		detail.Requirements.Dexterity = base.DexReq
+		detail.Requirements.Strength = d2.ApplyRequirementReduction(base.StrReq, item.Properties)
+		detail.Requirements.Dexterity = d2.ApplyRequirementReduction(base.DexReq, item.Properties)
+		detail.VendorValue = d2.ComputeItemCost(base.Cost, item.CostMult, item.CostAdd)
 	} else if item.BaseName != "" {
 		detail.Base = dto.ItemBaseInfo{
 			Name: item.BaseName,
@@ -755,13 +1940,16 @@ func (h *ItemHandler) convertRunewordToDTO(item *d2.Runeword, bases []d2.Runewor
 		Type:        "Runeword",
 		Rarity:      "Runeword",
 		LadderOnly:  item.LadderOnly,
-		ImageURL:    item.ImageURL,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "runeword")
 
-	// Build runes with display info
+	// Build runes with display info, keeping the stored order: it is the
+	// socketing order and must never be sorted
 	detail.Runes = make([]dto.RunewordRune, 0, len(item.Runes))
-	for _, runeCode := range item.Runes {
+	detail.RuneSequence = make([]dto.RuneSequenceEntry, 0, len(item.Runes))
+	for i, runeCode := range item.Runes {
 		rune := dto.RunewordRune{Code: runeCode}
+		step := dto.RuneSequenceEntry{Position: i + 1, Code: runeCode, Name: runeCode}
 		if info, ok := runeInfoMap[runeCode]; ok {
 			rune.ID = info.ID
 			// Use short name (strip " Rune" suffix)
@@ -769,11 +1957,16 @@ func (h *ItemHandler) convertRunewordToDTO(item *d2.Runeword, bases []d2.Runewor
 			rune.Name = shortName
 			rune.ImageURL = info.ImageURL
 			detail.RuneOrder += shortName
+			step.RuneNumber = info.RuneNumber
+			step.Name = shortName
+			step.ImageURL = info.ImageURL
 		} else {
 			detail.RuneOrder += runeCode
 		}
 		detail.Runes = append(detail.Runes, rune)
+		detail.RuneSequence = append(detail.RuneSequence, step)
 	}
+	detail.RequiredSockets = len(detail.Runes)
 
 	// Build valid types with names
 	detail.ValidTypes = make([]dto.RunewordValidType, 0, len(item.ValidItemTypes))
@@ -795,11 +1988,12 @@ func (h *ItemHandler) convertRunewordToDTO(item *d2.Runeword, bases []d2.Runewor
 		detail.ValidBaseItems = make([]dto.RunewordBaseItem, 0, len(bases))
 		for _, b := range bases {
 			detail.ValidBaseItems = append(detail.ValidBaseItems, dto.RunewordBaseItem{
-				ID:         b.ItemBaseID,
-				Code:       b.ItemBaseCode,
-				Name:       b.ItemBaseName,
-				Category:   capitalize(b.Category),
-				MaxSockets: b.MaxSockets,
+				ID:              b.ItemBaseID,
+				Code:            b.ItemBaseCode,
+				Name:            b.ItemBaseName,
+				Category:        capitalize(b.Category),
+				MaxSockets:      b.MaxSockets,
+				RequiredSockets: b.RequiredSockets,
 			})
 		}
 	}
@@ -818,8 +2012,9 @@ func (h *ItemHandler) convertRuneToDTO(item *d2.Rune) *dto.RuneDetail {
 		Requirements: dto.ItemRequirements{
 			Level: item.LevelReq,
 		},
-		ImageURL: item.ImageURL,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "rune")
+	detail.VendorValue = d2.ComputeItemCost(item.Cost, 0, 0)
 
 	// Convert mods
 	detail.WeaponMods = h.convertPropertiesToAffixes(item.WeaponMods)
@@ -829,17 +2024,46 @@ func (h *ItemHandler) convertRuneToDTO(item *d2.Rune) *dto.RuneDetail {
 	return detail
 }
 
+// setSiblings fills a set item's set size and the names of the other items
+// in its set from the enabled set item names keyed by lowercased set name.
+// A nil map (failed lookup) leaves the item without siblings.
+func setSiblings(detail *dto.SetItemDetail, setNames map[string][]string) {
+	names := setNames[strings.ToLower(detail.SetName)]
+	detail.SetItemCount = len(names)
+	detail.SetSiblings = make([]string, 0, len(names))
+	for _, name := range names {
+		if name != detail.Name {
+			detail.SetSiblings = append(detail.SetSiblings, name)
+		}
+	}
+}
+
+func (h *ItemHandler) convertSetBonusToDTO(bonus *d2.SetBonus, itemNames []string) *dto.SetBonusDetail {
+	if itemNames == nil {
+		itemNames = []string{}
+	}
+	return &dto.SetBonusDetail{
+		ID:             bonus.ID,
+		Name:           bonus.Name,
+		Items:          itemNames,
+		SetItemCount:   len(itemNames),
+		PartialBonuses: h.convertPropertiesToAffixes(bonus.PartialBonuses),
+		FullBonuses:    h.convertPropertiesToAffixes(bonus.FullBonuses),
+	}
+}
+
 func (h *ItemHandler) convertGemToDTO(item *d2.Gem) *dto.GemDetail {
 	detail := &dto.GemDetail{
-		ID:       item.ID,
-		Code:     item.Code,
-		Name:     item.Name,
-		GemType:  capitalize(item.GemType),
-		Quality:  capitalize(item.Quality),
-		Type:     "Gem",
-		Rarity:   "Gem",
-		ImageURL: item.ImageURL,
+		ID:      item.ID,
+		Code:    item.Code,
+		Name:    item.Name,
+		GemType: capitalize(item.GemType),
+		Quality: capitalize(item.Quality),
+		IsSkull: item.IsSkull || d2.IsSkull(item.GemType),
+		Type:    "Gem",
+		Rarity:  "Gem",
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "gem")
 
 	// Convert mods
 	detail.WeaponMods = h.convertPropertiesToAffixes(item.WeaponMods)
@@ -857,30 +2081,32 @@ func (h *ItemHandler) convertBaseToDTO(item *d2.ItemBase, itemType *d2.ItemType)
 		Type:     "Base",
 		Rarity:   "Normal",
 		Category: capitalize(item.Category),
-		Tier:          item.Tier,
-		TypeTags:      item.TypeTags,
-		ClassSpecific: item.ClassSpecific,
+		Tier:     item.Tier,
+		TypeTags: item.TypeTags,
 		Requirements: dto.ItemRequirements{
 			Level:     item.LevelReq,
 			Strength:  item.StrReq,
 			Dexterity: item.DexReq,
 		},
-		MaxSockets: item.MaxSockets,
-		Durability: item.Durability,
-		Speed:      item.Speed,
-		ImageURL:   item.ImageURL,
+		MaxSockets:  item.MaxSockets,
+		Durability:  item.Durability,
+		Speed:       item.Speed,
+		Placeholder: item.Placeholder,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "base")
+	detail.ClassSpecific, detail.ClassRestriction = resolveClassRestriction(item, itemType)
+	detail.VendorValue = d2.ComputeItemCost(item.Cost, 0, 0)
+
+	spawn := d2.ResolveQualitySpawn(item, itemType)
+	detail.CanBeMagic = spawn.CanBeMagic
+	detail.CanBeRare = spawn.CanBeRare
+	detail.CanBeUnique = spawn.CanBeUnique
 
 	if len(item.IconVariants) > 0 {
 		detail.IconVariants = item.IconVariants
 	}
 
-	// Set item type name from lookup
-	if itemType != nil {
-		detail.ItemType = h.resolveItemTypeName(itemType.Code)
-	} else {
-		detail.ItemType = h.resolveItemTypeName(item.ItemType)
-	}
+	detail.ItemType = resolveItemTypeName(item.ItemType, itemType)
 
 	// Defense for armor
 	if item.MinAC > 0 || item.MaxAC > 0 {
@@ -909,6 +2135,29 @@ func (h *ItemHandler) convertBaseToDTO(item *d2.ItemBase, itemType *d2.ItemType)
 		}
 	}
 
+	// Ethereal form, for bases that can be ethereal
+	if eth := d2.ComputeEtherealStats(item); eth != nil {
+		detail.CanBeEthereal = true
+		detail.Ethereal = &dto.EtherealStats{
+			Requirements: dto.ItemRequirements{
+				Level:     item.LevelReq,
+				Strength:  eth.StrReq,
+				Dexterity: eth.DexReq,
+			},
+		}
+		if detail.Defense != nil {
+			detail.Ethereal.Defense = &dto.DefenseRange{Min: eth.MinAC, Max: eth.MaxAC}
+		}
+		if detail.Damage != nil {
+			detail.Ethereal.Damage = &dto.DamageRange{
+				OneHandMin: eth.MinDam,
+				OneHandMax: eth.MaxDam,
+				TwoHandMin: eth.TwoHandMinDam,
+				TwoHandMax: eth.TwoHandMaxDam,
+			}
+		}
+	}
+
 	return detail
 }
 
@@ -918,8 +2167,9 @@ func (h *ItemHandler) convertPropertiesToAffixes(props []d2.Property) []dto.Item
 		name := prop.DisplayText
 		hasRange := prop.HasRange
 
-		// Fallback for old data without pre-computed values (remove after re-import)
-		if name == "" {
+		// Fallback for old data without pre-computed values (remove after re-import).
+		// Pre-computed text is English, so other locales always translate.
+		if name == "" || h.translator.Locale() != d2.DefaultLocale {
 			name = h.translator.Translate(prop)
 			hasRange = prop.Min != prop.Max
 		}
@@ -948,6 +2198,9 @@ func (h *ItemHandler) convertPropertiesToAffixes(props []d2.Property) []dto.Item
 			affix.MinValue = &min
 			affix.MaxValue = &max
 		}
+		if len(prop.Alternatives) > 0 {
+			affix.Alternatives = h.convertPropertiesToAffixes(prop.Alternatives)
+		}
 		affixes = append(affixes, affix)
 	}
 	return affixes
@@ -956,7 +2209,7 @@ func (h *ItemHandler) convertPropertiesToAffixes(props []d2.Property) []dto.Item
 // GetAllStats returns all filterable stat codes for marketplace filtering
 // GET /api/d2/stats
 func (h *ItemHandler) GetAllStats(c *fiber.Ctx) error {
-	stats, err := h.repo.GetAllStats(c.Context())
+	stats, err := h.repo.GetAllStats(c.UserContext())
 	if err != nil {
 		// Fallback to hardcoded stats if DB query fails
 		hardcoded := d2.FilterableStats()
@@ -989,6 +2242,91 @@ func (h *ItemHandler) GetAllStats(c *fiber.Ctx) error {
 	return c.JSON(results)
 }
 
+// GetStatCategories returns the stat categories in canonical display order
+// along with the number of stats in each
+// GET /api/d2/stats/categories
+func (h *ItemHandler) GetStatCategories(c *fiber.Ctx) error {
+	counts := make(map[string]int)
+	stats, err := h.repo.GetAllStats(c.UserContext())
+	if err != nil {
+		// Fallback to hardcoded stats if DB query fails
+		for _, s := range d2.FilterableStats() {
+			counts[s.Category]++
+		}
+	} else {
+		for _, s := range stats {
+			counts[s.Category]++
+		}
+	}
+
+	results := make([]dto.StatCategory, 0, len(d2.StatCategories))
+	for i, name := range d2.StatCategories {
+		results = append(results, dto.StatCategory{
+			Name:      name,
+			SortIndex: i,
+			Icon:      d2.StatCategoryIcon(name),
+			StatCount: counts[name],
+		})
+	}
+
+	return c.JSON(results)
+}
+
+// GetStatRange returns the lowest and highest values a stat takes across
+// stored items, so range sliders span the real data. Results are cached until
+// the next import.
+// GET /api/d2/stats/:code/range
+func (h *ItemHandler) GetStatRange(c *fiber.Ctx) error {
+	code := d2.StatCodeGroup(strings.ToLower(c.Params("code")))[0]
+	if code == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Stat code is required")
+	}
+
+	sr, err := h.statRange(c.UserContext(), code)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get stat range")
+	}
+
+	result := dto.StatRange{Code: code}
+	if sr != nil {
+		result.Min, result.Max, result.Count = &sr.Min, &sr.Max, sr.Count
+	}
+	return c.JSON(result)
+}
+
+// statRange returns a stat's observed range, served from the cache while the
+// catalog hasn't been re-imported
+func (h *ItemHandler) statRange(ctx context.Context, code string) (*d2.StatRange, error) {
+	updatedAt, err := h.repo.GetCatalogUpdatedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := h.statRanges
+	cache.mu.Lock()
+	if !cache.updatedAt.Equal(updatedAt) {
+		cache.updatedAt = updatedAt
+		cache.ranges = make(map[string]*d2.StatRange)
+	}
+	sr, ok := cache.ranges[code]
+	cache.mu.Unlock()
+	if ok {
+		return sr, nil
+	}
+
+	sr, err = h.repo.GetStatObservedRange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	if cache.updatedAt.Equal(updatedAt) {
+		cache.ranges[code] = sr
+	}
+	cache.mu.Unlock()
+	return sr, nil
+}
+
 // GetAllCategories returns all item categories for marketplace filtering
 // GET /api/d2/categories
 func (h *ItemHandler) GetAllCategories(c *fiber.Ctx) error {
@@ -1006,6 +2344,57 @@ func (h *ItemHandler) GetAllCategories(c *fiber.Ctx) error {
 	return c.JSON(results)
 }
 
+// GetAllItemTypes returns every item type with its resolved parent chain
+// GET /api/d2/item-types
+func (h *ItemHandler) GetAllItemTypes(c *fiber.Ctx) error {
+	types, err := h.repo.GetAllItemTypes(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get item types")
+	}
+
+	hierarchy := d2.NewItemTypeHierarchy(types)
+	results := make([]dto.ItemTypeInfo, 0, len(types))
+	for i := range types {
+		results = append(results, convertItemTypeToDTO(&types[i], hierarchy))
+	}
+
+	return c.JSON(results)
+}
+
+// GetItemTypeByCode returns one item type with its parent chain and direct
+// children
+// GET /api/d2/item-types/:code
+func (h *ItemHandler) GetItemTypeByCode(c *fiber.Ctx) error {
+	types, err := h.repo.GetAllItemTypes(c.UserContext())
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get item types")
+	}
+
+	hierarchy := d2.NewItemTypeHierarchy(types)
+	it := hierarchy.Get(c.Params("code"))
+	if it == nil {
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Item type not found")
+	}
+
+	result := convertItemTypeToDTO(it, hierarchy)
+	result.Children = hierarchy.Children(it.Code)
+	return c.JSON(result)
+}
+
+func convertItemTypeToDTO(it *d2.ItemType, hierarchy *d2.ItemTypeHierarchy) dto.ItemTypeInfo {
+	return dto.ItemTypeInfo{
+		Code:    it.Code,
+		Name:    it.Name,
+		Parents: hierarchy.Ancestors(it.Code),
+		MaxSockets: dto.ItemTypeSockets{
+			Normal:    it.MaxSocketsNormal,
+			Nightmare: it.MaxSocketsNightmare,
+			Hell:      it.MaxSocketsHell,
+		},
+		ClassRestriction: it.ClassRestriction,
+	}
+}
+
 // GetAllRarities returns all item rarities for marketplace filtering
 // GET /api/d2/rarities
 func (h *ItemHandler) GetAllRarities(c *fiber.Ctx) error {
@@ -1029,23 +2418,15 @@ func (h *ItemHandler) GetAllRarities(c *fiber.Ctx) error {
 func (h *ItemHandler) GetQuestItem(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Invalid item ID",
-			Code:    400,
-		})
+		return respondError(c, fiber.StatusBadRequest, dto.ErrCodeBadRequest, "Invalid item ID")
 	}
 
-	item, err := h.repo.GetItemBase(c.Context(), id)
+	item, err := h.repo.GetItemBase(c.UserContext(), id)
 	if err != nil || !item.QuestItem {
-		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-			Error:   "not_found",
-			Message: "Quest item not found",
-			Code:    404,
-		})
+		return respondError(c, fiber.StatusNotFound, dto.ErrCodeNotFound, "Quest item not found")
 	}
 
-	return c.JSON(dto.UnifiedItemDetail{
+	return respondItemDetail(c, dto.UnifiedItemDetail{
 		ItemType: "quest",
 		Quest:    h.convertQuestToDTO(item),
 	})
@@ -1054,13 +2435,9 @@ func (h *ItemHandler) GetQuestItem(c *fiber.Ctx) error {
 // GetAllQuestItems returns all quest items
 // GET /api/d2/quests
 func (h *ItemHandler) GetAllQuestItems(c *fiber.Ctx) error {
-	items, err := h.repo.GetAllQuestItems(c.Context())
+	items, err := h.repo.GetAllQuestItems(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get quest items",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get quest items")
 	}
 
 	results := make([]*dto.QuestItemDetail, 0, len(items))
@@ -1074,13 +2451,9 @@ func (h *ItemHandler) GetAllQuestItems(c *fiber.Ctx) error {
 // GetAllClasses returns all character classes
 // GET /api/d2/classes
 func (h *ItemHandler) GetAllClasses(c *fiber.Ctx) error {
-	classes, err := h.repo.GetAllClasses(c.Context())
+	classes, err := h.repo.GetAllClasses(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to get classes",
-			Code:    500,
-		})
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrCodeInternal, "Failed to get classes")
 	}
 
 	results := make([]dto.ClassDetail, 0, len(classes))
@@ -1092,15 +2465,16 @@ func (h *ItemHandler) GetAllClasses(c *fiber.Ctx) error {
 }
 
 func (h *ItemHandler) convertQuestToDTO(item *d2.ItemBase) *dto.QuestItemDetail {
-	return &dto.QuestItemDetail{
+	detail := &dto.QuestItemDetail{
 		ID:          item.ID,
 		Code:        item.Code,
 		Name:        item.Name,
 		Description: item.Description,
 		Type:        "Quest",
 		Rarity:      "Quest",
-		ImageURL:    item.ImageURL,
 	}
+	detail.ImageURL, detail.HasImage = h.resolveImageURL(item.ImageURL, "quest")
+	return detail
 }
 
 func convertClassToDTO(cls *d2.Class) dto.ClassDetail {
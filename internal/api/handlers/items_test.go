@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// berRow is Ber's row selected with the rune columns
+var berRow = []interface{}{
+	30, "r30", "Ber", 30, 63, 63,
+	dbtest.JSON([]d2.Property{{Code: "crush", Min: 20, Max: 20}}),
+	dbtest.JSON([]d2.Property{{Code: "red-dmg%", Min: 8, Max: 8}}),
+	dbtest.JSON([]d2.Property{{Code: "red-dmg%", Min: 8, Max: 8}}),
+	"invrBer", nil, 1, nil, nil,
+}
+
+// runewordRow is a row selected with the runeword columns
+func runewordRow(id int, name string, runes ...string) []interface{} {
+	return []interface{}{
+		id, "HTMLRuneword_" + name, name, true, false, nil, nil,
+		dbtest.JSON([]string{"weap"}), nil, dbtest.JSON(runes), dbtest.JSON([]d2.Property{}), nil,
+		nil, nil,
+	}
+}
+
+// getJSON serves one GET request and decodes the JSON response into out
+func getJSON(t *testing.T, app *fiber.App, url string, out interface{}) *http.Response {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	if out != nil {
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s response: %v", url, err)
+		}
+	}
+	return resp
+}
+
+func TestGetRuneFullBer(t *testing.T) {
+	db := dbtest.NewFake().
+		On("WHERE LOWER(code) = LOWER($1)", []interface{}{30}).
+		On("runes WHERE id = $1", berRow).
+		On("runes ? $1",
+			runewordRow(1, "Enigma", "r31", "r06", "r30"),
+			runewordRow(2, "Infinity", "r30", "r23", "r30", "r24"),
+		)
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/runes/:id/full", h.Localized((*ItemHandler).GetRuneFull))
+
+	var got dto.RuneFullDetail
+	resp := getJSON(t, app, "/runes/r30/full", &got)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	if got.Rune == nil || got.Rune.Name != "Ber" || got.Rune.Code != "r30" {
+		t.Fatalf("rune = %+v, want Ber (r30)", got.Rune)
+	}
+	mods := map[string][]dto.ItemAffix{
+		"weapon": got.Rune.WeaponMods,
+		"armor":  got.Rune.ArmorMods,
+		"shield": got.Rune.ShieldMods,
+	}
+	want := map[string]string{
+		"weapon": "20% Chance Of Crushing Blow",
+		"armor":  "Damage Reduced By 8%",
+		"shield": "Damage Reduced By 8%",
+	}
+	for slot, text := range want {
+		if len(mods[slot]) != 1 || mods[slot][0].Name != text {
+			t.Errorf("%s mods = %+v, want %q", slot, mods[slot], text)
+		}
+	}
+
+	if len(got.Runewords) != 2 {
+		t.Fatalf("got %d runewords, want 2", len(got.Runewords))
+	}
+	if got.Runewords[0].DisplayName != "Enigma" || got.Runewords[1].DisplayName != "Infinity" {
+		t.Errorf("runewords = %q, %q; want Enigma, Infinity", got.Runewords[0].DisplayName, got.Runewords[1].DisplayName)
+	}
+	if n := db.Count("runes ? $1"); n != 1 {
+		t.Errorf("ran %d runeword queries, want 1", n)
+	}
+}
+
+func TestGetRuneFullUnknownRune(t *testing.T) {
+	h := NewItemHandler(d2.NewRepository(dbtest.NewFake()), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/runes/:id/full", h.Localized((*ItemHandler).GetRuneFull))
+
+	resp := getJSON(t, app, "/runes/r99/full", nil)
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+// itemBaseRow is a row selected with the item base columns. Columns not
+// listed scan as zero values.
+func itemBaseRow(id int, code, name, itemType, classSpecific string) []interface{} {
+	row := make([]interface{}, 49)
+	row[0], row[1], row[2], row[3], row[5] = id, code, name, itemType, "armor"
+	if classSpecific != "" {
+		row[8] = classSpecific
+	}
+	return row
+}
+
+// itemTypeRow is a row selected with the item type columns
+func itemTypeRow(id int, code, name, classRestriction string) []interface{} {
+	row := make([]interface{}, 17)
+	row[0], row[1], row[2] = id, code, name
+	if classRestriction != "" {
+		row[13] = classRestriction
+	}
+	return row
+}
+
+// runewordBasesDB serves runeword 5's bases: an unrestricted Cap, a
+// sorceress Eagle Orb and a necromancer Preserved Head
+func runewordBasesDB() *dbtest.Fake {
+	return dbtest.NewFake().
+		On("WHERE runeword_id = $1",
+			[]interface{}{1, 5, 10, "cap", "Cap", "armor", 2, 2, nil},
+			[]interface{}{2, 5, 11, "ob1", "Eagle Orb", "weapon", 2, 2, nil},
+			[]interface{}{3, 5, 12, "ne1", "Preserved Head", "armor", 2, 2, nil},
+		).
+		On("item_bases WHERE code = ANY($1)",
+			itemBaseRow(10, "cap", "Cap", "helm", ""),
+			itemBaseRow(11, "ob1", "Eagle Orb", "orb", ""),
+			itemBaseRow(12, "ne1", "Preserved Head", "head", "nec"),
+		).
+		On("item_types WHERE code = ANY($1)",
+			itemTypeRow(1, "helm", "Helm", ""),
+			itemTypeRow(2, "orb", "Orb", "sor"),
+			itemTypeRow(3, "head", "Voodoo Heads", "nec"),
+		)
+}
+
+func TestGetAllBasesForRunewordBatchesClassLookups(t *testing.T) {
+	db := runewordBasesDB()
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/bases", h.Localized((*ItemHandler).GetAllBases))
+
+	var got []dto.BaseItemDetail
+	resp := getJSON(t, app, "/bases?runeword=5&class=sorceress", &got)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(got) != 1 || got[0].Code != "ob1" {
+		t.Errorf("got %+v, want only the Eagle Orb", got)
+	}
+
+	if n := db.Count("item_bases WHERE code = ANY($1)"); n != 1 {
+		t.Errorf("ran %d base lookups, want 1", n)
+	}
+	if n := db.Count("item_types WHERE code = ANY($1)"); n != 1 {
+		t.Errorf("ran %d item type lookups, want 1", n)
+	}
+	if n := db.Count("WHERE code = $1"); n != 0 {
+		t.Errorf("ran %d per-base lookups, want 0", n)
+	}
+}
+
+func TestGetAllBasesClassFilterModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCodes  []string
+	}{
+		{"no filter", "", fiber.StatusOK, []string{"cap", "ob1", "ne1"}},
+		{"class keeps only restricted", "&class=sor", fiber.StatusOK, []string{"ob1"}},
+		{"usable_by adds unrestricted", "&usable_by=sorceress", fiber.StatusOK, []string{"cap", "ob1"}},
+		{"usable_by ignores case", "&usable_by=Sorceress", fiber.StatusOK, []string{"cap", "ob1"}},
+		{"both filters", "&class=sorceress&usable_by=sorceress", fiber.StatusOK, []string{"ob1"}},
+		{"unknown class", "&class=bard", fiber.StatusBadRequest, nil},
+		{"usable_by not in classes table", "&usable_by=necromancer", fiber.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := runewordBasesDB().On("FROM d2.classes", []interface{}{"sorceress", "Sorceress", nil, nil, nil, nil})
+			h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+			app := fiber.New()
+			app.Get("/bases", h.Localized((*ItemHandler).GetAllBases))
+
+			var got []dto.BaseItemDetail
+			var out interface{}
+			if tt.wantStatus == fiber.StatusOK {
+				out = &got
+			}
+			resp := getJSON(t, app, "/bases?runeword=5"+tt.query, out)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			codes := make([]string, 0, len(got))
+			for _, b := range got {
+				codes = append(codes, b.Code)
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.wantCodes, ",") {
+				t.Errorf("bases = %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestGetAllBasesUsableByClassesLookupFails(t *testing.T) {
+	db := runewordBasesDB().OnError("FROM d2.classes", errors.New("connection reset"))
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/bases", h.Localized((*ItemHandler).GetAllBases))
+
+	var got dto.ErrorResponse
+	resp := getJSON(t, app, "/bases?runeword=5&usable_by=sorceress", &got)
+	if resp.StatusCode != fiber.StatusInternalServerError || got.Error != dto.ErrCodeInternal {
+		t.Errorf("status = %d, error = %q; want 500 %q", resp.StatusCode, got.Error, dto.ErrCodeInternal)
+	}
+}
+
+func TestConvertRunewordReportsRequiredSockets(t *testing.T) {
+	h := NewItemHandler(d2.NewRepository(dbtest.NewFake()), ItemHandlerConfig{})
+	malice := &d2.Runeword{ID: 1, Name: "Runeword_Malice", DisplayName: "Malice", Runes: []string{"r06", "r01", "r05"}}
+	runes := map[string]d2.RuneInfo{
+		"r06": {Code: "r06", Name: "Ith Rune"},
+		"r01": {Code: "r01", Name: "El Rune"},
+		"r05": {Code: "r05", Name: "Eth Rune"},
+	}
+
+	detail := h.convertRunewordToDTO(malice, nil, runes, nil)
+	if detail.RequiredSockets != 3 {
+		t.Errorf("RequiredSockets = %d, want 3", detail.RequiredSockets)
+	}
+	if detail.RuneOrder != "IthElEth" {
+		t.Errorf("RuneOrder = %q, want IthElEth", detail.RuneOrder)
+	}
+}
+
+// uniqueRow is a row selected with the unique item columns
+func uniqueRow(id int, name, baseCode, baseName string) []interface{} {
+	return []interface{}{
+		id, id, name, baseCode, baseName, 0, 0, 1,
+		true, false, nil, nil,
+		dbtest.JSON([]d2.Property{}), nil, nil, nil, nil,
+		0, 0, nil, nil,
+	}
+}
+
+func TestGetSameBaseWithoutBaseCode(t *testing.T) {
+	db := dbtest.NewFake().
+		On("unique_items WHERE id = $1", uniqueRow(7, "Unresolved Crest", "", "Mystery Helm")).
+		On("base_code = $1", []interface{}{8})
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/items/:type/:id/same-base", h.Localized((*ItemHandler).GetSameBase))
+
+	var got dto.SameBaseItems
+	resp := getJSON(t, app, "/items/unique/7/same-base", &got)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(got.Uniques) != 0 || len(got.SetItems) != 0 {
+		t.Errorf("got %d uniques and %d set items, want none", len(got.Uniques), len(got.SetItems))
+	}
+	if got.Base.Name != "Mystery Helm" {
+		t.Errorf("base name = %q, want Mystery Helm", got.Base.Name)
+	}
+	if n := db.Count("base_code = $1"); n != 0 {
+		t.Errorf("ran %d same-base queries, want 0", n)
+	}
+}
+
+func TestIncludeQuestReachesQueries(t *testing.T) {
+	tests := []struct {
+		url      string
+		fragment string // statement carrying the quest flag
+		arg      int
+		want     bool
+	}{
+		{"/search?q=cube", "-- Quest items", 3, false},
+		{"/search?q=cube&include_quest=true", "-- Quest items", 3, true},
+		{"/search?q=cube", "WHERE $2 AND quest_item = true", 1, false},
+		{"/search?q=cube&include_quest=true", "WHERE $2 AND quest_item = true", 1, true},
+		{"/bases", "($1 OR quest_item IS NOT TRUE)", 0, false},
+		{"/bases?include_quest=true", "($1 OR quest_item IS NOT TRUE)", 0, true},
+	}
+	for _, tt := range tests {
+		db := dbtest.NewFake()
+		h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+		app := fiber.New()
+		app.Get("/search", h.Search)
+		app.Get("/bases", h.Localized((*ItemHandler).GetAllBases))
+		getJSON(t, app, tt.url, nil)
+
+		found := false
+		for _, call := range db.Calls() {
+			if strings.Contains(call.SQL, tt.fragment) {
+				found = true
+				if call.Args[tt.arg] != tt.want {
+					t.Errorf("GET %s: quest flag = %v, want %v", tt.url, call.Args[tt.arg], tt.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("GET %s ran no statement containing %q", tt.url, tt.fragment)
+		}
+	}
+}
+
+func TestParseStatThresholds(t *testing.T) {
+	tests := []struct {
+		query  string
+		want   string // thresholds as code:min:max, "-" for an unset bound
+		wantOK bool
+	}{
+		{"", "", true},
+		{"stat=fcr", "fcr:-:-", true},
+		{"stat=fcr&min=20", "fcr:20:-", true},
+		{"stat=fcr&max=20", "fcr:-:20", true},
+		{"stat=%20fcr%20&min=-5&max=10", "fcr:-5:10", true},
+		{"stat=fcr&stat=res-all&min=20&min=&max=&max=30", "fcr:20:- res-all:-:30", true},
+		{"stat=fcr&stat=res-all&min=20", "fcr:20:- res-all:-:-", true},
+
+		{"min=20", "", false},
+		{"stat=fcr&min=20&min=30", "", false},
+		{"stat=fcr&max=1&max=2", "", false},
+		{"stat=fcr&min=abc", "", false},
+		{"stat=fcr&max=1.5", "", false},
+		{"stat=&min=20", "", false},
+		{"stat=%20", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []dto.StatThreshold
+			var ok bool
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got, ok = parseStatThresholds(c)
+				return nil
+			})
+			if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)); err != nil {
+				t.Fatalf("GET /?%s: %v", tt.query, err)
+			}
+
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			bound := func(v *int) string {
+				if v == nil {
+					return "-"
+				}
+				return strconv.Itoa(*v)
+			}
+			parts := make([]string, 0, len(got))
+			for _, th := range got {
+				parts = append(parts, th.Code+":"+bound(th.Min)+":"+bound(th.Max))
+			}
+			if s := strings.Join(parts, " "); s != tt.want {
+				t.Errorf("thresholds = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSetBonusesByEquippedCount(t *testing.T) {
+	partial := []d2.Property{
+		{Code: "mag%", Min: 10, Max: 10, ItemCount: 2},
+		{Code: "res-ltng", Min: 15, Max: 15, ItemCount: 3},
+		{Code: "ac", Min: 150, Max: 150, ItemCount: 4},
+	}
+	full := []d2.Property{{Code: "res-all", Min: 50, Max: 50}, {Code: "fcr", Min: 10, Max: 10}}
+	db := dbtest.NewFake().
+		On("FROM d2.set_bonuses", []interface{}{1, 1, "Tal Rasha's Wrappings", 100, dbtest.JSON(partial), dbtest.JSON(full), nil, nil}).
+		On("LOWER(set_name)", []interface{}{1}, []interface{}{2}, []interface{}{3}, []interface{}{4}, []interface{}{5}).
+		On("set_items WHERE id = $1", []interface{}{
+			1, 1, "Tal Rasha's Lidless Eye", "Tal Rasha's Wrappings", "oba", "Swirling Crystal", 65, 65, 1,
+			dbtest.JSON([]d2.Property{}), nil, nil, nil, nil, nil,
+			0, 0, nil, nil,
+		})
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/set/:name/bonuses", h.Localized((*ItemHandler).GetSetBonuses))
+
+	tiers := func(list []dto.SetBonusTier) []int {
+		counts := make([]int, 0, len(list))
+		for _, tier := range list {
+			counts = append(counts, tier.ItemCount)
+		}
+		return counts
+	}
+	tests := []struct {
+		equipped       int
+		active, locked []int
+	}{
+		{2, []int{2}, []int{3, 4, 5}},
+		{4, []int{2, 3, 4}, []int{5}},
+	}
+	for _, tt := range tests {
+		var got dto.SetBonusProgress
+		resp := getJSON(t, app, "/set/Tal%20Rasha's%20Wrappings/bonuses?equipped="+strconv.Itoa(tt.equipped), &got)
+		if resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("equipped=%d: status = %d, want 200", tt.equipped, resp.StatusCode)
+		}
+		if got.TotalItems != 5 || got.Equipped != tt.equipped {
+			t.Errorf("equipped=%d: got %d/%d pieces", tt.equipped, got.Equipped, got.TotalItems)
+		}
+		if a, l := tiers(got.Active), tiers(got.Locked); fmt.Sprint(a) != fmt.Sprint(tt.active) || fmt.Sprint(l) != fmt.Sprint(tt.locked) {
+			t.Errorf("equipped=%d: active %v locked %v, want active %v locked %v", tt.equipped, a, l, tt.active, tt.locked)
+		}
+		if n := len(got.Locked); n == 0 || !got.Locked[n-1].Full || len(got.Locked[n-1].Affixes) != len(full) {
+			t.Errorf("equipped=%d: last locked tier = %+v, want the full set bonuses", tt.equipped, got.Locked)
+		}
+	}
+
+	if resp := getJSON(t, app, "/set/Tal%20Rasha's%20Wrappings/bonuses?equipped=6", nil); resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("equipped=6: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestBaseImagePlaceholder(t *testing.T) {
+	withImage := itemBaseRow(11, "uap", "Shako", "helm", "")
+	withImage[36] = "https://cdn.example/items/shako.png"
+	db := dbtest.NewFake().
+		On("WHERE id = $1", itemBaseRow(10, "cap", "Cap", "helm", "")).
+		On("FROM d2.item_types", itemTypeRow(1, "helm", "Helm", ""))
+
+	tests := []struct {
+		name        string
+		placeholder string
+		row         []interface{}
+		wantURL     string
+		wantImage   bool
+	}{
+		{"fallback", "https://cdn.example/placeholders/", nil, "https://cdn.example/placeholders/base.png", false},
+		{"fallback disabled", "", nil, "", false},
+		{"real image", "https://cdn.example/placeholders", withImage, "https://cdn.example/items/shako.png", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := db
+			if tt.row != nil {
+				fake = dbtest.NewFake().On("WHERE id = $1", tt.row)
+			}
+			h := NewItemHandler(d2.NewRepository(fake), ItemHandlerConfig{ImagePlaceholderURL: tt.placeholder})
+			app := fiber.New()
+			app.Get("/items/:type/:id", h.Localized((*ItemHandler).GetItem))
+
+			var got dto.UnifiedItemDetail
+			if resp := getJSON(t, app, "/items/base/10", &got); resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got.Base == nil {
+				t.Fatal("response has no base detail")
+			}
+			if got.Base.ImageURL != tt.wantURL || got.Base.HasImage != tt.wantImage {
+				t.Errorf("imageUrl = %q, hasImage = %v; want %q, %v", got.Base.ImageURL, got.Base.HasImage, tt.wantURL, tt.wantImage)
+			}
+		})
+	}
+}
+
+func TestRunewordRuneSequenceKeepsOrderAndDuplicates(t *testing.T) {
+	db := dbtest.NewFake().
+		On("runewords WHERE id = $1", runewordRow(2, "Infinity", "r30", "r23", "r30", "r24")).
+		On("rune_number",
+			[]interface{}{24, "r24", "Ist Rune", 24, "https://cdn.example/runes/ist.png"},
+			[]interface{}{30, "r30", "Ber Rune", 30, "https://cdn.example/runes/ber.png"},
+			[]interface{}{23, "r23", "Mal Rune", 23, "https://cdn.example/runes/mal.png"},
+		)
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+	app := fiber.New()
+	app.Get("/items/:type/:id", h.Localized((*ItemHandler).GetItem))
+
+	var got dto.UnifiedItemDetail
+	if resp := getJSON(t, app, "/items/runeword/2", &got); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got.Runeword == nil {
+		t.Fatal("response has no runeword detail")
+	}
+
+	want := []dto.RuneSequenceEntry{
+		{Position: 1, RuneNumber: 30, Code: "r30", Name: "Ber", ImageURL: "https://cdn.example/runes/ber.png"},
+		{Position: 2, RuneNumber: 23, Code: "r23", Name: "Mal", ImageURL: "https://cdn.example/runes/mal.png"},
+		{Position: 3, RuneNumber: 30, Code: "r30", Name: "Ber", ImageURL: "https://cdn.example/runes/ber.png"},
+		{Position: 4, RuneNumber: 24, Code: "r24", Name: "Ist", ImageURL: "https://cdn.example/runes/ist.png"},
+	}
+	if len(got.Runeword.RuneSequence) != len(want) {
+		t.Fatalf("runeSequence = %+v, want %d runes", got.Runeword.RuneSequence, len(want))
+	}
+	for i := range want {
+		if got.Runeword.RuneSequence[i] != want[i] {
+			t.Errorf("runeSequence[%d] = %+v, want %+v", i, got.Runeword.RuneSequence[i], want[i])
+		}
+	}
+	if got.Runeword.RuneOrder != "BerMalBerIst" || got.Runeword.RequiredSockets != 4 {
+		t.Errorf("runeOrder = %q with %d sockets, want BerMalBerIst with 4", got.Runeword.RuneOrder, got.Runeword.RequiredSockets)
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// resolveLocale picks the response language: ?lang= wins, then the best
+// supported Accept-Language entry, then English
+func resolveLocale(c *fiber.Ctx) string {
+	if lang := c.Query("lang"); lang != "" {
+		if locale, ok := d2.MatchLocale(lang); ok {
+			return locale
+		}
+		return d2.DefaultLocale
+	}
+
+	for _, tag := range acceptLanguageTags(c.Get(fiber.HeaderAcceptLanguage)) {
+		if locale, ok := d2.MatchLocale(tag); ok {
+			return locale
+		}
+	}
+	return d2.DefaultLocale
+}
+
+// acceptLanguageTags returns the tags of an Accept-Language header, highest
+// quality first. Entries with q=0 are dropped.
+func acceptLanguageTags(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			entries = append(entries, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	tags := make([]string, len(entries))
+	for i, e := range entries {
+		tags[i] = e.tag
+	}
+	return tags
+}
+
+// Localized runs handler with a translator for the request's language, so
+// item affixes are rendered in that language. Responses vary by
+// Accept-Language for shared caches.
+func (h *ItemHandler) Localized(handler func(*ItemHandler, *fiber.Ctx) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAcceptLanguage)
+
+		locale := resolveLocale(c)
+		if locale == h.translator.Locale() {
+			return handler(h, c)
+		}
+		localized := *h
+		localized.translator = d2.TranslatorForLocale(locale).WithSkillNames(h.repo.SkillNames())
+		return handler(&localized, c)
+	}
+}
+
+// LoadSkillNames loads the class skill trees that affix text resolves skill
+// params with, using the first request's context. Until a load succeeds the
+// built-in names are used and the next request retries.
+func (h *ItemHandler) LoadSkillNames(c *fiber.Ctx) error {
+	if !h.repo.SkillNames().Loaded() {
+		h.repo.LoadSkillNames(c.UserContext(), false)
+	}
+	return c.Next()
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+func TestLoadSkillNamesOnFirstRequest(t *testing.T) {
+	trees := []d2.SkillTree{{Name: "Fire Spells", Skills: []string{"Fireball"}}}
+	db := dbtest.NewFake().On("FROM d2.classes", []interface{}{"sorceress", "Sorceress", "sor", dbtest.JSON(trees), nil, nil})
+	h := NewItemHandler(d2.NewRepository(db), ItemHandlerConfig{})
+
+	app := fiber.New()
+	app.Use(h.LoadSkillNames)
+	app.Get("/affix", h.Localized(func(h *ItemHandler, c *fiber.Ctx) error {
+		return c.SendString(h.translator.Translate(d2.Property{Code: "skill", Param: "47", Min: 3, Max: 3}))
+	}))
+
+	if db.Count("FROM d2.classes") != 0 {
+		t.Fatal("classes queried before the first request")
+	}
+	for url, want := range map[string]string{
+		"/affix":            "+3 To Fireball",
+		"/affix?lang=pt-BR": "+3 em Fireball",
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+		if err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != want {
+			t.Errorf("GET %s = %q, want %q", url, body, want)
+		}
+	}
+	if n := db.Count("FROM d2.classes"); n != 1 {
+		t.Errorf("queried classes %d times, want once", n)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/middleware"
+)
+
+// Limits enforced on admin item input
+const (
+	maxCharacterLevel = 99
+	maxItemSockets    = 6
+)
+
+var (
+	validBaseCategories = map[string]bool{"armor": true, "weapon": true, "misc": true}
+	validGemTypes       = map[string]bool{"amethyst": true, "sapphire": true, "emerald": true, "ruby": true, "diamond": true, "topaz": true, "skull": true}
+	validGemQualities   = map[string]bool{"chipped": true, "flawed": true, "normal": true, "flawless": true, "perfect": true}
+)
+
+// fieldErrors collects per-field validation failures for a request body
+type fieldErrors []dto.FieldError
+
+func (e *fieldErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, dto.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *fieldErrors) between(field string, v, min, max int) {
+	if v < min || v > max {
+		e.add(field, "must be between %d and %d", min, max)
+	}
+}
+
+func (e *fieldErrors) nonNegative(field string, v int) {
+	if v < 0 {
+		e.add(field, "must not be negative")
+	}
+}
+
+func (e *fieldErrors) ordered(minField, maxField string, min, max int) {
+	if min > max {
+		e.add(maxField, "must not be less than %s", minField)
+	}
+}
+
+func (e *fieldErrors) oneOf(field, v string, allowed map[string]bool) {
+	if v != "" && !allowed[v] {
+		e.add(field, "invalid value %q", v)
+	}
+}
+
+func (e *fieldErrors) properties(field string, props []dto.PropertyInput) {
+	for i, p := range props {
+		if p.Code == "" {
+			e.add(fmt.Sprintf("%s[%d].code", field, i), "is required")
+		}
+	}
+}
+
+// respondValidationErrors writes a 422 listing each invalid field
+func respondValidationErrors(c *fiber.Ctx, errs fieldErrors) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ValidationErrorResponse{
+		Error:     dto.ErrCodeValidationFailed,
+		Message:   "Request body failed validation",
+		Code:      422,
+		RequestID: middleware.GetRequestID(c),
+		Fields:    errs,
+	})
+}
+
+func validateUniqueItemRequest(req *dto.CreateUniqueItemRequest) fieldErrors {
+	var errs fieldErrors
+	errs.between("levelReq", req.LevelReq, 0, maxCharacterLevel)
+	errs.properties("properties", req.Properties)
+	return errs
+}
+
+func validateSetItemRequest(req *dto.CreateSetItemRequest) fieldErrors {
+	var errs fieldErrors
+	errs.between("levelReq", req.LevelReq, 0, maxCharacterLevel)
+	errs.properties("properties", req.Properties)
+	errs.properties("bonusProperties", req.BonusProperties)
+	return errs
+}
+
+func validateRunewordRequest(req *dto.CreateRunewordRequest) fieldErrors {
+	var errs fieldErrors
+	if len(req.Runes) > maxItemSockets {
+		errs.add("runes", "must have at most %d runes", maxItemSockets)
+	}
+	errs.properties("properties", req.Properties)
+	return errs
+}
+
+func validateRuneRequest(req *dto.CreateRuneRequest) fieldErrors {
+	var errs fieldErrors
+	errs.nonNegative("runeNumber", req.RuneNumber)
+	errs.between("levelReq", req.LevelReq, 0, maxCharacterLevel)
+	errs.properties("weaponMods", req.WeaponMods)
+	errs.properties("armorMods", req.ArmorMods)
+	errs.properties("shieldMods", req.ShieldMods)
+	return errs
+}
+
+func validateGemRequest(req *dto.CreateGemRequest) fieldErrors {
+	var errs fieldErrors
+	errs.oneOf("gemType", req.GemType, validGemTypes)
+	errs.oneOf("quality", req.Quality, validGemQualities)
+	errs.properties("weaponMods", req.WeaponMods)
+	errs.properties("armorMods", req.ArmorMods)
+	errs.properties("shieldMods", req.ShieldMods)
+	return errs
+}
+
+func validateBaseItemRequest(req *dto.CreateBaseItemRequest) fieldErrors {
+	var errs fieldErrors
+	errs.oneOf("category", req.Category, validBaseCategories)
+	errs.between("levelReq", req.LevelReq, 0, maxCharacterLevel)
+	errs.nonNegative("strReq", req.StrReq)
+	errs.nonNegative("dexReq", req.DexReq)
+	errs.nonNegative("durability", req.Durability)
+	errs.between("maxSockets", req.MaxSockets, 0, maxItemSockets)
+	errs.nonNegative("minAc", req.MinAC)
+	errs.ordered("minAc", "maxAc", req.MinAC, req.MaxAC)
+	errs.nonNegative("minDam", req.MinDam)
+	errs.ordered("minDam", "maxDam", req.MinDam, req.MaxDam)
+	errs.nonNegative("twoHandMinDam", req.TwoHandMinDam)
+	errs.ordered("twoHandMinDam", "twoHandMaxDam", req.TwoHandMinDam, req.TwoHandMaxDam)
+	return errs
+}
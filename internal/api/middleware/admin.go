@@ -11,20 +11,12 @@ func AdminMiddleware(repo *d2.Repository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userID := GetUserID(c)
 		if userID == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Authentication required",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Authentication required")
 		}
 
 		isAdmin, err := repo.IsAdmin(c.Context(), userID)
 		if err != nil || !isAdmin {
-			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
-				Error:   "forbidden",
-				Message: "Admin access required",
-				Code:    403,
-			})
+			return RespondError(c, fiber.StatusForbidden, dto.ErrCodeForbidden, "Admin access required")
 		}
 
 		return c.Next()
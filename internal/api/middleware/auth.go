@@ -174,22 +174,14 @@ func NewAuthMiddleware(config AuthConfig) fiber.Handler {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			debugLog(config, "Missing authorization header")
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Missing authorization header",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Missing authorization header")
 		}
 
 		// Extract token from "Bearer <token>"
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 			debugLog(config, "Invalid authorization header format")
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Invalid authorization header format",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Invalid authorization header format")
 		}
 
 		tokenString := parts[1]
@@ -244,42 +236,26 @@ func NewAuthMiddleware(config AuthConfig) fiber.Handler {
 
 		if err != nil {
 			debugLog(config, "Token validation failed: %v", err)
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Invalid or expired token",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Invalid or expired token")
 		}
 
 		if !token.Valid {
 			debugLog(config, "Token marked as invalid")
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Invalid or expired token",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Invalid or expired token")
 		}
 
 		// Extract claims
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
 			debugLog(config, "Failed to extract claims from token")
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Invalid token claims",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Invalid token claims")
 		}
 
 		// Get user ID from the "sub" claim (Supabase standard)
 		userID, ok := claims["sub"].(string)
 		if !ok || userID == "" {
 			debugLog(config, "Missing or invalid 'sub' claim in token")
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "unauthorized",
-				Message: "Missing user ID in token",
-				Code:    401,
-			})
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Missing user ID in token")
 		}
 
 		debugLog(config, "Authentication successful for user: %s", userID)
@@ -394,11 +370,7 @@ func GetUserID(c *fiber.Ctx) string {
 func RequireUserID(c *fiber.Ctx) (string, error) {
 	userID := GetUserID(c)
 	if userID == "" {
-		return "", c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-			Error:   "unauthorized",
-			Message: "Authentication required",
-			Code:    401,
-		})
+		return "", RespondError(c, fiber.StatusUnauthorized, dto.ErrCodeUnauthorized, "Authentication required")
 	}
 	return userID, nil
 }
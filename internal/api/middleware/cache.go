@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheControl marks successful GET responses as publicly cacheable for maxAge.
+// Catalog data only changes on import, so clients and CDNs can safely cache it.
+// A Cache-Control header already set further down the chain (e.g. NoStore on a
+// dynamic route) is left untouched. A zero maxAge disables the header.
+func CacheControl(maxAge time.Duration) fiber.Handler {
+	value := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if maxAge <= 0 || c.Method() != fiber.MethodGet {
+			return err
+		}
+		if c.GetRespHeader(fiber.HeaderCacheControl) != "" {
+			return err
+		}
+		if status := c.Response().StatusCode(); status >= 200 && status < 300 {
+			c.Set(fiber.HeaderCacheControl, value)
+		}
+		return err
+	}
+}
+
+// NoStore marks responses as non-cacheable (search, admin, other dynamic routes)
+func NoStore() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return err
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+)
+
+// RespondError writes an ErrorResponse with the given status and
+// machine-readable code (one of the dto.ErrCode constants), tagged with the
+// request ID
+func RespondError(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(dto.ErrorResponse{
+		Error:     code,
+		Message:   message,
+		Code:      status,
+		RequestID: GetRequestID(c),
+	})
+}
+
+// ErrorHandler renders errors returned up the handler chain (unknown routes,
+// panics caught by recover, fiber's own errors) as an ErrorResponse, so every
+// error body has the same shape
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	message := "Internal server error"
+	if fe, ok := err.(*fiber.Error); ok {
+		status = fe.Code
+		message = fe.Message
+	}
+	return RespondError(c, status, errorCodeForStatus(status), message)
+}
+
+// errorCodeForStatus picks the machine-readable code for an HTTP status
+func errorCodeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return dto.ErrCodeBadRequest
+	case fiber.StatusUnauthorized:
+		return dto.ErrCodeUnauthorized
+	case fiber.StatusForbidden:
+		return dto.ErrCodeForbidden
+	case fiber.StatusNotFound:
+		return dto.ErrCodeNotFound
+	case fiber.StatusUnprocessableEntity:
+		return dto.ErrCodeValidationFailed
+	case fiber.StatusTooManyRequests:
+		return dto.ErrCodeRateLimited
+	}
+	if status < 500 {
+		return dto.ErrCodeBadRequest
+	}
+	return dto.ErrCodeInternal
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/metrics"
+)
+
+// metricRequestDuration is the request latency histogram name
+const metricRequestDuration = "http_request_duration_seconds"
+
+// Metrics records a latency histogram per method, matched route and status
+// in reg. The histogram's _count series doubles as the request counter.
+// Routes are labeled by pattern ("/items/:type/:id"), not path, to keep the
+// number of series bounded.
+func Metrics(reg *metrics.Registry) fiber.Handler {
+	reg.NewHistogram(metricRequestDuration, "HTTP request latency, by method, route and status", nil)
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if fe, ok := err.(*fiber.Error); ok {
+			status = fe.Code
+		}
+		reg.Observe(metricRequestDuration, metrics.Labels{
+			"method": c.Method(),
+			"route":  c.Route().Path,
+			"status": strconv.Itoa(status),
+		}, time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// MetricsHandler serves reg in the Prometheus text exposition format
+func MetricsHandler(reg *metrics.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return reg.WriteText(c)
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
+)
+
+// HeaderAPIKey identifies a client for rate limiting. Clients without a
+// configured key are limited by IP.
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeys is the set of keys that get their own rate limit bucket. Keys are
+// not validated anywhere else, so an unknown key must not earn a fresh bucket.
+type APIKeys map[string]struct{}
+
+// NewAPIKeys builds a key set, skipping blank entries
+func NewAPIKeys(keys []string) APIKeys {
+	set := make(APIKeys, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			set[key] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Has reports whether key is configured
+func (k APIKeys) Has(key string) bool {
+	_, ok := k[key]
+	return ok
+}
+
+// RateCounter counts requests per key in fixed windows. Incr returns the
+// count in the current window and the time until the window resets.
+// *cache.RedisCache implements it for limits shared across instances.
+type RateCounter interface {
+	Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// RateLimitConfig configures one rate-limited route group
+type RateLimitConfig struct {
+	Group   string        // Counted separately from other groups, e.g. "search"
+	Limit   int           // Requests per client per window (0 disables)
+	Window  time.Duration // Defaults to one minute
+	Counter RateCounter
+	APIKeys APIKeys // Keys counted per key; any other client is counted per IP
+}
+
+// RateLimit rejects clients that exceed cfg.Limit requests per window with
+// 429 and a Retry-After header. Counter errors let the request through, so a
+// Redis outage degrades to no limiting rather than no service.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+	if cfg.Limit <= 0 || cfg.Counter == nil {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	limit := strconv.Itoa(cfg.Limit)
+
+	return func(c *fiber.Ctx) error {
+		key := cache.RateLimitKey(cfg.Group, rateLimitClient(c, cfg.APIKeys))
+		count, reset, err := cfg.Counter.Incr(c.UserContext(), key, cfg.Window)
+		if err != nil {
+			return c.Next()
+		}
+
+		remaining := int64(cfg.Limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Set("X-RateLimit-Limit", limit)
+		c.Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+		if count > int64(cfg.Limit) {
+			retryAfter := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+			c.Set(fiber.HeaderRetryAfter, retryAfter)
+			return RespondError(c, fiber.StatusTooManyRequests, dto.ErrCodeRateLimited, "Too many requests, retry after "+retryAfter+"s")
+		}
+		return c.Next()
+	}
+}
+
+// rateLimitClient identifies the caller: a hash of the API key when it is a
+// configured one (keys never reach the counter store in clear), otherwise the
+// IP. Unknown keys are ignored so rotating random keys can't dodge the limit.
+func rateLimitClient(c *fiber.Ctx, keys APIKeys) string {
+	if apiKey := c.Get(HeaderAPIKey); apiKey != "" && keys.Has(apiKey) {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	return "ip:" + c.IP()
+}
+
+// memoryWindow is one key's count in the current window
+type memoryWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// MemoryRateCounter is a RateCounter for a single instance, used when Redis
+// is unavailable. now is injectable so windows can be tested with a fake clock.
+type MemoryRateCounter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	windows map[string]*memoryWindow
+}
+
+// NewMemoryRateCounter creates an in-memory counter. A nil now uses time.Now.
+func NewMemoryRateCounter(now func() time.Time) *MemoryRateCounter {
+	if now == nil {
+		now = time.Now
+	}
+	return &MemoryRateCounter{now: now, windows: make(map[string]*memoryWindow)}
+}
+
+// memorySweepSize is the number of tracked keys above which expired windows
+// are dropped
+const memorySweepSize = 10000
+
+// Incr counts a request for key and returns the window's count and time left
+func (m *MemoryRateCounter) Incr(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if len(m.windows) > memorySweepSize {
+		for k, w := range m.windows {
+			if !now.Before(w.resetAt) {
+				delete(m.windows, k)
+			}
+		}
+	}
+
+	w, ok := m.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &memoryWindow{resetAt: now.Add(window)}
+		m.windows[key] = w
+	}
+	w.count++
+	return w.count, w.resetAt.Sub(now), nil
+}
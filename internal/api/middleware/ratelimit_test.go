@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeClock is a settable time source for MemoryRateCounter
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time          { return f.now }
+func (f *fakeClock) Advance(d time.Duration) { f.now = f.now.Add(d) }
+
+// incr counts one request for key in a one-minute window
+func incr(t *testing.T, m *MemoryRateCounter, key string) (int64, time.Duration) {
+	t.Helper()
+	count, reset, err := m.Incr(context.Background(), key, time.Minute)
+	if err != nil {
+		t.Fatalf("Incr(%q) error: %v", key, err)
+	}
+	return count, reset
+}
+
+func TestMemoryRateCounterCountsWithinWindow(t *testing.T) {
+	clock := newFakeClock()
+	counter := NewMemoryRateCounter(clock.Now)
+
+	for want := int64(1); want <= 3; want++ {
+		count, reset := incr(t, counter, "a")
+		if count != want {
+			t.Fatalf("count = %d, want %d", count, want)
+		}
+		if reset != time.Minute-time.Duration(want-1)*10*time.Second {
+			t.Fatalf("reset = %v after %d requests", reset, want)
+		}
+		clock.Advance(10 * time.Second)
+	}
+}
+
+func TestMemoryRateCounterResetsAfterWindow(t *testing.T) {
+	clock := newFakeClock()
+	counter := NewMemoryRateCounter(clock.Now)
+
+	incr(t, counter, "a")
+	incr(t, counter, "a")
+	clock.Advance(time.Minute)
+
+	count, reset := incr(t, counter, "a")
+	if count != 1 {
+		t.Fatalf("count after window = %d, want 1", count)
+	}
+	if reset != time.Minute {
+		t.Fatalf("reset after window = %v, want %v", reset, time.Minute)
+	}
+}
+
+func TestMemoryRateCounterKeysAreIndependent(t *testing.T) {
+	counter := NewMemoryRateCounter(newFakeClock().Now)
+
+	incr(t, counter, "a")
+	incr(t, counter, "a")
+	if count, _ := incr(t, counter, "b"); count != 1 {
+		t.Fatalf("count for b = %d, want 1", count)
+	}
+}
+
+func TestMemoryRateCounterSweepsExpiredWindows(t *testing.T) {
+	clock := newFakeClock()
+	counter := NewMemoryRateCounter(clock.Now)
+
+	for i := 0; i <= memorySweepSize; i++ {
+		incr(t, counter, strconv.Itoa(i))
+	}
+	clock.Advance(time.Minute)
+	incr(t, counter, "fresh")
+
+	if len(counter.windows) != 1 {
+		t.Fatalf("tracked windows = %d, want 1 after sweep", len(counter.windows))
+	}
+}
+
+// newRateLimitApp serves GET / behind a limiter of limit requests per minute
+func newRateLimitApp(limit int, counter RateCounter, keys APIKeys) *fiber.App {
+	app := fiber.New()
+	app.Use(RateLimit(RateLimitConfig{Group: "test", Limit: limit, Counter: counter, APIKeys: keys}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, apiKey string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if apiKey != "" {
+		req.Header.Set(HeaderAPIKey, apiKey)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp
+}
+
+func TestRateLimitRejectsOverLimit(t *testing.T) {
+	clock := newFakeClock()
+	app := newRateLimitApp(2, NewMemoryRateCounter(clock.Now), nil)
+
+	for i := 0; i < 2; i++ {
+		if resp := doRequest(t, app, ""); resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+
+	clock.Advance(15 * time.Second)
+	resp := doRequest(t, app, "")
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderRetryAfter); got != "45" {
+		t.Fatalf("Retry-After = %q, want 45", got)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "0" {
+		t.Fatalf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+
+	clock.Advance(45 * time.Second)
+	if resp := doRequest(t, app, ""); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status after window = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestRateLimitUnknownKeysShareTheIPBucket(t *testing.T) {
+	app := newRateLimitApp(2, NewMemoryRateCounter(newFakeClock().Now), NewAPIKeys([]string{"known"}))
+
+	doRequest(t, app, "random-1")
+	doRequest(t, app, "random-2")
+	if resp := doRequest(t, app, "random-3"); resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("status with a fresh unknown key = %d, want 429", resp.StatusCode)
+	}
+}
+
+func TestRateLimitConfiguredKeyGetsOwnBucket(t *testing.T) {
+	app := newRateLimitApp(1, NewMemoryRateCounter(newFakeClock().Now), NewAPIKeys([]string{" known ", ""}))
+
+	if resp := doRequest(t, app, ""); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("IP request status = %d, want 200", resp.StatusCode)
+	}
+	if resp := doRequest(t, app, "known"); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("keyed request status = %d, want 200", resp.StatusCode)
+	}
+	if resp := doRequest(t, app, "known"); resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("second keyed request status = %d, want 429", resp.StatusCode)
+	}
+}
+
+// failingCounter always errors, standing in for a Redis outage
+type failingCounter struct{}
+
+func (failingCounter) Incr(context.Context, string, time.Duration) (int64, time.Duration, error) {
+	return 0, 0, context.DeadlineExceeded
+}
+
+func TestRateLimitLetsRequestsThroughOnCounterError(t *testing.T) {
+	app := newRateLimitApp(1, failingCounter{}, nil)
+
+	for i := 0; i < 3; i++ {
+		if resp := doRequest(t, app, ""); resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderRequestID carries the request ID in both directions
+const HeaderRequestID = "X-Request-ID"
+
+// requestIDLocal is the c.Locals key holding the request ID. The logger
+// prints it with ${locals:requestid}.
+const requestIDLocal = "requestid"
+
+// maxRequestIDLen caps client-supplied request IDs
+const maxRequestIDLen = 64
+
+// RequestID assigns every request an ID for log correlation. A well-formed
+// incoming X-Request-ID is kept so IDs can span services; otherwise a random
+// one is generated. The ID is echoed in the response header and included in
+// error responses.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Locals(requestIDLocal, id)
+		c.Set(HeaderRequestID, id)
+		return c.Next()
+	}
+}
+
+// GetRequestID returns the request's ID, or "" outside the RequestID middleware
+func GetRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocal).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts IDs of letters, digits, '-', '_' and '.', so
+// client input can't break log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
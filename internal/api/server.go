@@ -8,9 +8,11 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/handlers"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/metrics"
 )
 
 // Server represents the HTTP server
@@ -18,6 +20,7 @@ type Server struct {
 	app    *fiber.App
 	repo   *d2.Repository
 	config *Config
+	games  games.Registry
 }
 
 // Config holds server configuration
@@ -31,6 +34,32 @@ type Config struct {
 	JWTAudience     string // Expected "aud" claim
 	JWTIssuer       string // Expected "iss" claim
 	AuthDebug       bool   // Debug logging for auth
+
+	// Response options
+	ImagePlaceholderURL string        // Base URL for fallback item images (empty disables)
+	CacheMaxAge         time.Duration // Cache-Control max-age for catalog data (0 disables)
+
+	// Breakpoints overrides the built-in speed breakpoint tables (nil uses defaults)
+	Breakpoints d2.BreakpointTables
+
+	// Cache stores item detail responses in Redis (nil disables). Rate limit
+	// counters are kept there too, falling back to per-instance counters.
+	Cache *cache.RedisCache
+
+	// Rate limits in requests per minute per client (a configured X-API-Key,
+	// else IP); 0 disables
+	SearchRateLimit  int      // search, by-stats, optimize and batch lookups
+	CatalogRateLimit int      // every public D2 route, search included
+	APIKeys          []string // X-API-Key values counted per key
+
+	// Client IP behind a load balancer. ProxyHeader names the header carrying
+	// it (e.g. Fly-Client-IP); empty uses the connection IP. TrustedProxies
+	// restricts which peers may set the header (IPs or CIDRs; empty trusts all).
+	ProxyHeader    string
+	TrustedProxies []string
+
+	// Games are mounted next to D2, each at /api/v1/<name>
+	Games []games.Game
 }
 
 // DefaultConfig returns default server configuration
@@ -40,6 +69,7 @@ func DefaultConfig() *Config {
 		ReadTimeout:     10 * time.Second,
 		WriteTimeout:    10 * time.Second,
 		AllowedOrigins:  "*",
+		CacheMaxAge:     time.Hour,
 	}
 }
 
@@ -53,6 +83,12 @@ func NewServer(repo *d2.Repository, config *Config) *Server {
 		ReadTimeout:  config.ReadTimeout,
 		WriteTimeout: config.WriteTimeout,
 		AppName:      "LootStash Catalog API",
+		ErrorHandler: middleware.ErrorHandler,
+
+		ProxyHeader:             config.ProxyHeader,
+		EnableIPValidation:      config.ProxyHeader != "",
+		EnableTrustedProxyCheck: len(config.TrustedProxies) > 0,
+		TrustedProxies:          config.TrustedProxies,
 	})
 
 	server := &Server{
@@ -60,6 +96,12 @@ func NewServer(repo *d2.Repository, config *Config) *Server {
 		repo:   repo,
 		config: config,
 	}
+	// A duplicate game name is a wiring mistake, not a runtime condition
+	for _, g := range append([]games.Game{NewD2Game(repo, config)}, config.Games...) {
+		if err := server.games.Register(g); err != nil {
+			panic(err)
+		}
+	}
 
 	server.setupMiddleware()
 	server.setupRoutes()
@@ -71,9 +113,18 @@ func (s *Server) setupMiddleware() {
 	// Recovery middleware
 	s.app.Use(recover.New())
 
+	// Request ID for log correlation, echoed in X-Request-ID and error bodies
+	s.app.Use(middleware.RequestID())
+
+	// Tracing middleware (no-op unless an exporter is configured)
+	s.app.Use(middleware.Tracing())
+
+	// Request latency and counts, exposed at /metrics
+	s.app.Use(middleware.Metrics(metrics.Default))
+
 	// Logger middleware
 	s.app.Use(logger.New(logger.Config{
-		Format:     "${time} ${status} ${method} ${path} ${latency}\n",
+		Format:     "${time} ${locals:requestid} ${status} ${method} ${path} ${latency}\n",
 		TimeFormat: "2006-01-02 15:04:05",
 	}))
 
@@ -81,7 +132,8 @@ func (s *Server) setupMiddleware() {
 	s.app.Use(cors.New(cors.Config{
 		AllowOrigins:     s.config.AllowedOrigins,
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,If-None-Match,X-API-Key,X-Request-ID",
+		ExposeHeaders:    "ETag,Retry-After,X-RateLimit-Limit,X-RateLimit-Remaining,X-Request-ID",
 		AllowCredentials: true,
 	}))
 }
@@ -95,58 +147,28 @@ func (s *Server) setupRoutes() {
 		})
 	})
 
+	// Prometheus metrics
+	s.app.Get("/metrics", middleware.MetricsHandler(metrics.Default))
+
 	// API v1 group
 	api := s.app.Group("/api")
 	v1 := api.Group("/v1")
 
-	// D2 routes
-	d2Routes := v1.Group("/d2")
-	s.setupD2Routes(d2Routes)
-
-	// Admin routes
-	adminRoutes := v1.Group("/admin/d2")
-	s.setupAdminRoutes(adminRoutes)
-}
+	// Game routes
+	for _, g := range s.games.All() {
+		g.RegisterRoutes(v1.Group("/" + g.Name()))
+	}
 
-func (s *Server) setupD2Routes(router fiber.Router) {
-	itemHandler := handlers.NewItemHandler(s.repo)
-
-	// Item routes
-	items := router.Group("/items")
-
-	// Search endpoint
-	items.Get("/search", itemHandler.Search)
-
-	// Generic item lookup by type and ID
-	items.Get("/:type/:id", itemHandler.GetItem)
-
-	// Specific type endpoints (for convenience)
-	items.Get("/unique/:id", itemHandler.GetUniqueItem)
-	items.Get("/set/:id", itemHandler.GetSetItem)
-	items.Get("/runeword/:id", itemHandler.GetRuneword)
-	items.Get("/runeword/:id/bases", itemHandler.GetRunewordBases)
-	items.Get("/rune/:id", itemHandler.GetRune)
-	items.Get("/gem/:id", itemHandler.GetGem)
-	items.Get("/base/:id", itemHandler.GetBase)
-	items.Get("/quest/:id", itemHandler.GetQuestItem)
-
-	// Collection endpoints - list all items by type
-	router.Get("/runes", itemHandler.GetAllRunes)
-	router.Get("/gems", itemHandler.GetAllGems)
-	router.Get("/bases", itemHandler.GetAllBases)
-	router.Get("/uniques", itemHandler.GetAllUniques)
-	router.Get("/sets", itemHandler.GetAllSets)
-	router.Get("/runewords", itemHandler.GetAllRunewords)
-	router.Get("/quests", itemHandler.GetAllQuestItems)
-	router.Get("/classes", itemHandler.GetAllClasses)
-
-	// Reference data endpoints - for marketplace filtering
-	router.Get("/stats", itemHandler.GetAllStats)
-	router.Get("/categories", itemHandler.GetAllCategories)
-	router.Get("/rarities", itemHandler.GetAllRarities)
+	// Admin routes, one group per game behind the shared admin checks
+	for _, g := range s.games.All() {
+		adminRoutes := v1.Group("/admin/" + g.Name())
+		s.setupAdminAuth(adminRoutes)
+		g.RegisterAdminRoutes(adminRoutes)
+	}
 }
 
-func (s *Server) setupAdminRoutes(router fiber.Router) {
+// setupAdminAuth requires an authenticated admin on every route of router
+func (s *Server) setupAdminAuth(router fiber.Router) {
 	authConfig := middleware.AuthConfig{
 		JWTSecret: s.config.JWTSecret,
 		JWKSURL:   s.config.JWKSURL,
@@ -154,18 +176,9 @@ func (s *Server) setupAdminRoutes(router fiber.Router) {
 		Issuer:    s.config.JWTIssuer,
 		Debug:     s.config.AuthDebug,
 	}
+	router.Use(middleware.NoStore())
 	router.Use(middleware.NewAuthMiddleware(authConfig))
 	router.Use(middleware.AdminMiddleware(s.repo))
-
-	adminHandler := handlers.NewAdminHandler(s.repo)
-
-	router.Post("/classes", adminHandler.CreateClass)
-	router.Put("/classes/:classId", adminHandler.UpdateClass)
-
-	items := router.Group("/items")
-	items.Post("/:type", adminHandler.CreateItem)
-	items.Put("/:type/:id", adminHandler.UpdateItem)
-	items.Delete("/:type/:id", adminHandler.DeleteItem)
 }
 
 // Start starts the HTTP server
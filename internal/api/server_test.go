@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/games/d2"
+)
+
+// newTestServer returns a server with the default config over an empty Fake
+// database, where every item lookup misses
+func newTestServer(t *testing.T, config *Config) *Server {
+	t.Helper()
+	return NewServer(d2.NewRepository(dbtest.NewFake()), config)
+}
+
+// serve runs req through the server's app
+func serve(t *testing.T, s *Server, req *http.Request) *http.Response {
+	t.Helper()
+	resp, err := s.app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestUnknownItemErrorCarriesRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		wantID string
+	}{
+		{name: "generated"},
+		{name: "client supplied", header: "trace-42.a", wantID: "trace-42.a"},
+		{name: "malformed client id replaced", header: "bad id!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/d2/items/unique/999999", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.HeaderRequestID, tt.header)
+			}
+			resp := serve(t, s, req)
+
+			if resp.StatusCode != http.StatusNotFound {
+				t.Fatalf("status = %d, want 404", resp.StatusCode)
+			}
+			headerID := resp.Header.Get(middleware.HeaderRequestID)
+			if headerID == "" {
+				t.Fatal("response has no X-Request-ID header")
+			}
+			if tt.wantID != "" && headerID != tt.wantID {
+				t.Errorf("X-Request-ID = %q, want %q", headerID, tt.wantID)
+			}
+			if tt.wantID == "" && headerID == tt.header {
+				t.Errorf("X-Request-ID kept malformed client value %q", headerID)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			var got dto.ErrorResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("decode error response %q: %v", body, err)
+			}
+			if got.Error != dto.ErrCodeNotFound {
+				t.Errorf("error = %q, want %q", got.Error, dto.ErrCodeNotFound)
+			}
+			if got.RequestID != headerID {
+				t.Errorf("requestId = %q, want the X-Request-ID header %q", got.RequestID, headerID)
+			}
+		})
+	}
+}
+
+func TestCacheControlByEndpointClass(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"catalog list", http.MethodGet, "/api/v1/d2/runes", "public, max-age=3600"},
+		{"reference data", http.MethodGet, "/api/v1/d2/rarities", "public, max-age=3600"},
+		{"search", http.MethodGet, "/api/v1/d2/items/search?q=ber", "no-store"},
+		{"stat search", http.MethodGet, "/api/v1/d2/items/by-stats?stats=str", "no-store"},
+		{"batch lookup", http.MethodPost, "/api/v1/d2/items/batch", "no-store"},
+		{"changes feed", http.MethodGet, "/api/v1/d2/changes", "no-store"},
+		{"admin", http.MethodGet, "/api/v1/admin/d2/stats/raw", "no-store"},
+		{"catalog miss", http.MethodGet, "/api/v1/d2/items/unique/999999", ""},
+		{"health", http.MethodGet, "/health", ""},
+	}
+	s := newTestServer(t, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serve(t, s, httptest.NewRequest(tt.method, tt.path, nil))
+			if got := resp.Header.Get("Cache-Control"); got != tt.want {
+				t.Errorf("%s %s (status %d): Cache-Control = %q, want %q", tt.method, tt.path, resp.StatusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlDisabled(t *testing.T) {
+	config := DefaultConfig()
+	config.CacheMaxAge = 0
+	s := newTestServer(t, config)
+
+	resp := serve(t, s, httptest.NewRequest(http.MethodGet, "/api/v1/d2/runes", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Cache-Control"); got != "" {
+		t.Errorf("Cache-Control = %q, want none with CacheMaxAge 0", got)
+	}
+}
+
+func TestSetRoutesDoNotCollideWithSetNames(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantStatus  int
+		wantMessage string
+	}{
+		// The collection route stays a list...
+		{"/api/v1/d2/sets/bonuses", http.StatusOK, ""},
+		// ...while a set named "bonuses" is looked up by name
+		{"/api/v1/d2/set/bonuses", http.StatusNotFound, "Set not found"},
+		{"/api/v1/d2/set/Tal%20Rasha's%20Wrappings/bonuses?equipped=1", http.StatusNotFound, "Set not found"},
+		{"/api/v1/d2/set/Tal%20Rasha's%20Wrappings/combined", http.StatusNotFound, "Set not found"},
+		{"/api/v1/d2/set/Tal%20Rasha's%20Wrappings/full", http.StatusNotFound, "Set not found"},
+	}
+	s := newTestServer(t, nil)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp := serve(t, s, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			var got dto.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q from the set handler", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
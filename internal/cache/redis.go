@@ -99,7 +99,53 @@ func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return n > 0, err
 }
 
+// Incr increments a counter that expires window after its first increment
+// and returns the new count with the time left until it resets
+func (c *RedisCache) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	count, err := c.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	if count == 1 {
+		if err := c.client.PExpire(ctx, key, window).Err(); err != nil {
+			return 0, 0, err
+		}
+		return count, window, nil
+	}
+
+	ttl, err := c.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	if ttl < 0 {
+		// The expiry was lost (e.g. a crash between INCR and PEXPIRE); set it
+		// again so the counter can't block a client forever
+		if err := c.client.PExpire(ctx, key, window).Err(); err != nil {
+			return 0, 0, err
+		}
+		ttl = window
+	}
+	return count, ttl, nil
+}
+
+// RateLimitKey builds the counter key for a client in a rate-limited route group
+func RateLimitKey(group, client string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", group, client)
+}
+
 // Cache key builders for D2 catalog
+func D2ItemDetailKey(itemType string, id int) string {
+	return fmt.Sprintf("d2:item:%s:%d", itemType, id)
+}
+
+func D2ItemDetailsPattern() string {
+	return "d2:item:*"
+}
+
+func D2AllPattern() string {
+	return "d2:*"
+}
+
 func D2ItemBaseKey(code string) string {
 	return fmt.Sprintf("d2:item_base:%s", code)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/tracing"
 )
 
 type DB struct {
@@ -19,6 +20,7 @@ func NewConnection(ctx context.Context, databaseURL string) (*DB, error) {
 	}
 
 	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	config.ConnConfig.Tracer = tracing.QueryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
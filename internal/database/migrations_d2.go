@@ -331,6 +331,93 @@ CREATE INDEX IF NOT EXISTS idx_item_bases_type_tags ON d2.item_bases USING GIN (
 CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_items_name ON d2.unique_items(name);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_set_items_name ON d2.set_items(name);
 
+-- Soft-delete flags for tables without one (purge/reconciliation; upserts restore)
+ALTER TABLE d2.set_items ADD COLUMN IF NOT EXISTS enabled BOOLEAN DEFAULT TRUE;
+ALTER TABLE d2.runes ADD COLUMN IF NOT EXISTS enabled BOOLEAN DEFAULT TRUE;
+ALTER TABLE d2.gems ADD COLUMN IF NOT EXISTS enabled BOOLEAN DEFAULT TRUE;
+
+-- Skulls socket like gems but have their own quality chain
+ALTER TABLE d2.gems ADD COLUMN IF NOT EXISTS is_skull BOOLEAN DEFAULT FALSE;
+UPDATE d2.gems SET is_skull = TRUE WHERE gem_type = 'skull' AND is_skull IS NOT TRUE;
+
+-- One-time data migrations record their name here so they don't rerun on
+-- every boot
+CREATE TABLE IF NOT EXISTS d2.data_migrations (
+    name TEXT PRIMARY KEY,
+    applied_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Reduction stats store positive magnitudes; the display template carries the
+-- sign. Flip rows stored with negative values and drop their stale displayText.
+-- Values that aren't JSON numbers are left alone rather than failing the cast.
+CREATE OR REPLACE FUNCTION d2.normalize_reduction_signs(props JSONB) RETURNS JSONB AS $$
+    SELECT COALESCE(jsonb_agg(
+        CASE WHEN p->>'code' IN ('ease', 'reduce-ac', 'red-dmg', 'red-dmg%', 'red-mag', 'res-pois-len', 'cheap',
+                                 'pierce-fire', 'pierce-cold', 'pierce-ltng', 'pierce-pois', 'pierce-mag')
+              AND jsonb_typeof(p->'min') = 'number' AND jsonb_typeof(p->'max') = 'number'
+        THEN CASE WHEN (p->>'min')::numeric <= 0 AND (p->>'max')::numeric <= 0
+                   AND ((p->>'min')::numeric < 0 OR (p->>'max')::numeric < 0)
+             THEN (p - 'displayText') || jsonb_build_object('min', -(p->>'max')::numeric, 'max', -(p->>'min')::numeric)
+             ELSE p END
+        ELSE p END
+        ORDER BY ord), '[]'::jsonb)
+    FROM jsonb_array_elements(props) WITH ORDINALITY AS e(p, ord)
+$$ LANGUAGE sql IMMUTABLE;
+
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    IF EXISTS (SELECT 1 FROM d2.data_migrations WHERE name = 'normalize_reduction_signs') THEN
+        RETURN;
+    END IF;
+    FOR col IN SELECT * FROM (VALUES
+        ('unique_items', 'properties'), ('set_items', 'properties'), ('set_items', 'bonus_properties'),
+        ('set_bonuses', 'partial_bonuses'), ('set_bonuses', 'full_bonuses'), ('runewords', 'properties'),
+        ('runes', 'weapon_mods'), ('runes', 'helm_mods'), ('runes', 'shield_mods'),
+        ('gems', 'weapon_mods'), ('gems', 'helm_mods'), ('gems', 'shield_mods')
+    ) AS t(tbl, name) LOOP
+        EXECUTE format(
+            'UPDATE d2.%I SET %I = d2.normalize_reduction_signs(%I) WHERE jsonb_typeof(%I) = ''array'' AND %I <> d2.normalize_reduction_signs(%I)',
+            col.tbl, col.name, col.name, col.name, col.name, col.name);
+    END LOOP;
+    INSERT INTO d2.data_migrations (name) VALUES ('normalize_reduction_signs');
+END $$;
+
+-- Content hash of the last imported source record; incremental imports skip
+-- rows whose hash is unchanged
+ALTER TABLE d2.item_bases ADD COLUMN IF NOT EXISTS content_hash TEXT;
+ALTER TABLE d2.unique_items ADD COLUMN IF NOT EXISTS content_hash TEXT;
+ALTER TABLE d2.set_items ADD COLUMN IF NOT EXISTS content_hash TEXT;
+ALTER TABLE d2.runewords ADD COLUMN IF NOT EXISTS content_hash TEXT;
+ALTER TABLE d2.runes ADD COLUMN IF NOT EXISTS content_hash TEXT;
+ALTER TABLE d2.gems ADD COLUMN IF NOT EXISTS content_hash TEXT;
+
+-- Downscaled copies of image_url for grid views
+ALTER TABLE d2.item_bases ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+ALTER TABLE d2.unique_items ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+ALTER TABLE d2.set_items ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+ALTER TABLE d2.runewords ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+ALTER TABLE d2.runes ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+ALTER TABLE d2.gems ADD COLUMN IF NOT EXISTS thumb_url TEXT;
+
+-- Uploaded images keyed by SHA-256 of their bytes, so identical icons are
+-- stored once across item types and import runs
+CREATE TABLE IF NOT EXISTS d2.image_hashes (
+    hash CHAR(64) PRIMARY KEY,
+    url TEXT NOT NULL,
+    thumb_url TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Full-text search over misc item descriptions (search include_description=true)
+CREATE INDEX IF NOT EXISTS idx_item_bases_description_fts
+    ON d2.item_bases USING GIN (to_tsvector('english', COALESCE(description, '')));
+
+-- Placeholder rows ("Expansion", "Not Used", ...) kept by an import run with
+-- placeholders included; hidden from public listings
+ALTER TABLE d2.item_bases ADD COLUMN IF NOT EXISTS placeholder BOOLEAN NOT NULL DEFAULT false;
+
 -- V2: Drop legacy TSV-only tables
 DROP TABLE IF EXISTS d2.treasure_class_items;
 DROP TABLE IF EXISTS d2.treasure_classes;
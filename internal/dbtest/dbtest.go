@@ -0,0 +1,283 @@
+// Package dbtest provides database stand-ins for tests: Fake, a scripted
+// in-memory replacement for a pgx pool, and Tx, a rolled-back transaction on
+// a real Postgres for tests that exercise SQL.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/database"
+)
+
+// EnvDatabaseURL names the variable pointing integration tests at a Postgres
+// database. Tests migrate it and write inside transactions that are rolled
+// back, but should not be pointed at production.
+const EnvDatabaseURL = "TEST_DATABASE_URL"
+
+// Tx returns a transaction on the EnvDatabaseURL database with the D2 schema
+// migrated, rolled back when the test ends. The test is skipped when the
+// variable is unset.
+func Tx(t testing.TB) pgx.Tx {
+	t.Helper()
+	url := os.Getenv(EnvDatabaseURL)
+	if url == "" {
+		t.Skipf("%s not set, skipping database test", EnvDatabaseURL)
+	}
+
+	ctx := context.Background()
+	db, err := database.NewConnection(ctx, url)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	if err := db.MigrateD2(ctx); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	tx, err := db.Pool().Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin test transaction: %v", err)
+	}
+	t.Cleanup(func() { _ = tx.Rollback(context.Background()) })
+	return tx
+}
+
+// Call is one statement run against a Fake
+type Call struct {
+	SQL  string
+	Args []interface{}
+}
+
+// stub answers statements containing fragment
+type stub struct {
+	fragment string
+	rows     [][]interface{}
+	err      error
+}
+
+// Fake is an in-memory stand-in for a pgx pool. A statement is answered by
+// the first stub whose fragment its SQL contains. Unstubbed queries return no
+// rows (QueryRow fails with pgx.ErrNoRows) and unstubbed Execs succeed. Execs
+// report one affected row per stub row.
+// Values are assigned to Scan destinations by type, so stub rows must list
+// values in the order and of the types the code scans them.
+type Fake struct {
+	mu    sync.Mutex
+	stubs []stub
+	calls []Call
+}
+
+// NewFake returns a Fake with no stubs
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// On answers statements containing fragment with rows
+func (f *Fake) On(fragment string, rows ...[]interface{}) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, stub{fragment: fragment, rows: rows})
+	return f
+}
+
+// OnError fails statements containing fragment with err
+func (f *Fake) OnError(fragment string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, stub{fragment: fragment, err: err})
+	return f
+}
+
+// Calls returns the statements run so far, in order
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Count returns how many statements run so far contain fragment
+func (f *Fake) Count(fragment string) int {
+	n := 0
+	for _, call := range f.Calls() {
+		if strings.Contains(call.SQL, fragment) {
+			n++
+		}
+	}
+	return n
+}
+
+// answer records a statement and finds its stub
+func (f *Fake) answer(sql string, args []interface{}) (stub, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{SQL: sql, Args: args})
+	for _, s := range f.stubs {
+		if strings.Contains(sql, s.fragment) {
+			return s, true
+		}
+	}
+	return stub{}, false
+}
+
+// Exec runs a statement, returning the stub's error if any
+func (f *Fake) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	s, _ := f.answer(sql, args)
+	if s.err != nil {
+		return pgconn.NewCommandTag(""), s.err
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("EXEC %d", len(s.rows))), nil
+}
+
+// Query returns the stub's rows
+func (f *Fake) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	s, _ := f.answer(sql, args)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &rows{rows: s.rows, pos: -1}, nil
+}
+
+// QueryRow returns the stub's first row, or pgx.ErrNoRows
+func (f *Fake) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	s, _ := f.answer(sql, args)
+	switch {
+	case s.err != nil:
+		return errRow{s.err}
+	case len(s.rows) == 0:
+		return errRow{pgx.ErrNoRows}
+	}
+	return &rows{rows: s.rows[:1], pos: 0}
+}
+
+// Begin returns a transaction whose statements run against the Fake.
+// Commit and rollback do nothing, so tests see every statement either way.
+func (f *Fake) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{fake: f}, nil
+}
+
+// JSON encodes v for a JSONB column
+func JSON(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// fakeTx is a Fake transaction. Methods beyond statements, commit and
+// rollback are not supported and panic on the nil embedded Tx.
+type fakeTx struct {
+	pgx.Tx
+	fake *Fake
+}
+
+func (t *fakeTx) Begin(context.Context) (pgx.Tx, error) { return t, nil }
+func (t *fakeTx) Commit(context.Context) error          { return nil }
+func (t *fakeTx) Rollback(context.Context) error        { return nil }
+
+func (t *fakeTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return t.fake.Exec(ctx, sql, args...)
+}
+
+func (t *fakeTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return t.fake.Query(ctx, sql, args...)
+}
+
+func (t *fakeTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return t.fake.QueryRow(ctx, sql, args...)
+}
+
+// errRow is a pgx.Row that fails to scan
+type errRow struct{ err error }
+
+func (r errRow) Scan(...interface{}) error { return r.err }
+
+// rows iterates stub rows. It serves as pgx.Rows and, positioned on its
+// first row, as pgx.Row.
+type rows struct {
+	rows [][]interface{}
+	pos  int
+	err  error
+}
+
+func (r *rows) Close()                                       {}
+func (r *rows) Err() error                                   { return r.err }
+func (r *rows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("") }
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *rows) RawValues() [][]byte                          { return nil }
+func (r *rows) Conn() *pgx.Conn                              { return nil }
+
+func (r *rows) Next() bool {
+	if r.pos+1 >= len(r.rows) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *rows) Values() ([]interface{}, error) {
+	return r.rows[r.pos], nil
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	row := r.rows[r.pos]
+	if len(dest) != len(row) {
+		r.err = fmt.Errorf("dbtest: scanning %d columns into %d destinations", len(row), len(dest))
+		return r.err
+	}
+	for i := range dest {
+		if err := assign(dest[i], row[i]); err != nil {
+			r.err = fmt.Errorf("dbtest: column %d: %w", i, err)
+			return r.err
+		}
+	}
+	return nil
+}
+
+// assign stores src in the pointer dest, allocating pointer targets and
+// converting between compatible types (int to int64, string to []byte)
+func assign(dest, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("destination %T is not a non-nil pointer", dest)
+	}
+	target := dv.Elem()
+	if src == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	for target.Kind() == reflect.Ptr && !sv.Type().AssignableTo(target.Type()) {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+
+	switch {
+	case sv.Type().AssignableTo(target.Type()):
+		target.Set(sv)
+	case target.Kind() == reflect.String && sv.CanInt():
+		// reflect converts integers to strings as code points; never wanted here
+		return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+	case sv.Type().ConvertibleTo(target.Type()):
+		target.Set(sv.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package d2
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxEIAS is the cap on effective IAS after weapon speed is applied
+const maxEIAS = 75
+
+// minEIAS is the floor on effective IAS (very slow weapons with no IAS)
+const minEIAS = -85
+
+// maxSearchIAS bounds the breakpoint search. Effective IAS from items alone
+// reaches the cap well below this for every weapon speed.
+const maxSearchIAS = 1000
+
+// attackFrames holds the standard attack (A1) animation length for each class
+// and weapon animation class, in human form. Warlock animations are not known
+// yet.
+var attackFrames = map[string]map[string]int{
+	"amazon":      {"1hs": 16, "1ht": 16, "2hs": 20, "2ht": 20, "bow": 14, "xbw": 20, "stf": 20},
+	"assassin":    {"1hs": 15, "1ht": 15, "2hs": 23, "2ht": 23, "bow": 16, "xbw": 21, "stf": 19, "ht1": 11},
+	"barbarian":   {"1hs": 16, "1ht": 16, "2hs": 18, "2ht": 19, "bow": 15, "xbw": 20, "stf": 19},
+	"druid":       {"1hs": 19, "1ht": 19, "2hs": 21, "2ht": 23, "bow": 16, "xbw": 20, "stf": 17},
+	"necromancer": {"1hs": 19, "1ht": 19, "2hs": 23, "2ht": 24, "bow": 18, "xbw": 20, "stf": 20},
+	"paladin":     {"1hs": 15, "1ht": 16, "2hs": 18, "2ht": 20, "bow": 16, "xbw": 20, "stf": 18},
+	"sorceress":   {"1hs": 20, "1ht": 19, "2hs": 23, "2ht": 24, "bow": 17, "xbw": 20, "stf": 18},
+}
+
+// weaponClassByType maps weapon item type codes to their animation class.
+// Swinging weapons are listed as one-handed; WeaponAnimationClass switches
+// them to "2hs" when the base has two-handed damage.
+var weaponClassByType = map[string]string{
+	"swor": "1hs",
+	"axe":  "1hs",
+	"mace": "1hs",
+	"club": "1hs",
+	"hamm": "1hs",
+	"scep": "1hs",
+	"wand": "1hs",
+	"orb":  "1hs",
+	"knif": "1ht",
+	"tkni": "1ht",
+	"jave": "1ht",
+	"spea": "2ht",
+	"pole": "2hs",
+	"staf": "stf",
+	"bow":  "bow",
+	"xbow": "xbw",
+	"h2h":  "ht1",
+}
+
+// WeaponAnimationClass returns the attack animation class of a base ("1hs",
+// "2ht", "bow", ...), or "" when the base is not a weapon or its type has no
+// known attack animation.
+func WeaponAnimationClass(base *ItemBase) string {
+	if base == nil || base.Category != "weapon" {
+		return ""
+	}
+	for _, code := range []string{base.ItemType, base.ItemType2} {
+		wclass, ok := weaponClassByType[code]
+		if !ok {
+			continue
+		}
+		if wclass == "1hs" && base.TwoHandMaxDam > 0 {
+			return "2hs"
+		}
+		return wclass
+	}
+	return ""
+}
+
+// AttackSpeedClasses returns the classes with known attack animations, sorted
+func AttackSpeedClasses() []string {
+	classes := make([]string, 0, len(attackFrames))
+	for class := range attackFrames {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// framesPerAttack applies the LoD attack speed formula: item IAS has
+// diminishing returns, the weapon speed modifier is subtracted, and the
+// result is capped before scaling the 256-speed animation.
+func framesPerAttack(frames, speed, ias int) int {
+	eias := 120*ias/(120+ias) - speed
+	if eias > maxEIAS {
+		eias = maxEIAS
+	}
+	if eias < minEIAS {
+		eias = minEIAS
+	}
+	step := 256 * (100 + eias) / 100
+	return (256*frames+step-1)/step - 1
+}
+
+// ComputeIASBreakpoints returns the IAS breakpoints for a class attacking with
+// a base: the smallest IAS value for each reachable frames-per-attack, sorted
+// by value. The first entry is always IAS 0. An error is returned when the
+// base is not a weapon or the class has no animation for it.
+func ComputeIASBreakpoints(base *ItemBase, class string) ([]Breakpoint, error) {
+	wclass := WeaponAnimationClass(base)
+	if wclass == "" {
+		return nil, fmt.Errorf("base %s has no attack animation", base.Code)
+	}
+	name := NormalizeClassName(class)
+	frames, ok := attackFrames[name][wclass]
+	if !ok {
+		return nil, fmt.Errorf("no %s attack animation for %s", wclass, class)
+	}
+
+	bps := []Breakpoint{{Value: 0, Frames: framesPerAttack(frames, base.Speed, 0)}}
+	for ias := 1; ias <= maxSearchIAS; ias++ {
+		fpa := framesPerAttack(frames, base.Speed, ias)
+		if fpa < bps[len(bps)-1].Frames {
+			bps = append(bps, Breakpoint{Value: ias, Frames: fpa})
+		}
+	}
+	return bps, nil
+}
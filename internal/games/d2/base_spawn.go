@@ -0,0 +1,31 @@
+package d2
+
+// QualitySpawn describes which item qualities a base can drop as
+type QualitySpawn struct {
+	CanBeMagic  bool
+	CanBeRare   bool
+	CanBeUnique bool
+}
+
+// ResolveQualitySpawn combines the base's own spawn flags with its item type's
+// CanBeMagic/CanBeRare flags. A base that never drops (not spawnable, quest
+// item, rarity 0) can't be any quality. Rare and unique both require the type
+// to allow magic. itemType may be nil, in which case the type flags default to
+// true like the item_types column defaults.
+func ResolveQualitySpawn(base *ItemBase, itemType *ItemType) QualitySpawn {
+	if base == nil || !base.Spawnable || base.QuestItem || base.Rarity <= 0 {
+		return QualitySpawn{}
+	}
+
+	canBeMagic, canBeRare := true, true
+	if itemType != nil {
+		canBeMagic = itemType.CanBeMagic
+		canBeRare = itemType.CanBeRare
+	}
+
+	return QualitySpawn{
+		CanBeMagic:  canBeMagic,
+		CanBeRare:   canBeMagic && canBeRare,
+		CanBeUnique: canBeMagic,
+	}
+}
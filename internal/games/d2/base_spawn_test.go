@@ -0,0 +1,32 @@
+package d2
+
+import "testing"
+
+func TestResolveQualitySpawn(t *testing.T) {
+	dropped := func(name string) *ItemBase { return &ItemBase{Name: name, Spawnable: true, Rarity: 3} }
+	all := QualitySpawn{CanBeMagic: true, CanBeRare: true, CanBeUnique: true}
+
+	tests := []struct {
+		name     string
+		base     *ItemBase
+		itemType *ItemType
+		want     QualitySpawn
+	}{
+		{"armor type", dropped("Mage Plate"), &ItemType{Code: "tors", CanBeMagic: true, CanBeRare: true}, all},
+		{"type that can't be magic", dropped("El Rune"), &ItemType{Code: "rune"}, QualitySpawn{}},
+		{"magic but not rare", dropped("Grand Charm"), &ItemType{Code: "lcha", CanBeMagic: true}, QualitySpawn{CanBeMagic: true, CanBeUnique: true}},
+		{"rare flag without magic", dropped("Odd Base"), &ItemType{Code: "odd", CanBeRare: true}, QualitySpawn{}},
+		{"unknown type", dropped("Cap"), nil, all},
+		{"not spawnable", &ItemBase{Name: "Hellforge Hammer", Rarity: 3}, &ItemType{CanBeMagic: true, CanBeRare: true}, QualitySpawn{}},
+		{"quest item", &ItemBase{Name: "Horadric Staff", Spawnable: true, QuestItem: true, Rarity: 3}, &ItemType{CanBeMagic: true, CanBeRare: true}, QualitySpawn{}},
+		{"rarity 0", &ItemBase{Name: "Never Drops", Spawnable: true}, &ItemType{CanBeMagic: true, CanBeRare: true}, QualitySpawn{}},
+		{"nil base", nil, nil, QualitySpawn{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveQualitySpawn(tt.base, tt.itemType); got != tt.want {
+				t.Errorf("ResolveQualitySpawn = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package d2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryByIDs runs a query taking an id array as $1 and calls scan for each row
+func (r *Repository) queryByIDs(ctx context.Context, what, sql string, ids []int, scan func(row pgx.Row) error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(ctx, sql, ids)
+	if err != nil {
+		return fmt.Errorf("get %s by ids failed: %w", what, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("scan %s failed: %w", what, err)
+		}
+	}
+	return rows.Err()
+}
+
+// GetUniqueItemsByIDs retrieves unique items for the given IDs in one query,
+// keyed by ID. IDs with no matching row are omitted from the map.
+func (r *Repository) GetUniqueItemsByIDs(ctx context.Context, ids []int) (map[int]*UniqueItem, error) {
+	result := make(map[int]*UniqueItem)
+	err := r.queryByIDs(ctx, "unique items", `SELECT `+uniqueItemColumns+` FROM `+tableUniqueItems+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanUniqueItem(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetSetItemsByIDs retrieves set items for the given IDs in one query, keyed
+// by ID
+func (r *Repository) GetSetItemsByIDs(ctx context.Context, ids []int) (map[int]*SetItem, error) {
+	result := make(map[int]*SetItem)
+	err := r.queryByIDs(ctx, "set items", `SELECT `+setItemColumns+` FROM `+tableSetItems+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanSetItem(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRunewordsByIDs retrieves runewords for the given IDs in one query, keyed
+// by ID
+func (r *Repository) GetRunewordsByIDs(ctx context.Context, ids []int) (map[int]*Runeword, error) {
+	result := make(map[int]*Runeword)
+	err := r.queryByIDs(ctx, "runewords", `SELECT `+runewordColumns+` FROM `+tableRunewords+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanRuneword(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRunesByIDs retrieves runes for the given IDs in one query, keyed by ID
+func (r *Repository) GetRunesByIDs(ctx context.Context, ids []int) (map[int]*Rune, error) {
+	result := make(map[int]*Rune)
+	err := r.queryByIDs(ctx, "runes", `SELECT `+runeColumns+` FROM `+tableRunes+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanRune(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetGemsByIDs retrieves gems for the given IDs in one query, keyed by ID
+func (r *Repository) GetGemsByIDs(ctx context.Context, ids []int) (map[int]*Gem, error) {
+	result := make(map[int]*Gem)
+	err := r.queryByIDs(ctx, "gems", `SELECT `+gemColumns+` FROM `+tableGems+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanGem(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetItemBasesByIDs retrieves base items (including quest items) for the
+// given IDs in one query, keyed by ID
+func (r *Repository) GetItemBasesByIDs(ctx context.Context, ids []int) (map[int]*ItemBase, error) {
+	result := make(map[int]*ItemBase)
+	err := r.queryByIDs(ctx, "item bases", `SELECT `+itemBaseColumns+` FROM `+tableItemBases+` WHERE id = ANY($1)`, ids,
+		func(row pgx.Row) error {
+			item, err := scanItemBase(row)
+			if err == nil {
+				result[item.ID] = item
+			}
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetFullItemTypesByCodes retrieves full item types for the given codes in one
+// query, keyed by code. Unlike GetItemTypesByCodes it returns every column.
+func (r *Repository) GetFullItemTypesByCodes(ctx context.Context, codes []string) (map[string]*ItemType, error) {
+	result := make(map[string]*ItemType)
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+itemTypeColumns+` FROM `+tableItemTypes+` WHERE code = ANY($1)`, codes)
+	if err != nil {
+		return nil, fmt.Errorf("get item types by codes failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		it, err := scanItemType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan item type failed: %w", err)
+		}
+		result[it.Code] = it
+	}
+	return result, rows.Err()
+}
+
+// GetBasesForRunewords retrieves the valid bases of several runewords in one
+// query, keyed by runeword ID, in the same order as GetBasesForRuneword
+func (r *Repository) GetBasesForRunewords(ctx context.Context, runewordIDs []int) (map[int][]RunewordBase, error) {
+	result := make(map[int][]RunewordBase)
+	err := r.queryByIDs(ctx, "runeword bases", `
+		SELECT id, runeword_id, item_base_id, item_base_code, item_base_name, category, max_sockets, required_sockets, created_at
+		FROM `+tableRunewordBases+`
+		WHERE runeword_id = ANY($1)
+		ORDER BY runeword_id, category, item_base_name`, runewordIDs,
+		func(row pgx.Row) error {
+			var rb RunewordBase
+			if err := row.Scan(&rb.ID, &rb.RunewordID, &rb.ItemBaseID, &rb.ItemBaseCode, &rb.ItemBaseName, &rb.Category, &rb.MaxSockets, &rb.RequiredSockets, &rb.CreatedAt); err != nil {
+				return err
+			}
+			result[rb.RunewordID] = append(result[rb.RunewordID], rb)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
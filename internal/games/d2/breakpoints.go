@@ -0,0 +1,117 @@
+package d2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Breakpoint is a speed stat threshold and the animation frames it yields
+type Breakpoint struct {
+	Value  int `json:"value"`  // Minimum stat value (e.g. 63 for 63% FCR)
+	Frames int `json:"frames"` // Animation frames at this value
+}
+
+// BreakpointTables maps class -> stat code -> breakpoints sorted by value.
+// Classes use the lowercase names from NormalizeClassName.
+type BreakpointTables map[string]map[string][]Breakpoint
+
+// DefaultBreakpointTables holds the FCR and FHR breakpoints for each class in
+// human form. Warlock tables are not known yet.
+var DefaultBreakpointTables = BreakpointTables{
+	"amazon": {
+		"fcr": {{0, 19}, {7, 18}, {14, 17}, {22, 16}, {32, 15}, {48, 14}, {68, 13}, {99, 12}, {152, 11}},
+		"fhr": {{0, 11}, {6, 10}, {13, 9}, {20, 8}, {32, 7}, {52, 6}, {86, 5}, {174, 4}, {600, 3}},
+	},
+	"assassin": {
+		"fcr": {{0, 16}, {8, 15}, {16, 14}, {27, 13}, {42, 12}, {65, 11}, {102, 10}, {174, 9}},
+		"fhr": {{0, 9}, {7, 8}, {15, 7}, {27, 6}, {48, 5}, {86, 4}, {200, 3}},
+	},
+	"barbarian": {
+		"fcr": {{0, 13}, {9, 12}, {20, 11}, {37, 10}, {63, 9}, {105, 8}, {200, 7}},
+		"fhr": {{0, 9}, {7, 8}, {15, 7}, {27, 6}, {48, 5}, {86, 4}, {200, 3}},
+	},
+	"druid": {
+		"fcr": {{0, 18}, {4, 17}, {10, 16}, {19, 15}, {30, 14}, {46, 13}, {68, 12}, {99, 11}, {163, 10}},
+		"fhr": {{0, 13}, {3, 12}, {7, 11}, {13, 10}, {19, 9}, {29, 8}, {42, 7}, {63, 6}, {99, 5}, {174, 4}, {456, 3}},
+	},
+	"necromancer": {
+		"fcr": {{0, 15}, {9, 14}, {18, 13}, {30, 12}, {48, 11}, {75, 10}, {125, 9}},
+		"fhr": {{0, 13}, {5, 12}, {10, 11}, {16, 10}, {26, 9}, {39, 8}, {56, 7}, {86, 6}, {152, 5}, {377, 4}},
+	},
+	"paladin": {
+		"fcr": {{0, 15}, {9, 14}, {18, 13}, {30, 12}, {48, 11}, {75, 10}, {125, 9}},
+		"fhr": {{0, 9}, {7, 8}, {15, 7}, {27, 6}, {48, 5}, {86, 4}, {200, 3}},
+	},
+	"sorceress": {
+		"fcr": {{0, 13}, {9, 12}, {20, 11}, {37, 10}, {63, 9}, {105, 8}, {200, 7}},
+		"fhr": {{0, 15}, {5, 14}, {9, 13}, {14, 12}, {20, 11}, {30, 10}, {42, 9}, {60, 8}, {86, 7}, {142, 6}, {280, 5}},
+	},
+}
+
+// LoadBreakpointTables reads breakpoint tables from JSON shaped like
+// {"sorceress": {"fcr": [{"value": 0, "frames": 13}, ...]}}. Class names and
+// stat codes are normalized and each table is sorted by value.
+func LoadBreakpointTables(r io.Reader) (BreakpointTables, error) {
+	var raw BreakpointTables
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode breakpoint tables: %w", err)
+	}
+
+	tables := make(BreakpointTables, len(raw))
+	for class, stats := range raw {
+		name := NormalizeClassName(class)
+		if name == "" {
+			return nil, fmt.Errorf("unknown class in breakpoint tables: %s", class)
+		}
+		tables[name] = make(map[string][]Breakpoint, len(stats))
+		for stat, bps := range stats {
+			sorted := append([]Breakpoint(nil), bps...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+			tables[name][StatCodeGroup(stat)[0]] = sorted
+		}
+	}
+	return tables, nil
+}
+
+// Table returns the breakpoints for a class and stat. Class codes ("sor") and
+// stat aliases ("cast1") are accepted.
+func (t BreakpointTables) Table(class, statCode string) ([]Breakpoint, error) {
+	name := NormalizeClassName(class)
+	if name == "" {
+		return nil, fmt.Errorf("unknown class: %s", class)
+	}
+	stat := StatCodeGroup(statCode)[0]
+	bps, ok := t[name][stat]
+	if !ok || len(bps) == 0 {
+		return nil, fmt.Errorf("no %s breakpoints for %s", stat, name)
+	}
+	return bps, nil
+}
+
+// Next returns the breakpoint currently reached and the next one above current.
+// next is nil when current is already at or past the last breakpoint.
+func (t BreakpointTables) Next(class, statCode string, current int) (reached Breakpoint, next *Breakpoint, err error) {
+	bps, err := t.Table(class, statCode)
+	if err != nil {
+		return Breakpoint{}, nil, err
+	}
+
+	reached = bps[0]
+	for i, bp := range bps {
+		if bp.Value <= current {
+			reached = bp
+			continue
+		}
+		return reached, &bps[i], nil
+	}
+	return reached, nil, nil
+}
+
+// NextBreakpoint returns the next breakpoint above current from the default
+// tables, or nil when current is already past the last one.
+func NextBreakpoint(class, statCode string, current int) (*Breakpoint, error) {
+	_, next, err := DefaultBreakpointTables.Next(class, statCode, current)
+	return next, err
+}
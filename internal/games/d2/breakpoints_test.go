@@ -0,0 +1,59 @@
+package d2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNextBreakpointSorceressFCR(t *testing.T) {
+	tests := []struct {
+		class, stat string
+		current     int
+		want        int // 0 when past the last breakpoint
+	}{
+		{"sorceress", "fcr", 62, 63},
+		{"sorceress", "fcr", 63, 105},
+		{"sor", "cast2", 104, 105},
+		{"Sorceress", "fcr", 0, 9},
+		{"sorceress", "fcr", 200, 0},
+	}
+	for _, tt := range tests {
+		next, err := NextBreakpoint(tt.class, tt.stat, tt.current)
+		if err != nil {
+			t.Fatalf("NextBreakpoint(%s, %s, %d): %v", tt.class, tt.stat, tt.current, err)
+		}
+		switch {
+		case tt.want == 0 && next != nil:
+			t.Errorf("NextBreakpoint(%s, %s, %d) = %+v, want none", tt.class, tt.stat, tt.current, *next)
+		case tt.want != 0 && (next == nil || next.Value != tt.want):
+			t.Errorf("NextBreakpoint(%s, %s, %d) = %+v, want %d", tt.class, tt.stat, tt.current, next, tt.want)
+		}
+	}
+
+	reached, next, _ := DefaultBreakpointTables.Next("sorceress", "fcr", 63)
+	if reached != (Breakpoint{63, 9}) || next == nil || *next != (Breakpoint{105, 8}) {
+		t.Errorf("Next at 63%% FCR = %+v, %+v; want 63%% at 9 frames, then 105%% at 8", reached, next)
+	}
+
+	if _, err := NextBreakpoint("sorceress", "ias", 0); err == nil {
+		t.Error("expected an error for a stat without breakpoints")
+	}
+	if _, err := NextBreakpoint("paladin-ish", "fcr", 0); err == nil {
+		t.Error("expected an error for an unknown class")
+	}
+}
+
+func TestLoadBreakpointTablesSortsAndNormalizes(t *testing.T) {
+	tables, err := LoadBreakpointTables(strings.NewReader(`{"Sor": {"cast1": [{"value": 63, "frames": 9}, {"value": 0, "frames": 13}]}}`))
+	if err != nil {
+		t.Fatalf("LoadBreakpointTables: %v", err)
+	}
+	_, next, err := tables.Next("sorceress", "fcr", 10)
+	if err != nil || next == nil || next.Value != 63 {
+		t.Errorf("Next = %+v, %v; want 63", next, err)
+	}
+
+	if _, err := LoadBreakpointTables(strings.NewReader(`{"bowazon": {"fcr": []}}`)); err == nil {
+		t.Error("expected an error for an unknown class")
+	}
+}
@@ -0,0 +1,69 @@
+package d2
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChangeEntry is one catalog row in the changes feed
+type ChangeEntry struct {
+	Type      string // unique, set, runeword, rune, gem, base, quest
+	ID        int
+	Name      string
+	UpdatedAt time.Time
+}
+
+// ChangeCursor is the last entry a feed page returned. Rows often share an
+// updated_at (one import stamps many rows), so paging by timestamp alone
+// could skip rows; the type and ID break ties.
+type ChangeCursor struct {
+	Type string
+	ID   int
+}
+
+// changesSQL lists every catalog row as (type, id, name, updated_at)
+var changesSQL = fmt.Sprintf(`
+	SELECT type, id, name, updated_at FROM (
+		SELECT 'unique' AS type, id, name, updated_at FROM %s
+		UNION ALL SELECT 'set', id, name, updated_at FROM %s
+		UNION ALL SELECT 'runeword', id, display_name, updated_at FROM %s
+		UNION ALL SELECT 'rune', id, name, updated_at FROM %s
+		UNION ALL SELECT 'gem', id, name, updated_at FROM %s
+		UNION ALL SELECT CASE WHEN quest_item IS TRUE THEN 'quest' ELSE 'base' END, id, name, updated_at FROM %s
+	) changes
+	WHERE updated_at IS NOT NULL`,
+	QualifiedTable("unique_items"), QualifiedTable("set_items"), QualifiedTable("runewords"),
+	QualifiedTable("runes"), QualifiedTable("gems"), QualifiedTable("item_bases"))
+
+// GetChangedSince returns up to limit catalog rows updated after since, oldest
+// first. With a cursor, rows at exactly since that sort after it are included
+// too, so a client can resume from the last entry of the previous page.
+// Deleted rows don't appear.
+func (r *Repository) GetChangedSince(ctx context.Context, since time.Time, after *ChangeCursor, limit int) ([]ChangeEntry, error) {
+	sql := changesSQL
+	args := []interface{}{since, limit}
+	if after != nil {
+		sql += ` AND (updated_at, type, id) > ($1, $3, $4)`
+		args = append(args, after.Type, after.ID)
+	} else {
+		sql += ` AND updated_at > $1`
+	}
+	sql += ` ORDER BY updated_at, type, id LIMIT $2`
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("changes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []ChangeEntry
+	for rows.Next() {
+		var e ChangeEntry
+		if err := rows.Scan(&e.Type, &e.ID, &e.Name, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
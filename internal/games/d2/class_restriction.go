@@ -0,0 +1,60 @@
+package d2
+
+import "strings"
+
+// classRestrictionCodes maps the short class codes used by itemtypes.txt
+// (and the class skill stat codes) to full lowercase class names.
+var classRestrictionCodes = map[string]string{
+	"ama": "amazon",
+	"sor": "sorceress",
+	"nec": "necromancer",
+	"pal": "paladin",
+	"bar": "barbarian",
+	"dru": "druid",
+	"ass": "assassin",
+	"war": "warlock",
+}
+
+// NormalizeClassName converts a class code ("sor") or name ("Sorceress") into
+// the lowercase class name used by ItemBase.ClassSpecific. Returns "" for
+// unknown values.
+func NormalizeClassName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	if name, ok := classRestrictionCodes[s]; ok {
+		return name
+	}
+	for _, name := range classRestrictionCodes {
+		if name == s {
+			return name
+		}
+	}
+	return ""
+}
+
+// ResolveClassRestriction returns the lowercase class an item base is restricted
+// to. The base's own ClassSpecific (derived from HTML type tags) wins; otherwise
+// the item type's ClassRestriction is used. Returns "" for unrestricted items.
+func ResolveClassRestriction(base *ItemBase, itemType *ItemType) string {
+	if base != nil {
+		if class := NormalizeClassName(base.ClassSpecific); class != "" {
+			return class
+		}
+	}
+	if itemType != nil {
+		return NormalizeClassName(itemType.ClassRestriction)
+	}
+	return ""
+}
+
+// ClassRestrictionLabel returns the display label for a class restriction,
+// e.g. "sorceress" -> "Sorceress Only". Returns "" for unrestricted items.
+func ClassRestrictionLabel(class string) string {
+	class = NormalizeClassName(class)
+	if class == "" {
+		return ""
+	}
+	return strings.ToUpper(class[:1]) + class[1:] + " Only"
+}
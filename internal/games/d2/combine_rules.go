@@ -0,0 +1,170 @@
+package d2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CombineMode controls how component properties merge into the result
+type CombineMode string
+
+const (
+	// CombineAllEqual merges only when every component has the same min/max,
+	// and the result takes that shared range
+	CombineAllEqual CombineMode = "all-equal"
+
+	// CombineAdditive always merges when every component is present, and the
+	// result takes the sum of the component ranges
+	CombineAdditive CombineMode = "additive"
+)
+
+// CombineRule describes component stat codes that collapse into a single
+// display code, e.g. str+dex+vit+enr with equal values -> all-stats.
+type CombineRule struct {
+	Name       string      `json:"name"`
+	Components []string    `json:"components"`
+	Result     string      `json:"result"`
+	Mode       CombineMode `json:"mode"`
+}
+
+// DefaultCombineRules are the combine rules applied during import
+var DefaultCombineRules = []CombineRule{
+	{
+		Name:       "all attributes",
+		Components: []string{"str", "dex", "vit", "enr"},
+		Result:     "all-stats",
+		Mode:       CombineAllEqual,
+	},
+	{
+		Name:       "all resistances",
+		Components: []string{"res-fire", "res-cold", "res-ltng", "res-pois"},
+		Result:     "res-all",
+		Mode:       CombineAllEqual,
+	},
+}
+
+// LoadCombineRules reads a JSON array of combine rules and validates them
+func LoadCombineRules(r io.Reader) ([]CombineRule, error) {
+	var rules []CombineRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("decode combine rules: %w", err)
+	}
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("combine rule %d: %w", i, err)
+		}
+	}
+	return rules, nil
+}
+
+// validate checks that a rule is well-formed
+func (r CombineRule) validate() error {
+	if r.Result == "" {
+		return fmt.Errorf("missing result code")
+	}
+	if len(r.Components) < 2 {
+		return fmt.Errorf("%s: needs at least two components", r.Result)
+	}
+	seen := make(map[string]bool, len(r.Components))
+	for _, code := range r.Components {
+		if seen[code] {
+			return fmt.Errorf("%s: duplicate component %s", r.Result, code)
+		}
+		seen[code] = true
+	}
+	switch r.Mode {
+	case CombineAllEqual, CombineAdditive:
+	default:
+		return fmt.Errorf("%s: unknown mode %q", r.Result, r.Mode)
+	}
+	return nil
+}
+
+// ApplyCombineRules applies each rule in order to props. When a rule matches,
+// its components are removed and the combined property is inserted at the
+// position of the first component. Properties are returned unchanged when no
+// rule matches.
+func ApplyCombineRules(props []Property, rules []CombineRule, translator *PropertyTranslator) []Property {
+	for _, rule := range rules {
+		props = applyCombineRule(props, rule, translator)
+	}
+	return props
+}
+
+// applyCombineRule applies a single rule to props
+func applyCombineRule(props []Property, rule CombineRule, translator *PropertyTranslator) []Property {
+	indexes := make(map[string]int, len(rule.Components))
+	for _, code := range rule.Components {
+		indexes[code] = -1
+	}
+	for i, p := range props {
+		// Only one option of an "or" bonus applies, so it never combines
+		if len(p.Alternatives) > 0 {
+			continue
+		}
+		if idx, ok := indexes[p.Code]; ok && idx == -1 {
+			indexes[p.Code] = i
+		}
+	}
+
+	// Check all components are present
+	firstIdx := len(props)
+	for _, idx := range indexes {
+		if idx == -1 {
+			return props
+		}
+		if idx < firstIdx {
+			firstIdx = idx
+		}
+	}
+
+	// Set bonuses only combine within the same equipped-count tier
+	ref := props[indexes[rule.Components[0]]]
+	for _, code := range rule.Components[1:] {
+		if props[indexes[code]].ItemCount != ref.ItemCount {
+			return props
+		}
+	}
+	combined := Property{Code: rule.Result, ItemCount: ref.ItemCount}
+	switch rule.Mode {
+	case CombineAllEqual:
+		for _, code := range rule.Components[1:] {
+			p := props[indexes[code]]
+			if p.Min != ref.Min || p.Max != ref.Max {
+				return props
+			}
+		}
+		combined.Min = ref.Min
+		combined.Max = ref.Max
+	case CombineAdditive:
+		for _, code := range rule.Components {
+			p := props[indexes[code]]
+			combined.Min += p.Min
+			combined.Max += p.Max
+		}
+	default:
+		return props
+	}
+	if translator != nil {
+		translator.EnrichProperty(&combined)
+	}
+
+	removeSet := make(map[int]bool, len(indexes))
+	for _, idx := range indexes {
+		removeSet[idx] = true
+	}
+
+	result := make([]Property, 0, len(props)-len(indexes)+1)
+	for i, p := range props {
+		if removeSet[i] {
+			if i == firstIdx {
+				result = append(result, combined)
+			}
+			continue
+		}
+		result = append(result, p)
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package d2
+
+import (
+	"reflect"
+	"testing"
+)
+
+// combineAllAttributes is the hardcoded combiner the importers used before
+// combine rules, kept to check the "all attributes" rule against it
+func combineAllAttributes(props []Property, translator *PropertyTranslator) []Property {
+	attrCodes := map[string]int{"str": -1, "dex": -1, "vit": -1, "enr": -1}
+	for i, p := range props {
+		if _, ok := attrCodes[p.Code]; ok {
+			attrCodes[p.Code] = i
+		}
+	}
+	for _, idx := range attrCodes {
+		if idx == -1 {
+			return props
+		}
+	}
+
+	ref := props[attrCodes["str"]]
+	for _, code := range []string{"dex", "vit", "enr"} {
+		p := props[attrCodes[code]]
+		if p.Min != ref.Min || p.Max != ref.Max {
+			return props
+		}
+	}
+
+	firstIdx := len(props)
+	for _, idx := range attrCodes {
+		if idx < firstIdx {
+			firstIdx = idx
+		}
+	}
+	removeSet := map[int]bool{
+		attrCodes["str"]: true,
+		attrCodes["dex"]: true,
+		attrCodes["vit"]: true,
+		attrCodes["enr"]: true,
+	}
+
+	allStats := Property{Code: "all-stats", Min: ref.Min, Max: ref.Max}
+	translator.EnrichProperty(&allStats)
+
+	result := make([]Property, 0, len(props)-3)
+	for i, p := range props {
+		if removeSet[i] {
+			if i == firstIdx {
+				result = append(result, allStats)
+			}
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func TestApplyCombineRulesMatchesCombineAllAttributes(t *testing.T) {
+	attr := func(code string, min, max int) Property { return Property{Code: code, Min: min, Max: max} }
+	ed := attr("dmg%", 200, 200)
+	fcr := attr("fcr", 20, 20)
+
+	tests := []struct {
+		name   string
+		props  []Property
+		merged bool
+	}{
+		{"empty", nil, false},
+		{"all equal", []Property{attr("str", 10, 10), attr("dex", 10, 10), attr("vit", 10, 10), attr("enr", 10, 10)}, true},
+		{"all equal ranges", []Property{attr("str", 5, 10), attr("dex", 5, 10), attr("vit", 5, 10), attr("enr", 5, 10)}, true},
+		{"interleaved out of order", []Property{ed, attr("vit", 3, 3), fcr, attr("str", 3, 3), attr("enr", 3, 3), attr("dex", 3, 3)}, true},
+		{"one differs", []Property{attr("str", 10, 10), attr("dex", 10, 10), attr("vit", 10, 10), attr("enr", 15, 15)}, false},
+		{"max differs", []Property{attr("str", 5, 10), attr("dex", 5, 10), attr("vit", 5, 10), attr("enr", 5, 12)}, false},
+		{"one missing", []Property{ed, attr("str", 10, 10), attr("dex", 10, 10), attr("vit", 10, 10)}, false},
+		{"no attributes", []Property{ed, fcr}, false},
+	}
+
+	tr := NewPropertyTranslator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := combineAllAttributes(append([]Property(nil), tt.props...), tr)
+			got := ApplyCombineRules(append([]Property(nil), tt.props...), DefaultCombineRules, tr)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyCombineRules = %+v\nwant %+v", got, want)
+			}
+			if merged := len(got) < len(tt.props); merged != tt.merged {
+				t.Errorf("merged = %v, want %v", merged, tt.merged)
+			}
+		})
+	}
+}
+
+func TestAllResistancesCombineWithinSetBonusTiers(t *testing.T) {
+	res := func(itemCount, value int, codes ...string) []Property {
+		props := make([]Property, 0, len(codes))
+		for _, code := range codes {
+			props = append(props, Property{Code: code, Min: value, Max: value, ItemCount: itemCount})
+		}
+		return props
+	}
+	all := []string{"res-fire", "res-cold", "res-ltng", "res-pois"}
+	tr := NewPropertyTranslator()
+	combine := func(props []Property) []Property { return ApplyCombineRules(props, DefaultCombineRules, tr) }
+
+	// Partial bonuses: four resistances at 2 pieces combine, a tier split
+	// between 2 and 3 pieces doesn't, and three resistances never do
+	partial := combine(append(res(2, 15, all...), res(3, 10, all[:3]...)...))
+	if len(partial) != 4 || partial[0].Code != "res-all" || partial[0].ItemCount != 2 || partial[0].Min != 15 {
+		t.Fatalf("partial bonuses = %+v, want res-all at 2 pieces and three single resistances at 3", partial)
+	}
+	split := combine(append(res(2, 15, all[:2]...), res(3, 15, all[2:]...)...))
+	if len(split) != 4 {
+		t.Errorf("resistances split across tiers combined: %+v", split)
+	}
+
+	full := combine(res(0, 25, all...))
+	if len(full) != 1 || full[0].Code != "res-all" || full[0].DisplayText != "All Resistances +25" {
+		t.Fatalf("full bonuses = %+v, want All Resistances +25", full)
+	}
+
+	tiers := SetBonusTiers(&SetBonus{Name: "Test Set", PartialBonuses: partial, FullBonuses: full}, 4)
+	if len(tiers) != 3 {
+		t.Fatalf("got %d tiers, want 2, 3 and full", len(tiers))
+	}
+	if p := tiers[0].Properties; tiers[0].ItemCount != 2 || len(p) != 1 || p[0].DisplayText != "All Resistances +15" {
+		t.Errorf("2-piece tier = %+v, want All Resistances +15", tiers[0])
+	}
+	if p := tiers[1].Properties; tiers[1].ItemCount != 3 || len(p) != 3 {
+		t.Errorf("3-piece tier = %+v, want the three single resistances", tiers[1])
+	}
+	if p := tiers[2].Properties; !tiers[2].Full || len(p) != 1 || p[0].DisplayText != "All Resistances +25" {
+		t.Errorf("full tier = %+v, want All Resistances +25", tiers[2])
+	}
+}
@@ -0,0 +1,29 @@
+package d2
+
+// costMultFixedPointOne is the value of a 1.0 multiplier when cost_mult is
+// stored in 1024-based fixed point. Smaller values are plain integer multipliers
+// (the uniqueitems.txt/setitems.txt convention, e.g. 5).
+const costMultFixedPointOne = 1024
+
+// ComputeItemCost returns the NPC value of an item: the base cost multiplied by
+// costMult, plus costAdd. A zero costMult leaves the base cost unchanged, so a
+// plain base is ComputeItemCost(base.Cost, 0, 0).
+func ComputeItemCost(baseCost, costMult, costAdd int) int {
+	if baseCost < 0 {
+		baseCost = 0
+	}
+
+	cost := baseCost
+	switch {
+	case costMult >= costMultFixedPointOne:
+		cost = baseCost * costMult / costMultFixedPointOne
+	case costMult > 0:
+		cost = baseCost * costMult
+	}
+
+	cost += costAdd
+	if cost < 0 {
+		return 0
+	}
+	return cost
+}
@@ -0,0 +1,25 @@
+package d2
+
+import "testing"
+
+func TestComputeItemCost(t *testing.T) {
+	tests := []struct {
+		name                        string
+		baseCost, costMult, costAdd int
+		want                        int
+	}{
+		{"plain base", 2500, 0, 0, 2500},
+		{"unique integer multiplier", 2500, 5, 5000, 17500},
+		{"unique fixed-point multiplier", 2500, 1536, 5000, 8750},
+		{"fixed-point 1.0", 2500, 1024, 0, 2500},
+		{"negative base", -10, 5, 100, 100},
+		{"never below zero", 100, 0, -500, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeItemCost(tt.baseCost, tt.costMult, tt.costAdd); got != tt.want {
+				t.Errorf("ComputeItemCost(%d, %d, %d) = %d, want %d", tt.baseCost, tt.costMult, tt.costAdd, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package d2
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX is the query surface the repository uses. *pgxpool.Pool and pgx.Tx
+// both implement it, so the same repository methods run standalone or
+// inside a transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// WithTx returns a repository whose queries run in tx
+func (r *Repository) WithTx(tx pgx.Tx) *Repository {
+	return &Repository{db: tx, skills: r.skills}
+}
+
+// InTx runs fn with a repository bound to a new transaction, committing when
+// fn returns nil and rolling back otherwise. Called on a repository that is
+// already in a transaction, it nests with a savepoint.
+func (r *Repository) InTx(ctx context.Context, fn func(tx *Repository) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(r.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,34 @@
+package d2
+
+import "fmt"
+
+// duplicateTracker detects rows in a single import file that resolve to the
+// same upsert key (code or name). The later row still wins the upsert, but the
+// conflict is logged and counted in ImportResult.DuplicateCodes so data
+// mistakes don't silently produce a wrong catalog.
+type duplicateTracker struct {
+	file   string
+	seen   map[string]string // key -> first row name
+	result *ImportResult
+}
+
+// newDuplicateTracker creates a tracker for one import file
+func newDuplicateTracker(file string, result *ImportResult) *duplicateTracker {
+	return &duplicateTracker{
+		file:   file,
+		seen:   make(map[string]string),
+		result: result,
+	}
+}
+
+// check records key for row and reports whether an earlier row already used it
+func (d *duplicateTracker) check(key, row string) bool {
+	first, ok := d.seen[key]
+	if !ok {
+		d.seen[key] = row
+		return false
+	}
+	fmt.Printf("    WARNING: duplicate key '%s' in %s: '%s' overwrites '%s'\n", key, d.file, row, first)
+	d.result.DuplicateCodes++
+	return true
+}
@@ -0,0 +1,58 @@
+package d2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+)
+
+// uniqueArticle renders a uniques.html item in the source site's markup
+func uniqueArticle(name, base string) string {
+	return fmt.Sprintf(`<article class="element-item">
+  <h3 class="z-sort-name"><a class="z-uniques-title">%s</a></h3>
+  <h4>Unique<br><span class="z-white">%s</span></h4>
+  <p class="z-smallstats">+2 To All Skills</p>
+</article>`, name, base)
+}
+
+func TestDuplicateTrackerCountsRepeatedKeys(t *testing.T) {
+	result := &ImportResult{}
+	d := newDuplicateTracker("misc.html", result)
+
+	for _, key := range []string{"rune:r30", "rune:r31", "rune:r30", "rune:r30"} {
+		d.check(key, "Ber Rune")
+	}
+	if result.DuplicateCodes != 2 {
+		t.Errorf("DuplicateCodes = %d, want 2", result.DuplicateCodes)
+	}
+	if d.check("rune:r32", "Lo Rune") {
+		t.Error("new key reported as duplicate")
+	}
+}
+
+func TestImportUniquesReportsDuplicateInFile(t *testing.T) {
+	dir := t.TempDir()
+	page := "<html><body>" + strings.Join([]string{
+		uniqueArticle("Harlequin Crest", "Shako"),
+		uniqueArticle("Tyrael's Might", "Sacred Armor"),
+		uniqueArticle("Harlequin Crest", "Shako"),
+	}, "\n") + "</body></html>"
+	if err := os.WriteFile(filepath.Join(dir, "uniques.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	repo := NewRepository(dbtest.NewFake())
+	h := NewHTMLImporterV2(repo, NewStatRegistry(repo), nil, true)
+	result := &ImportResult{}
+	if err := h.importUniques(context.Background(), dir, result); err != nil {
+		t.Fatalf("importUniques: %v", err)
+	}
+	if result.DuplicateCodes != 1 {
+		t.Errorf("DuplicateCodes = %d, want 1", result.DuplicateCodes)
+	}
+}
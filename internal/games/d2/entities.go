@@ -51,6 +51,11 @@ type Property struct {
 	Max         int    `json:"max"`
 	DisplayText string `json:"displayText,omitempty"`
 	HasRange    bool   `json:"hasRange,omitempty"`
+	ItemCount   int    `json:"item_count,omitempty"` // Set pieces equipped to activate (set bonuses only)
+
+	// Alternatives are the other options of an "X or Y" set bonus; the
+	// property's own fields are the first option. Only one option applies.
+	Alternatives []Property `json:"alternatives,omitempty"`
 }
 
 // ItemType represents an item type/category
@@ -141,6 +146,10 @@ type ItemBase struct {
 	Throwable bool `json:"throwable"`
 	QuestItem bool `json:"quest_item"`
 
+	// Placeholder marks "Expansion"/"Not Used" style rows kept by an import
+	// with placeholders included
+	Placeholder bool `json:"placeholder,omitempty"`
+
 	Rarity int `json:"rarity"`
 	Cost   int `json:"cost"`
 
@@ -279,6 +288,7 @@ type Gem struct {
 	Name    string `json:"name"`
 	GemType string `json:"gem_type"` // amethyst, sapphire, emerald, ruby, diamond, topaz, skull
 	Quality string `json:"quality"`  // chipped, flawed, normal, flawless, perfect
+	IsSkull bool   `json:"is_skull"` // Skulls socket like gems but have their own chain
 
 	WeaponMods []Property `json:"weapon_mods"`
 	HelmMods   []Property `json:"helm_mods"`
@@ -308,22 +318,50 @@ type RunewordBase struct {
 
 // ImportStats tracks import statistics
 type ImportStats struct {
-	Imported int
-	Skipped  int
+	Imported  int `json:"imported"`
+	Skipped   int `json:"skipped"`
+	Unchanged int `json:"unchanged"` // Imported rows an incremental import left untouched
 }
 
 // ImportResult holds all import statistics
 type ImportResult struct {
-	ItemTypes      ImportStats
-	ItemBases      ImportStats
-	UniqueItems    ImportStats
-	SetBonuses     ImportStats
-	SetItems       ImportStats
-	Runewords      ImportStats
-	Runes          ImportStats
-	Gems           ImportStats
-	RunewordBases  ImportStats
-	Stats          ImportStats
-	ImagesUploaded int
-	ImagesMissing  int
+	ItemTypes      ImportStats `json:"item_types"`
+	ItemBases      ImportStats `json:"item_bases"`
+	UniqueItems    ImportStats `json:"unique_items"`
+	SetBonuses     ImportStats `json:"set_bonuses"`
+	SetItems       ImportStats `json:"set_items"`
+	Runewords      ImportStats `json:"runewords"`
+	Runes          ImportStats `json:"runes"`
+	Gems           ImportStats `json:"gems"`
+	RunewordBases  ImportStats `json:"runeword_bases"`
+	Stats          ImportStats `json:"stats"`
+	ImagesUploaded int         `json:"images_uploaded"`
+	ImagesMissing  int         `json:"images_missing"`
+
+	ThumbnailsUploaded int `json:"thumbnails_uploaded"` // Downscaled copies uploaded next to new images
+	ImagesDeduplicated int `json:"images_deduplicated"` // Images whose bytes were already uploaded, URL reused
+
+	PlaceholdersFiltered int `json:"placeholders_filtered"` // Rows skipped by the placeholder name filter
+	PlaceholdersImported int `json:"placeholders_imported"` // Placeholder base rows kept and marked (placeholders included)
+	RuneOrderRepaired    int `json:"rune_order_repaired"`   // Runewords whose stored rune order differed from the source
+	DuplicateCodes       int `json:"duplicate_codes"`       // Rows sharing a code/name with an earlier row in the same file
+	SocketMismatches     int `json:"socket_mismatches"`     // Runewords whose declared socket count differs from their rune count
+
+	RawProperties      int                 `json:"raw_properties"`       // Property lines stored as "raw" (no reverse-translation match)
+	RawPropertySamples []RawPropertySample `json:"raw_property_samples"` // Most frequent unmatched lines, deduplicated and capped
+
+	StaleItems   []StaleItem `json:"stale_items"`   // Active rows not seen in this import
+	StaleRemoved int         `json:"stale_removed"` // Stale rows soft-deleted (only with pruning enabled)
+
+	Errors       []string `json:"errors"`         // Rows or steps that failed, one message each
+	NewStatCodes []string `json:"new_stat_codes"` // Stat codes with no curated stat, auto-registered during import
+
+	Orphans []OrphanReport `json:"orphans"` // Uniques and set items whose base code matches no item base
+}
+
+// StaleItem is a database row that no longer appears in the source data
+type StaleItem struct {
+	Type string `json:"type"` // "uniques", "sets", "runewords", "runes", "gems", "bases"
+	Key  string `json:"key"`  // Code or name the importer upserts by
+	Name string `json:"name"`
 }
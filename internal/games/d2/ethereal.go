@@ -0,0 +1,63 @@
+package d2
+
+// Ethereal items have base defense and damage multiplied by 1.5 (rounded
+// down) and strength/dexterity requirements lowered by 10.
+const (
+	etherealMultNum    = 3
+	etherealMultDen    = 2
+	etherealReqPenalty = 10
+)
+
+// EtherealStats holds the base stats of an item in its ethereal form
+type EtherealStats struct {
+	MinAC         int
+	MaxAC         int
+	MinDam        int
+	MaxDam        int
+	TwoHandMinDam int
+	TwoHandMaxDam int
+	StrReq        int
+	DexReq        int
+}
+
+// CanBeEthereal reports whether a base can drop ethereal. Only armor and
+// weapons with durability qualify; indestructible bases (no durability) and
+// misc items can't be ethereal.
+func CanBeEthereal(base *ItemBase) bool {
+	if base == nil || base.Durability <= 0 {
+		return false
+	}
+	return base.Category == "armor" || base.Category == "weapon"
+}
+
+// ComputeEtherealStats applies the ethereal multiplier to a base's defense
+// and damage ranges and lowers its stat requirements. Returns nil when the
+// base can't be ethereal.
+func ComputeEtherealStats(base *ItemBase) *EtherealStats {
+	if !CanBeEthereal(base) {
+		return nil
+	}
+	return &EtherealStats{
+		MinAC:         etherealValue(base.MinAC),
+		MaxAC:         etherealValue(base.MaxAC),
+		MinDam:        etherealValue(base.MinDam),
+		MaxDam:        etherealValue(base.MaxDam),
+		TwoHandMinDam: etherealValue(base.TwoHandMinDam),
+		TwoHandMaxDam: etherealValue(base.TwoHandMaxDam),
+		StrReq:        etherealRequirement(base.StrReq),
+		DexReq:        etherealRequirement(base.DexReq),
+	}
+}
+
+// etherealValue applies the 1.5x multiplier, rounding down
+func etherealValue(v int) int {
+	return v * etherealMultNum / etherealMultDen
+}
+
+// etherealRequirement lowers a stat requirement, never below zero
+func etherealRequirement(req int) int {
+	if req <= etherealReqPenalty {
+		return 0
+	}
+	return req - etherealReqPenalty
+}
@@ -0,0 +1,43 @@
+package d2
+
+import "testing"
+
+func TestComputeEtherealStats(t *testing.T) {
+	tests := []struct {
+		name string
+		base ItemBase
+		want *EtherealStats
+	}{
+		{
+			"armor",
+			ItemBase{Name: "Sacred Armor", Category: "armor", Durability: 60, MinAC: 487, MaxAC: 600, StrReq: 232},
+			&EtherealStats{MinAC: 730, MaxAC: 900, StrReq: 222},
+		},
+		{
+			"two-handed weapon",
+			ItemBase{Name: "Thresher", Category: "weapon", Durability: 65, TwoHandMinDam: 12, TwoHandMaxDam: 141, StrReq: 152, DexReq: 118},
+			&EtherealStats{TwoHandMinDam: 18, TwoHandMaxDam: 211, StrReq: 142, DexReq: 108},
+		},
+		{
+			"odd values round down and low requirements stop at zero",
+			ItemBase{Name: "Short Sword", Category: "weapon", Durability: 24, MinDam: 2, MaxDam: 7, DexReq: 5},
+			&EtherealStats{MinDam: 3, MaxDam: 10},
+		},
+		{"indestructible", ItemBase{Name: "Phase Blade", Category: "weapon", MinDam: 31, MaxDam: 35}, nil},
+		{"misc", ItemBase{Name: "Jewel", Category: "misc", Durability: 10}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeEtherealStats(&tt.base)
+			if CanBeEthereal(&tt.base) != (tt.want != nil) {
+				t.Errorf("CanBeEthereal = %v, want %v", !(tt.want != nil), tt.want != nil)
+			}
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("got %+v, want nil", got)
+			case tt.want != nil && (got == nil || *got != *tt.want):
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
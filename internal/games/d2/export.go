@@ -0,0 +1,76 @@
+package d2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ExportFormatVersion is bumped when the shape of the catalog export changes
+const ExportFormatVersion = 1
+
+// exportSections maps each export section to its table and the condition for
+// rows currently visible in the API
+var exportSections = map[string]struct {
+	table  string
+	active string
+}{
+	"uniques":     {itemTypeTables["uniques"].table, itemTypeTables["uniques"].active},
+	"sets":        {itemTypeTables["sets"].table, itemTypeTables["sets"].active},
+	"set_bonuses": {QualifiedTable("set_bonuses"), "true"},
+	"runewords":   {itemTypeTables["runewords"].table, itemTypeTables["runewords"].active},
+	"runes":       {itemTypeTables["runes"].table, itemTypeTables["runes"].active},
+	"gems":        {itemTypeTables["gems"].table, itemTypeTables["gems"].active},
+	"bases":       {itemTypeTables["bases"].table, itemTypeTables["bases"].active},
+}
+
+// ExportSections returns the sections of a catalog export in output order
+func ExportSections() []string {
+	return []string{"uniques", "sets", "set_bonuses", "runewords", "runes", "gems", "bases"}
+}
+
+// CheckExportSections verifies each section's table can be read, so an
+// export that can't start fails before its status code is sent
+func (r *Repository) CheckExportSections(ctx context.Context, sections []string) error {
+	for _, section := range sections {
+		s, ok := exportSections[section]
+		if !ok {
+			return fmt.Errorf("unknown export section: %s", section)
+		}
+		if _, err := r.db.Exec(ctx, fmt.Sprintf(`SELECT 1 FROM %s LIMIT 1`, s.table)); err != nil {
+			return fmt.Errorf("export %s failed: %w", section, err)
+		}
+	}
+	return nil
+}
+
+// StreamExportSection calls fn with each visible row of a section as a JSON
+// object keyed by column name, reading from a cursor so the section is never
+// held in memory. Import bookkeeping columns are left out.
+func (r *Repository) StreamExportSection(ctx context.Context, section string, fn func(row json.RawMessage) error) error {
+	s, ok := exportSections[section]
+	if !ok {
+		return fmt.Errorf("unknown export section: %s", section)
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(
+		`SELECT (to_jsonb(t) - 'content_hash')::text FROM %s t WHERE %s ORDER BY id`, s.table, s.active))
+	if err != nil {
+		return fmt.Errorf("export %s failed: %w", section, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("export %s failed: %w", section, err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export %s failed: %w", section, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package d2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FullSet is a set definition with every enabled member item and the bases
+// those items are built on
+type FullSet struct {
+	Name  string
+	Bonus *SetBonus            // nil when the set has no bonus row
+	Items []SetItem            // Ordered by name
+	Bases map[string]*ItemBase // Keyed by base code
+}
+
+// GetFullSet loads a set's bonus, its enabled items and their bases in three
+// queries, whatever the set size. Returns nil when no enabled item belongs to
+// the set.
+func (r *Repository) GetFullSet(ctx context.Context, setName string) (*FullSet, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+setItemColumns+`
+		FROM `+tableSetItems+`
+		WHERE enabled IS NOT FALSE AND LOWER(set_name) = LOWER($1)
+		ORDER BY name`, setName)
+	if err != nil {
+		return nil, fmt.Errorf("get full set items failed: %w", err)
+	}
+	defer rows.Close()
+
+	var items []SetItem
+	for rows.Next() {
+		item, err := scanSetItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan set item failed: %w", err)
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	set := &FullSet{Name: items[0].SetName, Items: items}
+
+	// Set bonuses are optional; items alone still make a set
+	set.Bonus, err = r.GetSetBonusByName(ctx, set.Name)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		codes = append(codes, item.BaseCode)
+	}
+	set.Bases, err = r.GetItemBasesByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
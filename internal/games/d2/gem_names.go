@@ -0,0 +1,136 @@
+package d2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// GemTypeSkull is the gem type used for skulls. Skulls socket like gems but
+// aren't gems: they have their own chipped..perfect chain and mods.
+const GemTypeSkull = "skull"
+
+// GemQualities lists gem (and skull) qualities from lowest to highest tier
+var GemQualities = []string{"chipped", "flawed", "normal", "flawless", "perfect"}
+
+// GemCodeParts is the gem type and quality a game item code maps to
+type GemCodeParts struct {
+	Type    string `json:"type"`
+	Quality string `json:"quality"`
+}
+
+// GemNameTable maps name words and game item codes to gem types and
+// qualities, so gem names can be classified in any language.
+type GemNameTable struct {
+	Types     map[string]string       `json:"types"`     // name word -> gem type ("rubis" -> "ruby")
+	Qualities map[string]string       `json:"qualities"` // name word -> quality ("taillé" -> "chipped")
+	Codes     map[string]GemCodeParts `json:"codes"`     // game code -> parts ("gcr" -> chipped ruby)
+}
+
+// gemCodeLetters is the last letter of the standard gem codes per type
+var gemCodeLetters = map[string]string{
+	"v": "amethyst",
+	"b": "sapphire",
+	"g": "emerald",
+	"r": "ruby",
+	"w": "diamond",
+	"y": "topaz",
+}
+
+// defaultGemCodes builds the standard game codes: "gc?" chipped, "gf?" flawed,
+// "gs?" normal, "gl?"/"gz?" flawless, "gp?" perfect, and "sk?" for skulls.
+func defaultGemCodes() map[string]GemCodeParts {
+	codes := make(map[string]GemCodeParts)
+	qualities := map[string]string{"c": "chipped", "f": "flawed", "s": "normal", "l": "flawless", "p": "perfect"}
+	for letter, gemType := range gemCodeLetters {
+		for q, quality := range qualities {
+			codes["g"+q+letter] = GemCodeParts{Type: gemType, Quality: quality}
+		}
+	}
+	// Amethyst uses "gzv" for flawless
+	codes["gzv"] = GemCodeParts{Type: "amethyst", Quality: "flawless"}
+	delete(codes, "glv")
+
+	codes["skc"] = GemCodeParts{Type: GemTypeSkull, Quality: "chipped"}
+	codes["skf"] = GemCodeParts{Type: GemTypeSkull, Quality: "flawed"}
+	codes["sku"] = GemCodeParts{Type: GemTypeSkull, Quality: "normal"}
+	codes["skl"] = GemCodeParts{Type: GemTypeSkull, Quality: "flawless"}
+	codes["skz"] = GemCodeParts{Type: GemTypeSkull, Quality: "perfect"}
+	return codes
+}
+
+// DefaultGemNameTable classifies English gem names and the standard codes
+var DefaultGemNameTable = &GemNameTable{
+	Types: map[string]string{
+		"amethyst": "amethyst",
+		"sapphire": "sapphire",
+		"emerald":  "emerald",
+		"ruby":     "ruby",
+		"diamond":  "diamond",
+		"topaz":    "topaz",
+		"skull":    GemTypeSkull,
+	},
+	Qualities: map[string]string{
+		"chipped":  "chipped",
+		"flawed":   "flawed",
+		"flawless": "flawless",
+		"perfect":  "perfect",
+	},
+	Codes: defaultGemCodes(),
+}
+
+// LoadGemNameTable reads a JSON gem name table and merges it over the
+// defaults, so English names and standard codes keep working.
+func LoadGemNameTable(r io.Reader) (*GemNameTable, error) {
+	var loaded GemNameTable
+	if err := json.NewDecoder(r).Decode(&loaded); err != nil {
+		return nil, fmt.Errorf("decode gem name table: %w", err)
+	}
+
+	table := &GemNameTable{
+		Types:     make(map[string]string),
+		Qualities: make(map[string]string),
+		Codes:     make(map[string]GemCodeParts),
+	}
+	for _, src := range []*GemNameTable{DefaultGemNameTable, &loaded} {
+		for k, v := range src.Types {
+			table.Types[strings.ToLower(k)] = v
+		}
+		for k, v := range src.Qualities {
+			table.Qualities[strings.ToLower(k)] = v
+		}
+		for k, v := range src.Codes {
+			table.Codes[strings.ToLower(k)] = v
+		}
+	}
+	return table, nil
+}
+
+// Parse classifies a gem by its game code when recognized, otherwise by the
+// words of its name. Quality defaults to "normal" and type to "unknown".
+func (t *GemNameTable) Parse(name, code string) (gemType, quality string) {
+	if parts, ok := t.Codes[strings.ToLower(strings.TrimSpace(code))]; ok {
+		return parts.Type, parts.Quality
+	}
+
+	gemType, quality = "unknown", "normal"
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		if v, ok := t.Types[w]; ok {
+			gemType = v
+		}
+		if v, ok := t.Qualities[w]; ok {
+			quality = v
+		}
+	}
+	return gemType, quality
+}
+
+// IsSkull reports whether a gem type is a skull
+func IsSkull(gemType string) bool {
+	return gemType == GemTypeSkull
+}
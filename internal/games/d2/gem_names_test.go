@@ -0,0 +1,76 @@
+package d2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGemNameTableParse(t *testing.T) {
+	codeLetters := map[string]string{
+		"amethyst": "v", "sapphire": "b", "emerald": "g", "ruby": "r", "diamond": "w", "topaz": "y", GemTypeSkull: "",
+	}
+	codePrefixes := map[string]string{"chipped": "gc", "flawed": "gf", "normal": "gs", "flawless": "gl", "perfect": "gp"}
+	skullCodes := map[string]string{"chipped": "skc", "flawed": "skf", "normal": "sku", "flawless": "skl", "perfect": "skz"}
+
+	for gemType, letter := range codeLetters {
+		for _, quality := range GemQualities {
+			name := strings.ToUpper(gemType[:1]) + gemType[1:]
+			if quality != "normal" {
+				name = strings.ToUpper(quality[:1]) + quality[1:] + " " + name
+			}
+			code := codePrefixes[quality] + letter
+			switch {
+			case gemType == GemTypeSkull:
+				code = skullCodes[quality]
+			case gemType == "amethyst" && quality == "flawless":
+				// Amethyst breaks the pattern with "gzv"
+				code = "gzv"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				if gotType, gotQuality := DefaultGemNameTable.Parse(name, ""); gotType != gemType || gotQuality != quality {
+					t.Errorf("Parse(%q) by name = %s %s, want %s %s", name, gotType, gotQuality, gemType, quality)
+				}
+				// Codes win over the name
+				if gotType, gotQuality := DefaultGemNameTable.Parse("Unnamed", code); gotType != gemType || gotQuality != quality {
+					t.Errorf("Parse by code %q = %s %s, want %s %s", code, gotType, gotQuality, gemType, quality)
+				}
+				if IsSkull(gemType) != (gemType == GemTypeSkull) {
+					t.Errorf("IsSkull(%s) = %v", gemType, IsSkull(gemType))
+				}
+			})
+		}
+	}
+
+	if gotType, _ := DefaultGemNameTable.Parse("Flawless Amethyst", "glv"); gotType != "amethyst" {
+		t.Errorf("unknown code glv didn't fall back to the name: %s", gotType)
+	}
+	if gotType, gotQuality := DefaultGemNameTable.Parse("Jewel", ""); gotType != "unknown" || gotQuality != "normal" {
+		t.Errorf("Parse(Jewel) = %s %s, want unknown normal", gotType, gotQuality)
+	}
+}
+
+func TestLoadGemNameTableMergesOverDefaults(t *testing.T) {
+	table, err := LoadGemNameTable(strings.NewReader(`{
+		"types": {"Rubis": "ruby", "Crâne": "skull"},
+		"qualities": {"Taillé": "chipped", "Parfait": "perfect"}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadGemNameTable: %v", err)
+	}
+
+	tests := []struct{ name, gemType, quality string }{
+		{"Rubis taillé", "ruby", "chipped"},
+		{"Crâne parfait", GemTypeSkull, "perfect"},
+		{"Perfect Skull", GemTypeSkull, "perfect"},
+		{"Chipped Ruby", "ruby", "chipped"},
+	}
+	for _, tt := range tests {
+		if gotType, gotQuality := table.Parse(tt.name, ""); gotType != tt.gemType || gotQuality != tt.quality {
+			t.Errorf("Parse(%q) = %s %s, want %s %s", tt.name, gotType, gotQuality, tt.gemType, tt.quality)
+		}
+	}
+	if gotType, gotQuality := table.Parse("", "SKZ"); gotType != GemTypeSkull || gotQuality != "perfect" {
+		t.Errorf("Parse by code SKZ = %s %s, want skull perfect", gotType, gotQuality)
+	}
+}
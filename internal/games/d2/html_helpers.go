@@ -1,6 +1,9 @@
 package d2
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 // htmlTypeNameToCode maps HTML type display names to D2 item type codes
 var htmlTypeNameToCode = map[string]string{
@@ -106,7 +109,42 @@ func generateBaseCode(name string) string {
 	return code
 }
 
-// splitOrBonuses splits stat text that contains "or" alternatives into separate bonus lines.
+// fallbackBaseCode is used for names that slug to nothing (empty or
+// punctuation-only names)
+const fallbackBaseCode = "item"
+
+// maxBaseCodeLen bounds generated codes including their numeric suffix
+const maxBaseCodeLen = 10
+
+// uniqueBaseCode generates a code for name that isn't in used, appending a
+// numeric suffix on collision, and marks it used. used must hold every code
+// already taken (loaded from the DB plus those generated this run) so that
+// separate partial imports stay collision-free. The result only depends on
+// name and used, so re-running an import assigns the same codes.
+func uniqueBaseCode(name string, used map[string]bool) string {
+	code := generateBaseCode(name)
+	if code == "" {
+		code = fallbackBaseCode
+	}
+	if used[code] {
+		for i := 2; ; i++ {
+			suffix := strconv.Itoa(i)
+			prefix := code
+			if len(prefix)+len(suffix) > maxBaseCodeLen {
+				prefix = prefix[:maxBaseCodeLen-len(suffix)]
+			}
+			if candidate := prefix + suffix; !used[candidate] {
+				code = candidate
+				break
+			}
+		}
+	}
+	used[code] = true
+	return code
+}
+
+// splitOrBonuses splits stat text that contains "or" alternatives into one
+// line per option. Text without alternatives is returned as a single line.
 func splitOrBonuses(text string) []string {
 	text = strings.TrimSpace(text)
 	parts := strings.Split(text, " or \n")
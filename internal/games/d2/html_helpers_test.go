@@ -0,0 +1,72 @@
+package d2
+
+import "testing"
+
+func TestUniqueBaseCodeCollisions(t *testing.T) {
+	if a, b := generateBaseCode("Eagle Orb"), generateBaseCode("Eagle Ornament"); a != b {
+		t.Fatalf("fixture names slug to %q and %q, want the same code", a, b)
+	}
+
+	assign := func(used map[string]bool) []string {
+		return []string{
+			uniqueBaseCode("Eagle Orb", used),
+			uniqueBaseCode("Eagle Ornament", used),
+			uniqueBaseCode("Eagle Orbit", used),
+		}
+	}
+
+	got := assign(map[string]bool{})
+	want := []string{"eagor", "eagor2", "eagor3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("codes = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Re-running with the same taken codes assigns the same codes
+	again := assign(map[string]bool{})
+	for i := range got {
+		if again[i] != got[i] {
+			t.Errorf("second run codes = %v, want %v", again, got)
+			break
+		}
+	}
+}
+
+func TestUniqueBaseCodeSkipsTakenCodes(t *testing.T) {
+	used := map[string]bool{"eagor": true, "eagor2": true}
+	if got := uniqueBaseCode("Eagle Orb", used); got != "eagor3" {
+		t.Errorf("uniqueBaseCode = %q, want eagor3", got)
+	}
+	if !used["eagor3"] {
+		t.Error("generated code was not marked used")
+	}
+}
+
+func TestUniqueBaseCodeSuffixStaysWithinMaxLength(t *testing.T) {
+	used := map[string]bool{}
+	first := uniqueBaseCode("Colossus Voulge Blade Edge", used)
+	if len(first) != 8 {
+		t.Fatalf("first code %q, want 8 characters", first)
+	}
+	for i := 0; i < 11; i++ {
+		uniqueBaseCode("Colossus Voulge Blade Edge", used)
+	}
+	if got := uniqueBaseCode("Colossus Voulge Blade Edge", used); len(got) > maxBaseCodeLen {
+		t.Errorf("code %q is longer than %d", got, maxBaseCodeLen)
+	}
+	if len(used) != 13 {
+		t.Errorf("assigned %d distinct codes, want 13", len(used))
+	}
+}
+
+func TestUniqueBaseCodeFallback(t *testing.T) {
+	used := map[string]bool{}
+	if got := uniqueBaseCode("'-'", used); got != fallbackBaseCode {
+		t.Errorf("uniqueBaseCode(\"'-'\") = %q, want %q", got, fallbackBaseCode)
+	}
+	if got := uniqueBaseCode("", used); got != fallbackBaseCode+"2" {
+		t.Errorf("uniqueBaseCode(\"\") = %q, want %q", got, fallbackBaseCode+"2")
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/storage"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // HTMLImporterV2 is the HTML-only import pipeline.
@@ -24,15 +28,80 @@ type HTMLImporterV2 struct {
 
 	// Caches loaded from DB
 	baseNameToCode    map[string]string
+	usedBaseCodes     map[string]bool   // base codes taken in the DB or generated this run
 	runeNameToCode    map[string]string
-	existingImageURLs map[string]bool // normalized name -> has image
+	existingImageURLs map[string]bool   // normalized name -> has image
+	names             *NameNormalizer   // normalizes names for existingImageURLs
 	imageCache        map[string]string // imagePath -> uploaded URL
+	thumbURLs         map[string]string // uploaded URL -> thumbnail URL
+
+	// Image uploads run on a bounded worker pool ahead of each import phase;
+	// mu guards imageCache, thumbURLs and the image counters on ImportResult.
+	uploadConcurrency int
+	mu                sync.Mutex
+
+	// placeholders filters out "Expansion"/"Not Used" style rows. With
+	// opts.IncludePlaceholders, base and misc rows matching it are imported
+	// marked as placeholders instead.
+	placeholders *PlaceholderFilter
+
+	// opts holds the options of the running import
+	opts ImportOptions
+
+	// combineRules collapse component stats (str/dex/vit/enr) into display codes
+	combineRules []CombineRule
+
+	// batchSize and progress control per-batch progress reporting in the
+	// item loops
+	batchSize int
+	progress  ProgressReporter
+
+	// rawLines counts property lines the reverse translator couldn't match
+	rawLines map[string]int
+
+	// gemNames classifies gem names and codes into gem type and quality
+	gemNames *GemNameTable
+
+	// runeOrders records the source rune order per runeword name so the
+	// stored order can be verified after import
+	runeOrders map[string][]string
+
+	// seen records the upsert keys found in the source per item type, for
+	// reconciling against the database after import. Types in incomplete
+	// weren't fully covered by this run and are never reconciled.
+	seen       map[string]map[string]bool
+	incomplete map[string]bool
+	pruneStale bool
+
+	// incremental skips writes for rows whose content hash is unchanged
+	incremental bool
+
+	// reportPath is where the JSON import report is written (empty: none)
+	reportPath string
+
+	// transactional runs the database writes of ImportAll in one transaction.
+	// imageRepo stays on the pool: image hash records point at files that
+	// survive a rollback, and upload workers query concurrently, which a
+	// transaction doesn't allow.
+	transactional bool
+	imageRepo     *Repository
+}
+
+// DefaultUploadConcurrency is the default number of parallel image uploads
+const DefaultUploadConcurrency = 8
+
+// imageUploadJob describes one image to upload ahead of an import phase
+type imageUploadJob struct {
+	imagePath string
+	category  string
+	itemName  string
 }
 
 // NewHTMLImporterV2 creates a new HTML-only importer
 func NewHTMLImporterV2(repo *Repository, statRegistry *StatRegistry, stor storage.Storage, dryRun bool) *HTMLImporterV2 {
 	return &HTMLImporterV2{
 		repo:              repo,
+		imageRepo:         repo,
 		parser:            NewHTMLItemParser(),
 		reverseTranslator: NewReverseTranslator(),
 		translator:        NewPropertyTranslator(),
@@ -40,11 +109,163 @@ func NewHTMLImporterV2(repo *Repository, statRegistry *StatRegistry, stor storag
 		storage:           stor,
 		dryRun:            dryRun,
 		imageCache:        make(map[string]string),
+		thumbURLs:         make(map[string]string),
+		uploadConcurrency: DefaultUploadConcurrency,
+		placeholders:      DefaultPlaceholderFilter,
+		names:             DefaultNameNormalizer,
+		combineRules:      DefaultCombineRules,
+		gemNames:          DefaultGemNameTable,
+		batchSize:         DefaultImportBatchSize,
+		progress:          PrintProgressReporter{},
+		runeOrders:        make(map[string][]string),
+		rawLines:          make(map[string]int),
+		seen:              make(map[string]map[string]bool),
+		incomplete:        make(map[string]bool),
 	}
 }
 
+// SetPruneStale enables soft-deleting rows that weren't seen in the import.
+// When disabled, stale rows are only reported in ImportResult.StaleItems.
+func (h *HTMLImporterV2) SetPruneStale(prune bool) {
+	h.pruneStale = prune
+}
+
+// markSeen records that an item type's upsert key appeared in the source data
+func (h *HTMLImporterV2) markSeen(itemType, key string) {
+	if h.seen[itemType] == nil {
+		h.seen[itemType] = make(map[string]bool)
+	}
+	h.seen[itemType][key] = true
+}
+
+// SetCombineRules replaces the rules used to combine component properties
+func (h *HTMLImporterV2) SetCombineRules(rules []CombineRule) {
+	h.combineRules = rules
+}
+
+// SetGemNameTable replaces the table used to classify gem names
+func (h *HTMLImporterV2) SetGemNameTable(t *GemNameTable) {
+	h.gemNames = t
+}
+
+// SetBatchSize sets how many items make up one progress batch.
+// Values below 1 disable batch progress reporting.
+func (h *HTMLImporterV2) SetBatchSize(n int) {
+	h.batchSize = n
+}
+
+// SetProgressReporter replaces the receiver of per-batch progress. A nil
+// reporter disables batch progress reporting.
+func (h *HTMLImporterV2) SetProgressReporter(r ProgressReporter) {
+	h.progress = r
+}
+
+// SetNameNormalizer replaces the normalizer used to match item names against
+// rows that already have an image
+func (h *HTMLImporterV2) SetNameNormalizer(n *NameNormalizer) {
+	h.names = n
+}
+
+// SetPlaceholderFilter replaces the filter used to skip placeholder rows
+func (h *HTMLImporterV2) SetPlaceholderFilter(f *PlaceholderFilter) {
+	h.placeholders = f
+}
+
+// skipPlaceholder reports whether name is a placeholder row and counts it
+func (h *HTMLImporterV2) skipPlaceholder(name string, result *ImportResult) bool {
+	if !h.placeholders.IsPlaceholder(name) {
+		return false
+	}
+	result.PlaceholdersFiltered++
+	return true
+}
+
+// skipPlaceholderBase is skipPlaceholder for rows stored as item bases. When
+// placeholders are included the row is kept and placeholder reports it.
+func (h *HTMLImporterV2) skipPlaceholderBase(name string, result *ImportResult) (skip, placeholder bool) {
+	if !h.opts.IncludePlaceholders {
+		return h.skipPlaceholder(name, result), false
+	}
+	if !h.placeholders.IsPlaceholder(name) {
+		return false, false
+	}
+	result.PlaceholdersImported++
+	return false, true
+}
+
+// SetUploadConcurrency sets the number of parallel image uploads.
+// Values below 1 disable parallel uploads (images are uploaded inline).
+func (h *HTMLImporterV2) SetUploadConcurrency(n int) {
+	h.uploadConcurrency = n
+}
+
+// SetTransactional makes ImportAll write everything in one database
+// transaction, so a failed import leaves the catalog untouched. The first
+// failed write or post-import step stops the import and rolls it back.
+// Uploaded images are not rolled back.
+func (h *HTMLImporterV2) SetTransactional(transactional bool) {
+	h.transactional = transactional
+}
+
+// inTransaction reports whether ImportAll runs its writes in one transaction
+func (h *HTMLImporterV2) inTransaction() bool {
+	return h.transactional && !h.dryRun
+}
+
+// ImportOptions controls what a single import run brings in
+type ImportOptions struct {
+	// IncludePlaceholders keeps placeholder base and misc rows, imported
+	// with ItemBase.Placeholder set, instead of skipping them. Other item
+	// types have no placeholder marker and always skip them.
+	IncludePlaceholders bool
+}
+
 // ImportAll runs the full HTML import pipeline
-func (h *HTMLImporterV2) ImportAll(ctx context.Context, catalogPath string) (*ImportResult, error) {
+func (h *HTMLImporterV2) ImportAll(ctx context.Context, catalogPath string, opts ImportOptions) (result *ImportResult, err error) {
+	h.opts = opts
+	defer func() { h.opts = ImportOptions{} }()
+
+	ctx, span := tracing.Start(ctx, "import.all",
+		attribute.Bool("import.dry_run", h.dryRun),
+		attribute.Bool("import.transactional", h.transactional))
+	defer func() {
+		recordImportMetrics(result, err)
+		tracing.End(span, err)
+	}()
+
+	if !h.inTransaction() {
+		return h.importAll(ctx, catalogPath)
+	}
+
+	err = h.repo.InTx(ctx, func(tx *Repository) error {
+		restore := h.useRepo(tx)
+		defer restore()
+
+		var importErr error
+		result, importErr = h.importAll(ctx, catalogPath)
+		if importErr != nil {
+			return importErr
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("%d import errors, rolled back", len(result.Errors))
+		}
+		return nil
+	})
+	return result, err
+}
+
+// useRepo points the importer and its stat registry at repo until the
+// returned restore func is called
+func (h *HTMLImporterV2) useRepo(repo *Repository) (restore func()) {
+	prevRepo, prevStatRepo := h.repo, h.statRegistry.repo
+	h.repo, h.statRegistry.repo = repo, repo
+	return func() {
+		h.repo, h.statRegistry.repo = prevRepo, prevStatRepo
+	}
+}
+
+// importAll runs the import steps against h.repo
+func (h *HTMLImporterV2) importAll(ctx context.Context, catalogPath string) (*ImportResult, error) {
 	result := &ImportResult{}
 
 	h.iconsPath = filepath.Join(catalogPath, "icons")
@@ -59,7 +280,9 @@ func (h *HTMLImporterV2) ImportAll(ctx context.Context, catalogPath string) (*Im
 		len(h.baseNameToCode), len(h.runeNameToCode), len(h.existingImageURLs))
 
 	// 1. Import bases
-	if err := h.importBases(ctx, pagesPath, result); err != nil {
+	if err := h.stage(ctx, "bases", func(ctx context.Context) error {
+		return h.importBases(ctx, pagesPath, result)
+	}); err != nil {
 		return result, err
 	}
 
@@ -67,7 +290,9 @@ func (h *HTMLImporterV2) ImportAll(ctx context.Context, catalogPath string) (*Im
 	h.reloadBaseCache(ctx)
 
 	// 3. Import misc (runes, gems, charms, jewels, keys) - before runewords so rune names resolve
-	if err := h.importMisc(ctx, pagesPath, result); err != nil {
+	if err := h.stage(ctx, "misc", func(ctx context.Context) error {
+		return h.importMisc(ctx, pagesPath, result)
+	}); err != nil {
 		return result, err
 	}
 
@@ -76,33 +301,106 @@ func (h *HTMLImporterV2) ImportAll(ctx context.Context, catalogPath string) (*Im
 	h.reloadRuneCache(ctx)
 
 	// 5. Import uniques
-	if err := h.importUniques(ctx, pagesPath, result); err != nil {
+	if err := h.stage(ctx, "uniques", func(ctx context.Context) error {
+		return h.importUniques(ctx, pagesPath, result)
+	}); err != nil {
 		return result, err
 	}
 
 	// 6. Import sets
-	if err := h.importSets(ctx, pagesPath, result); err != nil {
+	if err := h.stage(ctx, "sets", func(ctx context.Context) error {
+		return h.importSets(ctx, pagesPath, result)
+	}); err != nil {
 		return result, err
 	}
 
 	// 7. Import runewords (needs rune name→code cache from step 4)
-	if err := h.importRunewords(ctx, pagesPath, result); err != nil {
+	if err := h.stage(ctx, "runewords", func(ctx context.Context) error {
+		return h.importRunewords(ctx, pagesPath, result)
+	}); err != nil {
 		return result, err
 	}
 
 	// 8. Link variants
-	if err := h.linkVariants(ctx, pagesPath); err != nil {
-		fmt.Printf("    Warning: variant linking failed: %v\n", err)
+	if err := h.postStep("variant linking", h.linkVariants(ctx, pagesPath)); err != nil {
+		return result, err
 	}
 
 	// 9. Compute runeword bases
-	if err := h.computeRunewordBases(ctx, result); err != nil {
+	if err := h.stage(ctx, "runeword_bases", func(ctx context.Context) error {
+		return h.computeRunewordBases(ctx, result)
+	}); err != nil {
+		return result, err
+	}
+
+	// 10. Verify runeword rune order survived the round-trip (order defines the runeword)
+	if !h.dryRun {
+		if err := h.postStep("rune order verification", h.verifyRuneOrders(ctx, result)); err != nil {
+			return result, err
+		}
+	}
+
+	// 11. Find (and optionally soft-delete) rows that disappeared from the source
+	if err := h.postStep("stale item reconciliation", h.reconcileStale(ctx, result)); err != nil {
+		return result, err
+	}
+
+	// 12. Hide placeholder rows left over from earlier imports
+	if !h.dryRun && h.placeholders != nil {
+		hidden, err := h.repo.HidePlaceholderItems(ctx, h.placeholders.Patterns(), h.opts.IncludePlaceholders)
+		if err := h.postStep("placeholder cleanup", err); err != nil {
+			return result, err
+		}
+		if hidden > 0 {
+			fmt.Printf("    Hid %d existing placeholder items\n", hidden)
+		}
+	}
+
+	// 13. Point rows at the thumbnails uploaded alongside their images
+	if err := h.postStep("storing thumbnail URLs", h.storeThumbnailURLs(ctx)); err != nil {
+		return result, err
+	}
+
+	// 14. Report property lines the reverse translator couldn't match
+	h.reportRawProperties(result)
+	result.NewStatCodes = h.statRegistry.Added()
+
+	// 15. Report uniques and set items whose base code doesn't resolve
+	if err := h.postStep("base reference check", h.reportOrphans(ctx, result)); err != nil {
+		return result, err
+	}
+
+	// 16. Write the machine-readable report
+	if err := h.writeReport(result); err != nil {
 		return result, err
 	}
 
 	return result, nil
 }
 
+// postStep handles the error of a step run after the items are imported. A
+// plain import has already written its rows, so the failure is only a
+// warning; a transactional import returns it and rolls back rather than
+// commit a half-finished catalog.
+func (h *HTMLImporterV2) postStep(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if h.inTransaction() {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	fmt.Printf("    Warning: %s failed: %v\n", name, err)
+	return nil
+}
+
+// stage runs one import step inside its own trace span
+func (h *HTMLImporterV2) stage(ctx context.Context, name string, fn func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "import."+name)
+	err := fn(ctx)
+	tracing.End(span, err)
+	return err
+}
+
 func (h *HTMLImporterV2) loadCaches(ctx context.Context) error {
 	var err error
 
@@ -110,6 +408,7 @@ func (h *HTMLImporterV2) loadCaches(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("base name map: %w", err)
 	}
+	h.markBaseCodesUsed()
 
 	h.runeNameToCode, err = h.repo.GetRuneNameToCodeMap(ctx)
 	if err != nil {
@@ -130,8 +429,8 @@ func (h *HTMLImporterV2) loadCaches(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("images for %s: %w", tbl.table, err)
 		}
-		for name := range names {
-			h.existingImageURLs[name] = true
+		for _, name := range names {
+			h.existingImageURLs[h.names.Normalize(name)] = true
 		}
 	}
 
@@ -140,6 +439,18 @@ func (h *HTMLImporterV2) loadCaches(ctx context.Context) error {
 
 func (h *HTMLImporterV2) reloadBaseCache(ctx context.Context) {
 	h.baseNameToCode, _ = h.repo.GetAllItemBaseNameToCode(ctx)
+	h.markBaseCodesUsed()
+}
+
+// markBaseCodesUsed adds the loaded base codes to usedBaseCodes. Codes are
+// only ever added: generated codes not stored yet (dry runs) stay reserved.
+func (h *HTMLImporterV2) markBaseCodesUsed() {
+	if h.usedBaseCodes == nil {
+		h.usedBaseCodes = make(map[string]bool, len(h.baseNameToCode))
+	}
+	for _, code := range h.baseNameToCode {
+		h.usedBaseCodes[code] = true
+	}
 }
 
 func (h *HTMLImporterV2) reloadRuneCache(ctx context.Context) {
@@ -161,35 +472,33 @@ func (h *HTMLImporterV2) importBases(ctx context.Context, pagesPath string, resu
 	}
 	fmt.Printf("    Found %d base items\n", len(items))
 
-	usedCodes := make(map[string]bool)
-	for _, code := range h.baseNameToCode {
-		usedCodes[code] = true
+	jobs := make([]imageUploadJob, 0, len(items))
+	for _, item := range items {
+		if h.placeholders.IsPlaceholder(item.Name) && !h.opts.IncludePlaceholders {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{item.ImagePath, "d2/base", item.Name})
 	}
+	h.prefetchImages(ctx, jobs, result)
 
 	ensuredTypes := make(map[string]bool)
 	baseErrors := 0
+	duplicates := newDuplicateTracker("bases.html", result)
 
+	progress := h.newBatchTracker("bases", len(items))
 	for _, item := range items {
+		progress.next()
+		skip, placeholder := h.skipPlaceholderBase(item.Name, result)
+		if skip {
+			continue
+		}
+
 		// Resolve or generate code
-		code := ""
-		if existing, ok := h.baseNameToCode[item.Name]; ok {
-			code = existing
-		} else {
-			code = generateBaseCode(item.Name)
-			if usedCodes[code] {
-				for i := 2; ; i++ {
-					candidate := fmt.Sprintf("%s%d", code, i)
-					if len(candidate) > 10 {
-						candidate = fmt.Sprintf("%s%d", code[:10-len(fmt.Sprintf("%d", i))], i)
-					}
-					if !usedCodes[candidate] {
-						code = candidate
-						break
-					}
-				}
-			}
+		code, ok := h.baseNameToCode[item.Name]
+		if !ok {
+			code = uniqueBaseCode(item.Name, h.usedBaseCodes)
 		}
-		usedCodes[code] = true
+		duplicates.check(code, item.Name)
 
 		// Determine category
 		category := "misc"
@@ -263,17 +572,28 @@ func (h *HTMLImporterV2) importBases(ctx context.Context, pagesPath string, resu
 			Spawnable:     true,
 			Rarity:        1,
 			ImageURL:      imageURL,
+			Placeholder:   placeholder,
 		}
 
+		h.markSeen("bases", code)
 		if !h.dryRun {
-			if err := h.repo.UpsertItemBase(ctx, base); err != nil {
-				fmt.Printf("    ERROR: base '%s' (code=%s, category=%s): %v\n", item.Name, code, category, err)
+			changed, err := h.upsertIfChanged(ctx, "item_bases", base.Code, base, func() error {
+				return h.repo.UpsertItemBase(ctx, base)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: base '%s' (code=%s, category=%s): %v", item.Name, code, category, err); abortErr != nil {
+					return abortErr
+				}
 				baseErrors++
 				continue
 			}
+			if !changed {
+				result.ItemBases.Unchanged++
+			}
 		}
 		result.ItemBases.Imported++
 	}
+	progress.finish()
 
 	fmt.Printf("    Bases: %d imported, %d errors\n", result.ItemBases.Imported, baseErrors)
 	return nil
@@ -292,8 +612,25 @@ func (h *HTMLImporterV2) importUniques(ctx context.Context, pagesPath string, re
 	maxID, _ := h.repo.GetMaxIndexID(ctx, "unique_items")
 	nextID := maxID + 1
 
+	jobs := make([]imageUploadJob, 0, len(items))
+	for _, item := range items {
+		if h.placeholders.IsPlaceholder(item.Name) {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{item.ImagePath, "d2/unique", item.Name})
+	}
+	h.prefetchImages(ctx, jobs, result)
+
 	skipped := 0
+	duplicates := newDuplicateTracker("uniques.html", result)
+	progress := h.newBatchTracker("uniques", len(items))
 	for _, item := range items {
+		progress.next()
+		if h.skipPlaceholder(item.Name, result) {
+			continue
+		}
+		duplicates.check(item.Name, item.Name)
+
 		// Resolve base code
 		baseCode := ""
 		if item.BaseName != "" {
@@ -305,8 +642,8 @@ func (h *HTMLImporterV2) importUniques(ctx context.Context, pagesPath string, re
 		}
 
 		// Reverse-translate properties and register stats
-		properties := h.reverseTranslator.ReverseTranslateLines(item.Properties)
-		properties = combineAllAttributes(properties, h.translator)
+		properties := h.reverseTranslateLines(item.Properties)
+		properties = ApplyCombineRules(properties, h.combineRules, h.translator)
 		for i := range properties {
 			if properties[i].Code != "raw" {
 				h.translator.EnrichProperty(&properties[i])
@@ -330,20 +667,50 @@ func (h *HTMLImporterV2) importUniques(ctx context.Context, pagesPath string, re
 		}
 		nextID++
 
+		h.markSeen("uniques", item.Name)
 		if !h.dryRun {
-			if err := h.repo.UpsertUniqueItemByName(ctx, unique); err != nil {
-				fmt.Printf("    ERROR: unique '%s': %v\n", item.Name, err)
+			changed, err := h.upsertIfChanged(ctx, "unique_items", unique.Name, unique, func() error {
+				return h.repo.UpsertUniqueItemByName(ctx, unique)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: unique '%s': %v", item.Name, err); abortErr != nil {
+					return abortErr
+				}
 				skipped++
 				continue
 			}
+			if !changed {
+				result.UniqueItems.Unchanged++
+			}
 		}
 		result.UniqueItems.Imported++
 	}
+	progress.finish()
 
 	fmt.Printf("    Uniques: %d imported, %d errors\n", result.UniqueItems.Imported, skipped)
 	return nil
 }
 
+// translateSetBonus reverse-translates one set bonus. "X or Y" bonuses stay
+// one property with Y as an alternative, since only one of them applies.
+func (h *HTMLImporterV2) translateSetBonus(ctx context.Context, text string, itemCount int) Property {
+	var prop Property
+	for i, line := range splitOrBonuses(text) {
+		option := h.reverseTranslate(line)
+		if option.Code != "raw" {
+			h.translator.EnrichProperty(&option)
+		}
+		h.statRegistry.EnsureStat(ctx, option)
+		if i == 0 {
+			prop = option
+		} else {
+			prop.Alternatives = append(prop.Alternatives, option)
+		}
+	}
+	prop.ItemCount = itemCount
+	return prop
+}
+
 // importSets parses sets.html and upserts set_bonuses and set_items by name
 func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, result *ImportResult) error {
 	fmt.Println("\n  Parsing sets.html...")
@@ -365,7 +732,7 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 	nextSetID := maxSetID + 1
 
 	for _, item := range setItems {
-		if item.SetName == "" || setNames[item.SetName] {
+		if item.SetName == "" || setNames[item.SetName] || h.placeholders.IsPlaceholder(item.SetName) {
 			continue
 		}
 		setNames[item.SetName] = true
@@ -373,16 +740,17 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 		// Translate full set bonuses if available
 		var partialBonuses, fullBonuses []Property
 		if fs, ok := fullSetMap[item.SetName]; ok {
-			for _, line := range fs.PartialBonuses {
-				prop := h.reverseTranslator.ReverseTranslate(line)
+			for _, bonus := range fs.PartialBonuses {
+				prop := h.reverseTranslate(bonus.Text)
 				if prop.Code != "raw" {
 					h.translator.EnrichProperty(&prop)
 				}
 				h.statRegistry.EnsureStat(ctx, prop)
+				prop.ItemCount = bonus.ItemCount
 				partialBonuses = append(partialBonuses, prop)
 			}
 			for _, line := range fs.FullBonuses {
-				prop := h.reverseTranslator.ReverseTranslate(line)
+				prop := h.reverseTranslate(line)
 				if prop.Code != "raw" {
 					h.translator.EnrichProperty(&prop)
 				}
@@ -401,7 +769,9 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 
 		if !h.dryRun {
 			if err := h.repo.UpsertSetBonus(ctx, setBonus); err != nil {
-				fmt.Printf("    Error upserting set %s: %v\n", item.SetName, err)
+				if abortErr := h.rowError(result, "Error upserting set %s: %v", item.SetName, err); abortErr != nil {
+					return abortErr
+				}
 				continue
 			}
 		}
@@ -412,8 +782,28 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 	maxItemID, _ := h.repo.GetMaxIndexID(ctx, "set_items")
 	nextItemID := maxItemID + 1
 
+	jobs := make([]imageUploadJob, 0, len(setItems))
+	for _, item := range setItems {
+		if h.placeholders.IsPlaceholder(item.Name) {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{item.ImagePath, "d2/set", item.Name})
+	}
+	h.prefetchImages(ctx, jobs, result)
+
 	setItemErrors := 0
+	duplicates := newDuplicateTracker("sets.html", result)
+	progress := h.newBatchTracker("set_items", len(setItems))
 	for _, item := range setItems {
+		progress.next()
+		// Set items are skipped even with IncludePlaceholders: set_items has
+		// no placeholder column, so a kept row would be listed as a real
+		// member of its set and counted toward the set's bonus tiers
+		if h.skipPlaceholder(item.Name, result) {
+			continue
+		}
+		duplicates.check(item.Name, item.Name)
+
 		baseCode := ""
 		if item.BaseName != "" {
 			if code, ok := h.baseNameToCode[item.BaseName]; ok {
@@ -424,8 +814,8 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 		}
 
 		// Reverse-translate properties
-		properties := h.reverseTranslator.ReverseTranslateLines(item.Properties)
-		properties = combineAllAttributes(properties, h.translator)
+		properties := h.reverseTranslateLines(item.Properties)
+		properties = ApplyCombineRules(properties, h.combineRules, h.translator)
 		for i := range properties {
 			if properties[i].Code != "raw" {
 				h.translator.EnrichProperty(&properties[i])
@@ -436,17 +826,9 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 		// Reverse-translate set bonuses
 		var bonusProperties []Property
 		for _, bonus := range item.SetBonuses {
-			bonusLines := splitOrBonuses(bonus.Text)
-			for _, line := range bonusLines {
-				prop := h.reverseTranslator.ReverseTranslate(line)
-				if prop.Code != "raw" {
-					h.translator.EnrichProperty(&prop)
-				}
-				h.statRegistry.EnsureStat(ctx, prop)
-				bonusProperties = append(bonusProperties, prop)
-			}
+			bonusProperties = append(bonusProperties, h.translateSetBonus(ctx, bonus.Text, bonus.ItemCount))
 		}
-		bonusProperties = combineAllAttributes(bonusProperties, h.translator)
+		bonusProperties = ApplyCombineRules(bonusProperties, h.combineRules, h.translator)
 
 		imageURL := h.maybeUploadImage(ctx, item.ImagePath, "d2/set", item.Name, result)
 
@@ -465,15 +847,25 @@ func (h *HTMLImporterV2) importSets(ctx context.Context, pagesPath string, resul
 		}
 		nextItemID++
 
+		h.markSeen("sets", item.Name)
 		if !h.dryRun {
-			if err := h.repo.UpsertSetItemByName(ctx, setItem); err != nil {
-				fmt.Printf("    ERROR: set item '%s': %v\n", item.Name, err)
+			changed, err := h.upsertIfChanged(ctx, "set_items", setItem.Name, setItem, func() error {
+				return h.repo.UpsertSetItemByName(ctx, setItem)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: set item '%s': %v", item.Name, err); abortErr != nil {
+					return abortErr
+				}
 				setItemErrors++
 				continue
 			}
+			if !changed {
+				result.SetItems.Unchanged++
+			}
 		}
 		result.SetItems.Imported++
 	}
+	progress.finish()
 
 	fmt.Printf("    Sets: %d imported, Set items: %d imported, %d errors\n", result.SetBonuses.Imported, result.SetItems.Imported, setItemErrors)
 	return nil
@@ -489,8 +881,18 @@ func (h *HTMLImporterV2) importRunewords(ctx context.Context, pagesPath string,
 	fmt.Printf("    Found %d runewords\n", len(runewords))
 
 	skippedRW := 0
+	duplicates := newDuplicateTracker("runewords.html", result)
+	progress := h.newBatchTracker("runewords", len(runewords))
 	for _, rw := range runewords {
-		// Resolve rune names to codes
+		progress.next()
+		if h.skipPlaceholder(rw.Name, result) {
+			continue
+		}
+		duplicates.check(rw.Name, rw.Name)
+		h.markSeen("runewords", fmt.Sprintf("HTMLRuneword_%s", strings.ReplaceAll(rw.Name, " ", "")))
+
+		// Resolve rune names to codes. Order must be preserved exactly:
+		// Jah+Ith+Ber is Enigma, any other order is not a runeword.
 		var runeCodes []string
 		var unresolvedRunes []string
 		for _, runeName := range rw.Runes {
@@ -506,12 +908,19 @@ func (h *HTMLImporterV2) importRunewords(ctx context.Context, pagesPath string,
 			continue
 		}
 
+		// A runeword needs exactly one socket per rune; a mismatch means the
+		// rune list or the declared socket count was parsed wrong
+		if rw.SocketCount > 0 && rw.SocketCount != len(runeCodes) {
+			fmt.Printf("    Warning: runeword '%s' declares %d sockets but has %d runes %v\n", rw.Name, rw.SocketCount, len(runeCodes), rw.Runes)
+			result.SocketMismatches++
+		}
+
 		// Store valid types as tag names (not codes) for type_tags matching
 		validTypes := rw.ValidTypes
 
 		// Reverse-translate properties
-		properties := h.reverseTranslator.ReverseTranslateLines(rw.Properties)
-		properties = combineAllAttributes(properties, h.translator)
+		properties := h.reverseTranslateLines(rw.Properties)
+		properties = ApplyCombineRules(properties, h.combineRules, h.translator)
 		for i := range properties {
 			if properties[i].Code != "raw" {
 				h.translator.EnrichProperty(&properties[i])
@@ -531,23 +940,127 @@ func (h *HTMLImporterV2) importRunewords(ctx context.Context, pagesPath string,
 		}
 
 		if !h.dryRun {
-			if err := h.repo.UpsertRuneword(ctx, runeword); err != nil {
-				fmt.Printf("    Error upserting runeword %s: %v\n", rw.Name, err)
+			changed, err := h.upsertIfChanged(ctx, "runewords", runeword.Name, runeword, func() error {
+				return h.repo.UpsertRuneword(ctx, runeword)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "Error upserting runeword %s: %v", rw.Name, err); abortErr != nil {
+					return abortErr
+				}
 				continue
 			}
+			if !changed {
+				result.Runewords.Unchanged++
+			}
 		}
+		h.runeOrders[internalName] = runeCodes
 		result.Runewords.Imported++
 	}
+	progress.finish()
 
 	fmt.Printf("    Runewords: %d imported, %d skipped\n", result.Runewords.Imported, skippedRW)
 	return nil
 }
 
+// verifyRuneOrders re-reads imported runewords and checks that the stored rune
+// sequence matches the source order exactly, restoring the source order for any
+// runeword where it differs.
+func (h *HTMLImporterV2) verifyRuneOrders(ctx context.Context, result *ImportResult) error {
+	if len(h.runeOrders) == 0 {
+		return nil
+	}
+
+	fmt.Println("\n  Verifying runeword rune order...")
+	stored, err := h.repo.GetAllRunewords(ctx)
+	if err != nil {
+		return fmt.Errorf("load runewords: %w", err)
+	}
+
+	for _, rw := range stored {
+		expected, ok := h.runeOrders[rw.Name]
+		if !ok || sameRuneOrder(rw.Runes, expected) {
+			continue
+		}
+		fmt.Printf("    Rune order mismatch for %s: stored %v, source %v\n", rw.DisplayName, rw.Runes, expected)
+		if err := h.repo.UpdateRunewordRunes(ctx, rw.ID, expected); err != nil {
+			if abortErr := h.rowError(result, "Error restoring rune order for %s: %v", rw.DisplayName, err); abortErr != nil {
+				return abortErr
+			}
+			continue
+		}
+		result.RuneOrderRepaired++
+	}
+
+	fmt.Printf("    Rune order: %d checked, %d repaired\n", len(h.runeOrders), result.RuneOrderRepaired)
+	return nil
+}
+
+// reconcileStale compares the keys seen in this import against the active rows
+// of each item type. Rows not seen are reported in result.StaleItems and, when
+// pruning is enabled, soft-deleted. Item types with no rows in this import are
+// skipped so a missing or broken source file can't wipe a whole table.
+func (h *HTMLImporterV2) reconcileStale(ctx context.Context, result *ImportResult) error {
+	fmt.Println("\n  Reconciling items missing from source...")
+
+	for _, itemType := range PurgeableItemTypes() {
+		seen := h.seen[itemType]
+		if len(seen) == 0 || h.incomplete[itemType] {
+			continue
+		}
+
+		active, err := h.repo.GetActiveItemKeys(ctx, itemType)
+		if err != nil {
+			return err
+		}
+
+		var stale []string
+		for key, name := range active {
+			if seen[key] {
+				continue
+			}
+			stale = append(stale, key)
+			result.StaleItems = append(result.StaleItems, StaleItem{Type: itemType, Key: key, Name: name})
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		fmt.Printf("    %s: %d stale\n", itemType, len(stale))
+
+		if h.pruneStale && !h.dryRun {
+			removed, err := h.repo.SoftDeleteItemsByKey(ctx, itemType, stale)
+			if err != nil {
+				return err
+			}
+			result.StaleRemoved += removed
+		}
+	}
+
+	fmt.Printf("    Stale items: %d found, %d removed\n", len(result.StaleItems), result.StaleRemoved)
+	return nil
+}
+
+// sameRuneOrder reports whether two rune code sequences are identical, position by position
+func sameRuneOrder(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // importMisc parses misc.html and upserts runes, gems, and misc items
 func (h *HTMLImporterV2) importMisc(ctx context.Context, pagesPath string, result *ImportResult) error {
 	miscPath := filepath.Join(pagesPath, "misc.html")
 	if _, err := os.Stat(miscPath); os.IsNotExist(err) {
 		fmt.Println("\n  No misc.html found, skipping misc import")
+		// Misc items share item_bases, so bases can't be reconciled either
+		h.incomplete["runes"] = true
+		h.incomplete["gems"] = true
+		h.incomplete["bases"] = true
 		return nil
 	}
 
@@ -558,15 +1071,44 @@ func (h *HTMLImporterV2) importMisc(ctx context.Context, pagesPath string, resul
 	}
 	fmt.Printf("    Found %d runes, %d gems, %d misc items\n", len(runes), len(gems), len(miscItems))
 
+	jobs := make([]imageUploadJob, 0, len(runes)+len(gems)+len(miscItems))
+	for _, rn := range runes {
+		if h.placeholders.IsPlaceholder(rn.Name) {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{rn.ImagePath, "d2/rune", rn.Name})
+	}
+	for _, gem := range gems {
+		if h.placeholders.IsPlaceholder(gem.Name) {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{gem.ImagePath, "d2/gem", gem.Name})
+	}
+	for _, item := range miscItems {
+		if h.placeholders.IsPlaceholder(item.Name) && !h.opts.IncludePlaceholders {
+			continue
+		}
+		jobs = append(jobs, imageUploadJob{item.ImagePath, "d2/misc", item.Name})
+	}
+	h.prefetchImages(ctx, jobs, result)
+
 	// Import runes
 	runeErrors := 0
+	duplicates := newDuplicateTracker("misc.html", result)
+	progress := h.newBatchTracker("runes", len(runes))
 	for _, rn := range runes {
+		progress.next()
+		if h.skipPlaceholder(rn.Name, result) {
+			continue
+		}
+
 		code := ""
 		if c, ok := h.runeNameToCode[rn.Name]; ok {
 			code = c
 		} else {
 			code = fmt.Sprintf("r%02d", rn.RuneIndex)
 		}
+		duplicates.check("rune:"+code, rn.Name)
 
 		weaponMods := h.translateAndRegisterMods(ctx, rn.WeaponMods)
 		helmMods := h.translateAndRegisterMods(ctx, rn.HelmMods)
@@ -586,22 +1128,39 @@ func (h *HTMLImporterV2) importMisc(ctx context.Context, pagesPath string, resul
 			ImageURL:   imageURL,
 		}
 
+		h.markSeen("runes", code)
 		if !h.dryRun {
-			if err := h.repo.UpsertRune(ctx, runeItem); err != nil {
-				fmt.Printf("    ERROR: rune '%s' (code=%s): %v\n", rn.Name, code, err)
+			changed, err := h.upsertIfChanged(ctx, "runes", runeItem.Code, runeItem, func() error {
+				return h.repo.UpsertRune(ctx, runeItem)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: rune '%s' (code=%s): %v", rn.Name, code, err); abortErr != nil {
+					return abortErr
+				}
 				runeErrors++
 				continue
 			}
+			if !changed {
+				result.Runes.Unchanged++
+			}
 		}
 		result.Runes.Imported++
 	}
+	progress.finish()
 	fmt.Printf("    Runes: %d imported, %d errors\n", result.Runes.Imported, runeErrors)
 
 	// Import gems
 	gemErrors := 0
+	progress = h.newBatchTracker("gems", len(gems))
 	for _, gem := range gems {
-		gemType, quality := parseGemNameParts(gem.Name)
+		progress.next()
+		if h.skipPlaceholder(gem.Name, result) {
+			continue
+		}
+
 		code := generateBaseCode(gem.Name)
+		gemType, quality := h.gemNames.Parse(gem.Name, code)
+		duplicates.check("gem:"+code, gem.Name)
 
 		weaponMods := h.translateAndRegisterMods(ctx, gem.WeaponMods)
 		helmMods := h.translateAndRegisterMods(ctx, gem.HelmMods)
@@ -614,50 +1173,49 @@ func (h *HTMLImporterV2) importMisc(ctx context.Context, pagesPath string, resul
 			Name:       gem.Name,
 			GemType:    gemType,
 			Quality:    quality,
+			IsSkull:    IsSkull(gemType),
 			WeaponMods: weaponMods,
 			HelmMods:   helmMods,
 			ShieldMods: shieldMods,
 			ImageURL:   imageURL,
 		}
 
+		h.markSeen("gems", code)
 		if !h.dryRun {
-			if err := h.repo.UpsertGem(ctx, gemItem); err != nil {
-				fmt.Printf("    ERROR: gem '%s' (code=%s, type=%s, quality=%s): %v\n", gem.Name, code, gemType, quality, err)
+			changed, err := h.upsertIfChanged(ctx, "gems", gemItem.Code, gemItem, func() error {
+				return h.repo.UpsertGem(ctx, gemItem)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: gem '%s' (code=%s, type=%s, quality=%s): %v", gem.Name, code, gemType, quality, err); abortErr != nil {
+					return abortErr
+				}
 				gemErrors++
 				continue
 			}
+			if !changed {
+				result.Gems.Unchanged++
+			}
 		}
 		result.Gems.Imported++
 	}
+	progress.finish()
 	fmt.Printf("    Gems: %d imported, %d errors\n", result.Gems.Imported, gemErrors)
 
 	// Import misc items as item_bases
-	usedCodes := make(map[string]bool)
-	for _, code := range h.baseNameToCode {
-		usedCodes[code] = true
-	}
-
 	miscErrors := 0
+	progress = h.newBatchTracker("misc", len(miscItems))
 	for _, item := range miscItems {
-		code := ""
-		if existing, ok := h.baseNameToCode[item.Name]; ok {
-			code = existing
-		} else {
-			code = generateBaseCode(item.Name)
-			if usedCodes[code] {
-				for i := 2; ; i++ {
-					candidate := fmt.Sprintf("%s%d", code, i)
-					if len(candidate) > 10 {
-						candidate = fmt.Sprintf("%s%d", code[:10-len(fmt.Sprintf("%d", i))], i)
-					}
-					if !usedCodes[candidate] {
-						code = candidate
-						break
-					}
-				}
-			}
+		progress.next()
+		skip, placeholder := h.skipPlaceholderBase(item.Name, result)
+		if skip {
+			continue
 		}
-		usedCodes[code] = true
+
+		code, ok := h.baseNameToCode[item.Name]
+		if !ok {
+			code = uniqueBaseCode(item.Name, h.usedBaseCodes)
+		}
+		duplicates.check("misc:"+code, item.Name)
 
 		imageURL := h.maybeUploadImage(ctx, item.ImagePath, "d2/misc", item.Name, result)
 
@@ -671,17 +1229,28 @@ func (h *HTMLImporterV2) importMisc(ctx context.Context, pagesPath string, resul
 			Rarity:      1,
 			Description: item.Description,
 			ImageURL:    imageURL,
+			Placeholder: placeholder,
 		}
 
+		h.markSeen("bases", code)
 		if !h.dryRun {
-			if err := h.repo.UpsertItemBase(ctx, base); err != nil {
-				fmt.Printf("    ERROR: misc '%s' (code=%s): %v\n", item.Name, code, err)
+			changed, err := h.upsertIfChanged(ctx, "item_bases", base.Code, base, func() error {
+				return h.repo.UpsertItemBase(ctx, base)
+			})
+			if err != nil {
+				if abortErr := h.rowError(result, "ERROR: misc '%s' (code=%s): %v", item.Name, code, err); abortErr != nil {
+					return abortErr
+				}
 				miscErrors++
 				continue
 			}
+			if !changed {
+				result.ItemBases.Unchanged++
+			}
 		}
 		result.ItemBases.Imported++
 	}
+	progress.finish()
 	fmt.Printf("    Misc items: %d imported, %d errors\n", len(miscItems)-miscErrors, miscErrors)
 
 	return nil
@@ -790,7 +1359,7 @@ func (h *HTMLImporterV2) computeRunewordBases(ctx context.Context, result *Impor
 
 // translateAndRegisterMods reverse-translates mod text lines and registers stats
 func (h *HTMLImporterV2) translateAndRegisterMods(ctx context.Context, lines []string) []Property {
-	mods := h.reverseTranslator.ReverseTranslateLines(lines)
+	mods := h.reverseTranslateLines(lines)
 	for i := range mods {
 		if mods[i].Code != "raw" {
 			h.translator.EnrichProperty(&mods[i])
@@ -800,30 +1369,95 @@ func (h *HTMLImporterV2) translateAndRegisterMods(ctx context.Context, lines []s
 	return mods
 }
 
-// maybeUploadImage uploads an image only if the item doesn't already have one
+// prefetchImages uploads the images for an import phase on a bounded worker
+// pool so the serial upsert loop that follows only hits the image cache.
+// Does nothing when parallel uploads are disabled or there is no storage.
+func (h *HTMLImporterV2) prefetchImages(ctx context.Context, jobs []imageUploadJob, result *ImportResult) {
+	if h.storage == nil || h.uploadConcurrency < 1 || len(jobs) == 0 {
+		return
+	}
+
+	// Dedupe by image path; the cache is keyed by path
+	seen := make(map[string]bool, len(jobs))
+	pending := make([]imageUploadJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.imagePath == "" || seen[job.imagePath] {
+			continue
+		}
+		seen[job.imagePath] = true
+		pending = append(pending, job)
+	}
+
+	workers := h.uploadConcurrency
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	start := time.Now()
+	queue := make(chan imageUploadJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				h.maybeUploadImage(ctx, job.imagePath, job.category, job.itemName, result)
+			}
+		}()
+	}
+
+feed:
+	for _, job := range pending {
+		select {
+		case <-ctx.Done():
+			break feed
+		case queue <- job:
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if workers > 0 {
+		fmt.Printf("    Images: %d processed in %s (%d workers)\n", len(pending), time.Since(start).Round(time.Millisecond), workers)
+	}
+}
+
+// maybeUploadImage uploads an image only if the item doesn't already have one.
+// Safe for concurrent use.
 func (h *HTMLImporterV2) maybeUploadImage(ctx context.Context, imagePath, category, itemName string, result *ImportResult) string {
 	if imagePath == "" || h.storage == nil {
 		return ""
 	}
 
-	normalized := NormalizeItemName(itemName)
+	normalized := h.names.Normalize(itemName)
 	if h.existingImageURLs[normalized] {
 		return "" // already has image
 	}
 
-	if url, ok := h.imageCache[imagePath]; ok {
+	h.mu.Lock()
+	url, ok := h.imageCache[imagePath]
+	h.mu.Unlock()
+	if ok {
 		return url
 	}
 
+	if ctx.Err() != nil {
+		return ""
+	}
+
 	imageFilename := filepath.Base(imagePath)
 	localPath := filepath.Join(h.iconsPath, imageFilename)
 	data, err := os.ReadFile(localPath)
 	if err != nil {
 		data = h.findImageFileCaseInsensitive(imageFilename)
 		if data == nil {
+			h.mu.Lock()
+			// Cache the miss so a prefetched path isn't counted twice
+			h.imageCache[imagePath] = ""
 			if result != nil {
 				result.ImagesMissing++
 			}
+			h.mu.Unlock()
 			return ""
 		}
 	}
@@ -832,133 +1466,77 @@ func (h *HTMLImporterV2) maybeUploadImage(ctx context.Context, imagePath, catego
 
 	if h.dryRun {
 		url := fmt.Sprintf("[dry-run] %s", storagePath)
+		h.mu.Lock()
 		h.imageCache[imagePath] = url
 		if result != nil {
 			result.ImagesUploaded++
 		}
+		h.mu.Unlock()
 		return url
 	}
 
-	publicURL, err := h.storage.UploadImage(ctx, storagePath, data, "image/png")
+	// Identical icons (rings, amulets, shared base art) are stored once
+	hash := HashImage(data)
+	known, err := h.imageRepo.GetImageURLByHash(ctx, hash)
 	if err != nil {
-		fmt.Printf("    Error uploading image for %s: %v\n", itemName, err)
-		return ""
+		h.importError(result, "Error looking up image hash for %s: %v", itemName, err)
 	}
-
-	h.imageCache[imagePath] = publicURL
-	if result != nil {
-		result.ImagesUploaded++
+	if known != nil {
+		h.mu.Lock()
+		h.imageCache[imagePath] = known.URL
+		if known.ThumbURL != "" {
+			h.thumbURLs[known.URL] = known.ThumbURL
+		}
+		if result != nil {
+			result.ImagesDeduplicated++
+		}
+		h.mu.Unlock()
+		return known.URL
 	}
-	return publicURL
-}
 
-// combineAllAttributes detects when str, dex, vit, and enr all share the same
-// min/max values and replaces them with a single "all-stats" property.
-// If values differ or not all 4 are present, the properties are returned unchanged.
-func combineAllAttributes(props []Property, translator *PropertyTranslator) []Property {
-	attrCodes := map[string]int{"str": -1, "dex": -1, "vit": -1, "enr": -1}
-	for i, p := range props {
-		if _, ok := attrCodes[p.Code]; ok {
-			attrCodes[p.Code] = i
-		}
+	publicURL, err := h.storage.UploadImage(ctx, storagePath, data, "image/png")
+	if err != nil {
+		h.importError(result, "Error uploading image for %s: %v", itemName, err)
+		return ""
 	}
 
-	// Check all 4 are present
-	for _, idx := range attrCodes {
-		if idx == -1 {
-			return props
-		}
+	// A missing thumbnail never fails the image itself
+	thumbURL, err := storage.UploadThumbnail(ctx, h.storage, storagePath, data)
+	if err != nil {
+		h.importError(result, "Error uploading thumbnail for %s: %v", itemName, err)
 	}
 
-	// Check all share the same min/max
-	ref := props[attrCodes["str"]]
-	for _, code := range []string{"dex", "vit", "enr"} {
-		p := props[attrCodes[code]]
-		if p.Min != ref.Min || p.Max != ref.Max {
-			return props
-		}
+	if err := h.imageRepo.PutImageHash(ctx, ImageHash{Hash: hash, URL: publicURL, ThumbURL: thumbURL}); err != nil {
+		h.importError(result, "Error recording image hash for %s: %v", itemName, err)
 	}
 
-	// Build replacement: keep all non-attribute props, insert all-stats at first attribute position
-	firstIdx := len(props)
-	for _, idx := range attrCodes {
-		if idx < firstIdx {
-			firstIdx = idx
+	h.mu.Lock()
+	h.imageCache[imagePath] = publicURL
+	if thumbURL != "" {
+		h.thumbURLs[publicURL] = thumbURL
+	}
+	if result != nil {
+		result.ImagesUploaded++
+		if thumbURL != "" {
+			result.ThumbnailsUploaded++
 		}
 	}
+	h.mu.Unlock()
+	return publicURL
+}
 
-	removeSet := map[int]bool{
-		attrCodes["str"]: true,
-		attrCodes["dex"]: true,
-		attrCodes["vit"]: true,
-		attrCodes["enr"]: true,
+// storeThumbnailURLs saves the thumbnails uploaded during this import. Rows
+// are matched by image_url, so this runs after every item has been upserted.
+func (h *HTMLImporterV2) storeThumbnailURLs(ctx context.Context) error {
+	if h.dryRun || len(h.thumbURLs) == 0 {
+		return nil
 	}
-
-	allStats := Property{
-		Code: "all-stats",
-		Min:  ref.Min,
-		Max:  ref.Max,
+	updated, err := h.repo.SetThumbnailURLs(ctx, h.thumbURLs)
+	if err != nil {
+		return err
 	}
-	translator.EnrichProperty(&allStats)
-
-	result := make([]Property, 0, len(props)-3)
-	inserted := false
-	for i, p := range props {
-		if removeSet[i] {
-			if i == firstIdx {
-				result = append(result, allStats)
-				inserted = true
-			}
-			continue
-		}
-		result = append(result, p)
-	}
-	if !inserted {
-		result = append(result, allStats)
-	}
-
-	return result
-}
-
-// parseGemNameParts extracts gem type and quality from a gem name
-func parseGemNameParts(name string) (gemType, quality string) {
-	nameLower := strings.ToLower(name)
-
-	// Determine quality
-	switch {
-	case strings.HasPrefix(nameLower, "chipped"):
-		quality = "chipped"
-	case strings.HasPrefix(nameLower, "flawed"):
-		quality = "flawed"
-	case strings.HasPrefix(nameLower, "flawless"):
-		quality = "flawless"
-	case strings.HasPrefix(nameLower, "perfect"):
-		quality = "perfect"
-	default:
-		quality = "normal"
-	}
-
-	// Determine gem type
-	switch {
-	case strings.Contains(nameLower, "amethyst"):
-		gemType = "amethyst"
-	case strings.Contains(nameLower, "sapphire"):
-		gemType = "sapphire"
-	case strings.Contains(nameLower, "emerald"):
-		gemType = "emerald"
-	case strings.Contains(nameLower, "ruby"):
-		gemType = "ruby"
-	case strings.Contains(nameLower, "diamond"):
-		gemType = "diamond"
-	case strings.Contains(nameLower, "topaz"):
-		gemType = "topaz"
-	case strings.Contains(nameLower, "skull"):
-		gemType = "skull"
-	default:
-		gemType = "unknown"
-	}
-
-	return
+	fmt.Printf("    Linked %d thumbnails\n", updated)
+	return nil
 }
 
 func (h *HTMLImporterV2) findImageFileCaseInsensitive(filename string) []byte {
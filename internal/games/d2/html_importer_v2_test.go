@@ -0,0 +1,209 @@
+package d2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+)
+
+func TestSkipPlaceholderBase(t *testing.T) {
+	filter, err := NewPlaceholderFilter(DefaultPlaceholderPatterns)
+	if err != nil {
+		t.Fatalf("NewPlaceholderFilter: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		opts            ImportOptions
+		item            string
+		wantSkip        bool
+		wantPlaceholder bool
+		wantFiltered    int
+		wantImported    int
+	}{
+		{"real base", ImportOptions{}, "Archon Plate", false, false, 0, 0},
+		{"placeholder skipped by default", ImportOptions{}, "Expansion Shield", true, false, 1, 0},
+		{"placeholder kept when included", ImportOptions{IncludePlaceholders: true}, "Not Used Axe", false, true, 0, 1},
+		{"real base when included", ImportOptions{IncludePlaceholders: true}, "Archon Plate", false, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HTMLImporterV2{placeholders: filter, opts: tt.opts}
+			result := &ImportResult{}
+
+			skip, placeholder := h.skipPlaceholderBase(tt.item, result)
+			if skip != tt.wantSkip || placeholder != tt.wantPlaceholder {
+				t.Errorf("skipPlaceholderBase(%q) = %v, %v; want %v, %v", tt.item, skip, placeholder, tt.wantSkip, tt.wantPlaceholder)
+			}
+			if result.PlaceholdersFiltered != tt.wantFiltered || result.PlaceholdersImported != tt.wantImported {
+				t.Errorf("filtered %d, imported %d; want %d, %d",
+					result.PlaceholdersFiltered, result.PlaceholdersImported, tt.wantFiltered, tt.wantImported)
+			}
+		})
+	}
+}
+
+func TestTranslateSetBonusKeepsOrAlternatives(t *testing.T) {
+	repo := NewRepository(dbtest.NewFake())
+	h := NewHTMLImporterV2(repo, NewStatRegistry(repo), nil, true)
+	ctx := context.Background()
+
+	bonus := h.translateSetBonus(ctx, "+3 to Mana after each Kill or \n30% Deadly Strike", 2)
+	if bonus.Min != 3 || bonus.ItemCount != 2 {
+		t.Fatalf("bonus = %+v, want +3 mana after each kill at 2 items", bonus)
+	}
+	if len(bonus.Alternatives) != 1 {
+		t.Fatalf("got %d alternatives, want 1", len(bonus.Alternatives))
+	}
+	if alt := bonus.Alternatives[0]; alt.Code != "deadly" || alt.Min != 30 {
+		t.Errorf("alternative = %+v, want deadly 30", alt)
+	}
+
+	// A plain bonus of the same stat must not absorb the "or" bonus
+	plain := h.translateSetBonus(ctx, "+5 to Mana after each Kill", 3)
+	props := ApplyCombineRules([]Property{bonus, plain}, h.combineRules, h.translator)
+	if len(props) != 2 || len(props[0].Alternatives) != 1 {
+		t.Errorf("ApplyCombineRules merged the \"or\" bonus: %+v", props)
+	}
+
+	summed := SumProperties(h.translator, []Property{bonus}, []Property{plain}, []Property{bonus})
+	if len(summed) != 2 {
+		t.Fatalf("SumProperties returned %d properties, want 2: %+v", len(summed), summed)
+	}
+	if got := summed[0]; got.Min != 3 || len(got.Alternatives) != 1 {
+		t.Errorf("\"or\" bonus = %+v, want it kept once, unsummed", got)
+	}
+	if got := summed[1]; got.Min != 5 || len(got.Alternatives) != 0 {
+		t.Errorf("plain bonus = %+v, want +5 mana after each kill", got)
+	}
+}
+
+// writeUniquesCatalog writes a catalog with the given uniques.html articles
+// and empty set and runeword pages
+func writeUniquesCatalog(t *testing.T, articles ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	pages := filepath.Join(dir, "pages")
+	if err := os.MkdirAll(pages, 0o755); err != nil {
+		t.Fatalf("mkdir pages: %v", err)
+	}
+	files := map[string]string{
+		"uniques.html":   strings.Join(articles, "\n"),
+		"sets.html":      "",
+		"runewords.html": "",
+	}
+	for name, body := range files {
+		page := "<html><body>" + body + "</body></html>"
+		if err := os.WriteFile(filepath.Join(pages, name), []byte(page), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestImportAllTransactionalStopsAtFirstError(t *testing.T) {
+	dir := writeUniquesCatalog(t,
+		uniqueArticle("Harlequin Crest", "Shako"),
+		uniqueArticle("Tyrael's Might", "Sacred Armor"),
+		uniqueArticle("Griffon's Eye", "Diadem"),
+	)
+
+	// The failed insert aborts the Postgres transaction, so nothing after it
+	// can succeed
+	db := dbtest.NewFake().OnError("INSERT INTO d2.unique_items", errors.New("duplicate key value"))
+	repo := NewRepository(db)
+	h := NewHTMLImporterV2(repo, NewStatRegistry(repo), nil, false)
+	h.SetTransactional(true)
+
+	result, err := h.ImportAll(context.Background(), dir, ImportOptions{})
+	if err == nil {
+		t.Fatal("ImportAll succeeded, want the failed insert to abort it")
+	}
+	if n := db.Count("INSERT INTO d2.unique_items"); n != 1 {
+		t.Errorf("ran %d unique inserts, want 1", n)
+	}
+	if result != nil && len(result.Errors) != 1 {
+		t.Errorf("report has %d errors, want only the failed insert: %v", len(result.Errors), result.Errors)
+	}
+}
+
+func TestImportAllPostStepFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		transactional bool
+		wantErr       bool
+	}{
+		{"plain import warns", false, false},
+		{"transactional import rolls back", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeUniquesCatalog(t, uniqueArticle("Harlequin Crest", "Shako"))
+			// Stale item reconciliation runs after every item is written
+			db := dbtest.NewFake().OnError("FROM d2.unique_items WHERE enabled = true", errors.New("connection reset"))
+			repo := NewRepository(db)
+			h := NewHTMLImporterV2(repo, NewStatRegistry(repo), nil, false)
+			h.SetTransactional(tt.transactional)
+
+			_, err := h.ImportAll(context.Background(), dir, ImportOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImportAll error = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// slowStorage is urlStorage with a fixed upload latency, standing in for a
+// network round trip
+type slowStorage struct {
+	urlStorage
+	latency time.Duration
+}
+
+func (s slowStorage) UploadImage(ctx context.Context, path string, data []byte, contentType string) (string, error) {
+	time.Sleep(s.latency)
+	return s.urlStorage.UploadImage(ctx, path, data, contentType)
+}
+
+// BenchmarkImageUploads measures an import phase's image uploads (prefetch
+// plus the serial loop's cache lookups) over many new icons, inline versus on
+// the worker pool
+func BenchmarkImageUploads(b *testing.B) {
+	const icons = 200
+	dir := b.TempDir()
+	jobs := make([]imageUploadJob, 0, icons)
+	for i := 0; i < icons; i++ {
+		name := fmt.Sprintf("icon%03d.png", i)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			b.Fatalf("write icon: %v", err)
+		}
+		jobs = append(jobs, imageUploadJob{"/images/" + name, "d2/unique", fmt.Sprintf("Unique %03d", i)})
+	}
+
+	for _, workers := range []int{0, 1, DefaultUploadConcurrency, 32} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				repo := NewRepository(dbtest.NewFake())
+				h := NewHTMLImporterV2(repo, NewStatRegistry(repo), slowStorage{latency: 2 * time.Millisecond}, false)
+				h.iconsPath = dir
+				h.SetUploadConcurrency(workers)
+				result := &ImportResult{}
+
+				h.prefetchImages(context.Background(), jobs, result)
+				for _, job := range jobs {
+					h.maybeUploadImage(context.Background(), job.imagePath, job.category, job.itemName, result)
+				}
+				if result.ImagesUploaded != icons {
+					b.Fatalf("uploaded %d images, want %d", result.ImagesUploaded, icons)
+				}
+			}
+		})
+	}
+}
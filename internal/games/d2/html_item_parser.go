@@ -42,8 +42,8 @@ type HTMLSetBonus struct {
 // HTMLParsedFullSet represents a full set definition from HTML
 type HTMLParsedFullSet struct {
 	Name           string
-	PartialBonuses []string // Text of partial set bonuses
-	FullBonuses    []string // Text of full set bonuses
+	PartialBonuses []HTMLSetBonus // Partial set bonuses with the item count that activates them
+	FullBonuses    []string       // Text of full set bonuses
 }
 
 // HTMLVariantLink represents a link to a base item variant (normal/exceptional/elite)
@@ -1021,6 +1021,21 @@ func (p *HTMLItemParser) extractSetBonuses(stats *goquery.Selection) []HTMLSetBo
 	return bonuses
 }
 
+// setItemCountRegex matches a trailing "(2 Items)" / "(3 set items)" label
+var setItemCountRegex = regexp.MustCompile(`(?i)\s*\((\d+) (?:set )?items?\)\s*$`)
+
+// splitSetItemCount separates a partial set bonus line from its trailing item
+// count label. The count is 0 when the line has no label.
+func splitSetItemCount(line string) HTMLSetBonus {
+	line = strings.TrimSpace(line)
+	m := setItemCountRegex.FindStringSubmatchIndex(line)
+	if m == nil {
+		return HTMLSetBonus{Text: line}
+	}
+	count, _ := strconv.Atoi(line[m[2]:m[3]])
+	return HTMLSetBonus{Text: strings.TrimSpace(line[:m[0]]), ItemCount: count}
+}
+
 // isJustNumber checks if a string is just a number (leftover from stat spans)
 func isJustNumber(s string) bool {
 	s = strings.TrimSpace(s)
@@ -1040,7 +1055,32 @@ func (p *HTMLItemParser) parseFullSetArticle(s *goquery.Selection) HTMLParsedFul
 		return fs
 	}
 
-	// Extract bonuses from p.z-smallstats sections
+	// Current layout: "Partial set completion:" and "Full set completion:"
+	// headers, each followed by a span.z-smallstats with one bonus per line.
+	// Partial lines end with "(N Items)".
+	s.Find("h4.z-uniques-title").Each(func(i int, h4 *goquery.Selection) {
+		title := strings.ToLower(h4.Text())
+		full := strings.Contains(title, "full set")
+		if !full && !strings.Contains(title, "partial set") {
+			return
+		}
+		html, _ := h4.NextFiltered("span.z-smallstats").Html()
+		for _, line := range p.cleanPropertyHTML(html) {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if full {
+				fs.FullBonuses = append(fs.FullBonuses, line)
+			} else {
+				fs.PartialBonuses = append(fs.PartialBonuses, splitSetItemCount(line))
+			}
+		}
+	})
+	if len(fs.PartialBonuses) > 0 || len(fs.FullBonuses) > 0 {
+		return fs
+	}
+
+	// Older layout: p.z-smallstats sections
 	// Partial bonuses have "(N set items)" labels, full set bonuses come after "Full Set"
 	inFullSet := false
 	s.Find("p.z-smallstats").Each(func(i int, stats *goquery.Selection) {
@@ -1065,7 +1105,7 @@ func (p *HTMLItemParser) parseFullSetArticle(s *goquery.Selection) HTMLParsedFul
 			if inFullSet {
 				fs.FullBonuses = append(fs.FullBonuses, line)
 			} else {
-				fs.PartialBonuses = append(fs.PartialBonuses, line)
+				fs.PartialBonuses = append(fs.PartialBonuses, splitSetItemCount(line))
 			}
 		}
 	})
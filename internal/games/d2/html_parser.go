@@ -101,13 +101,8 @@ func normalizeImagePath(path string) string {
 	return path
 }
 
-// NormalizeItemName normalizes an item name for matching
+// NormalizeItemName normalizes an item name for matching using
+// DefaultNameNormalizer (lowercase, trim, straighten curly quotes)
 func NormalizeItemName(name string) string {
-	name = strings.ToLower(strings.TrimSpace(name))
-	// Convert curly quotes to straight quotes (using Unicode code points)
-	name = strings.ReplaceAll(name, "\u2018", "'") // Left single quote
-	name = strings.ReplaceAll(name, "\u2019", "'") // Right single quote
-	name = strings.ReplaceAll(name, "\u201C", "\"") // Left double quote
-	name = strings.ReplaceAll(name, "\u201D", "\"") // Right double quote
-	return name
+	return DefaultNameNormalizer.Normalize(name)
 }
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-catalog-api/internal/storage"
 )
@@ -49,6 +51,7 @@ var fallbackIconByName = map[string]string{
 type UploadStats struct {
 	TotalDBItems   int
 	Uploaded       int
+	Thumbnails     int
 	ReusedCache    int
 	MatchedUnique  int
 	MatchedSet     int
@@ -58,8 +61,9 @@ type UploadStats struct {
 	NotInHTML      int
 	MissingFiles   int
 	Errors         int
-	MissingImages  []string // Images referenced in HTML but not in icons folder
-	NotInHTMLItems []string // Items not found in HTML files
+	UploadTime     time.Duration // Wall time spent uploading, across all workers
+	MissingImages  []string      // Images referenced in HTML but not in icons folder
+	NotInHTMLItems []string      // Items not found in HTML files
 }
 
 // IconUploader handles uploading local images to Supabase
@@ -71,19 +75,56 @@ type IconUploader struct {
 	iconsPath  string
 	pagesPath  string
 	imageCache map[string]string // imagePath -> uploadedURL
+	thumbURLs  map[string]string // uploadedURL -> thumbnail URL
+
+	// Uploads for each item type run on a bounded worker pool; mu guards
+	// imageCache, thumbURLs and the upload counters on UploadStats
+	concurrency int
+	mu          sync.Mutex
 }
 
 // NewIconUploader creates a new icon uploader
 func NewIconUploader(repo *Repository, stor storage.Storage, dryRun bool, force bool) *IconUploader {
 	return &IconUploader{
-		repo:       repo,
-		storage:    stor,
-		dryRun:     dryRun,
-		force:      force,
-		imageCache: make(map[string]string),
+		repo:        repo,
+		storage:     stor,
+		dryRun:      dryRun,
+		force:       force,
+		imageCache:  make(map[string]string),
+		thumbURLs:   make(map[string]string),
+		concurrency: DefaultUploadConcurrency,
 	}
 }
 
+// SetConcurrency sets how many images upload in parallel (values below 1
+// upload one at a time). Dry runs always use a single worker so their output
+// order is stable.
+func (u *IconUploader) SetConcurrency(n int) {
+	u.concurrency = n
+}
+
+// iconJob is one distinct image path to upload, stored under the name of the
+// first item that uses it
+type iconJob struct {
+	imagePath string
+	itemName  string
+}
+
+// iconUpload is the outcome of one iconJob
+type iconUpload struct {
+	url      string
+	thumbURL string
+	missing  bool // No local file for the image path
+	err      error
+	thumbErr error
+}
+
+// iconMatch is a database item and the image path it resolved to
+type iconMatch struct {
+	item      ItemWithoutImage
+	imagePath string
+}
+
 // Upload scans HTML files for item-image mappings and uploads images
 func (u *IconUploader) Upload(ctx context.Context, catalogPath string) (*UploadStats, error) {
 	stats := &UploadStats{}
@@ -174,6 +215,14 @@ func (u *IconUploader) Upload(ctx context.Context, catalogPath string) (*UploadS
 		fmt.Printf("  Warning: Icon variant upload encountered errors: %v\n", err)
 	}
 
+	// Link thumbnails to every row using the uploaded images
+	if len(u.thumbURLs) > 0 {
+		if _, err := u.repo.SetThumbnailURLs(ctx, u.thumbURLs); err != nil {
+			fmt.Printf("  Error storing thumbnail URLs: %v\n", err)
+			stats.Errors++
+		}
+	}
+
 	return stats, nil
 }
 
@@ -204,6 +253,10 @@ func (u *IconUploader) processItemType(ctx context.Context, itemType, category s
 	stats.TotalDBItems += len(items)
 	fmt.Printf("  Loaded %d %s items from database\n", len(items), itemType)
 
+	// Resolve each item's image path, queueing each new path once
+	var matches []iconMatch
+	var jobs []iconJob
+	queued := make(map[string]bool)
 	for _, item := range items {
 		normalizedName := normalizeForMatch(item.Name)
 
@@ -231,72 +284,156 @@ func (u *IconUploader) processItemType(ctx context.Context, itemType, category s
 			continue
 		}
 
-		// Check if we've already uploaded this image
-		if cachedURL, exists := u.imageCache[imagePath]; exists {
-			// Reuse cached URL
-			if !u.dryRun {
-				if err := u.updateItemURL(ctx, item, cachedURL); err != nil {
-					stats.Errors++
-					continue
-				}
-			}
-			stats.ReusedCache++
-			u.incrementMatchCount(itemType, stats)
-			continue
+		matches = append(matches, iconMatch{item: item, imagePath: imagePath})
+		u.mu.Lock()
+		_, cached := u.imageCache[imagePath]
+		u.mu.Unlock()
+		if !cached && !queued[imagePath] {
+			queued[imagePath] = true
+			jobs = append(jobs, iconJob{imagePath: imagePath, itemName: item.Name})
 		}
+	}
 
-		// Extract filename from image path
-		imageFilename := filepath.Base(imagePath)
+	// Upload every new image path, then link items serially in load order
+	results := u.uploadAll(ctx, category, jobs, stats)
 
-		// Try to find the image file (check for variations with (1), (2), etc.)
-		imageData, foundPath := u.findImageFile(imageFilename)
-		if imageData == nil {
+	counted := make(map[string]bool, len(results))
+	for _, m := range matches {
+		res, uploadedNow := results[m.imagePath]
+		if uploadedNow && res.missing {
 			stats.MissingFiles++
 			if len(stats.MissingImages) < 50 {
-				stats.MissingImages = append(stats.MissingImages, fmt.Sprintf("%s (for %s)", imageFilename, item.Name))
+				stats.MissingImages = append(stats.MissingImages, fmt.Sprintf("%s (for %s)", filepath.Base(m.imagePath), m.item.Name))
 			}
 			continue
 		}
-		_ = foundPath // Used for debugging if needed
+		if uploadedNow && res.err != nil {
+			continue // Already counted and reported by the worker
+		}
 
-		// Upload image
-		storagePath := storage.StoragePath(category, item.Name)
-		contentType := "image/png"
-		if strings.HasSuffix(strings.ToLower(imageFilename), ".jpg") || strings.HasSuffix(strings.ToLower(imageFilename), ".jpeg") {
-			contentType = "image/jpeg"
+		u.mu.Lock()
+		url := u.imageCache[m.imagePath]
+		u.mu.Unlock()
+
+		if !u.dryRun {
+			if err := u.updateItemURL(ctx, m.item, url); err != nil {
+				fmt.Printf("  Error updating DB for %s: %v\n", m.item.Name, err)
+				stats.Errors++
+				continue
+			}
 		}
 
-		if u.dryRun {
-			fmt.Printf("  [DRY-RUN] Would upload %s -> %s\n", imageFilename, storagePath)
-			u.imageCache[imagePath] = "dry-run-url"
+		if uploadedNow && !counted[m.imagePath] {
+			counted[m.imagePath] = true
 			stats.Uploaded++
-			u.incrementMatchCount(itemType, stats)
-			continue
+		} else {
+			stats.ReusedCache++
 		}
+		u.incrementMatchCount(itemType, stats)
+	}
 
-		publicURL, err := u.storage.UploadImage(ctx, storagePath, imageData, contentType)
-		if err != nil {
-			fmt.Printf("  Error uploading %s: %v\n", imageFilename, err)
-			stats.Errors++
-			continue
-		}
+	return nil
+}
 
-		// Cache the URL for reuse
-		u.imageCache[imagePath] = publicURL
+// uploadAll uploads the jobs on a bounded worker pool and returns the outcome
+// per image path. Successful uploads are added to the image cache.
+func (u *IconUploader) uploadAll(ctx context.Context, category string, jobs []iconJob, stats *UploadStats) map[string]iconUpload {
+	results := make(map[string]iconUpload, len(jobs))
+	if len(jobs) == 0 {
+		return results
+	}
 
-		// Update database
-		if err := u.updateItemURL(ctx, item, publicURL); err != nil {
-			fmt.Printf("  Error updating DB for %s: %v\n", item.Name, err)
-			stats.Errors++
-			continue
+	workers := u.concurrency
+	if u.dryRun || workers < 1 {
+		workers = 1
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	start := time.Now()
+	queue := make(chan iconJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				res := u.uploadOne(ctx, category, job)
+
+				u.mu.Lock()
+				results[job.imagePath] = res
+				if res.url != "" {
+					u.imageCache[job.imagePath] = res.url
+				}
+				if res.thumbURL != "" {
+					u.thumbURLs[res.url] = res.thumbURL
+					stats.Thumbnails++
+				}
+				if res.err != nil {
+					stats.Errors++
+				}
+				if res.thumbErr != nil {
+					stats.Errors++
+				}
+				u.mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, job := range jobs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case queue <- job:
 		}
+	}
+	close(queue)
+	wg.Wait()
 
-		stats.Uploaded++
-		u.incrementMatchCount(itemType, stats)
-		fmt.Printf("  ✓ %s -> %s\n", item.Name, storagePath)
+	elapsed := time.Since(start)
+	stats.UploadTime += elapsed
+	fmt.Printf("  Images: %d processed in %s (%.1f/s, %d workers)\n",
+		len(results), elapsed.Round(time.Millisecond), float64(len(results))/elapsed.Seconds(), workers)
+	return results
+}
+
+// uploadOne uploads the image and thumbnail for one job. Safe for concurrent
+// use: it only reads uploader state.
+func (u *IconUploader) uploadOne(ctx context.Context, category string, job iconJob) iconUpload {
+	imageFilename := filepath.Base(job.imagePath)
+
+	// Try to find the image file (check for variations with (1), (2), etc.)
+	imageData, _ := u.findImageFile(imageFilename)
+	if imageData == nil {
+		return iconUpload{missing: true}
 	}
 
-	return nil
+	storagePath := storage.StoragePath(category, job.itemName)
+	contentType := "image/png"
+	if strings.HasSuffix(strings.ToLower(imageFilename), ".jpg") || strings.HasSuffix(strings.ToLower(imageFilename), ".jpeg") {
+		contentType = "image/jpeg"
+	}
+
+	if u.dryRun {
+		fmt.Printf("  [DRY-RUN] Would upload %s -> %s\n", imageFilename, storagePath)
+		return iconUpload{url: "dry-run-url"}
+	}
+
+	publicURL, err := u.storage.UploadImage(ctx, storagePath, imageData, contentType)
+	if err != nil {
+		fmt.Printf("  Error uploading %s: %v\n", imageFilename, err)
+		return iconUpload{err: err}
+	}
+
+	res := iconUpload{url: publicURL}
+	res.thumbURL, res.thumbErr = storage.UploadThumbnail(ctx, u.storage, storagePath, imageData)
+	if res.thumbErr != nil {
+		fmt.Printf("  Error uploading thumbnail for %s: %v\n", imageFilename, res.thumbErr)
+	}
+	fmt.Printf("  ✓ %s -> %s\n", job.itemName, storagePath)
+	return res
 }
 
 func (u *IconUploader) incrementMatchCount(itemType string, stats *UploadStats) {
@@ -332,11 +469,11 @@ func (u *IconUploader) updateItemURL(ctx context.Context, item ItemWithoutImage,
 
 // Load functions for each item type
 func (u *IconUploader) loadAllUniques(ctx context.Context) ([]ItemWithoutImage, error) {
-	query := `SELECT id, name FROM d2.unique_items ORDER BY id`
+	query := `SELECT id, name FROM ` + tableUniqueItems + ` ORDER BY id`
 	if !u.force {
-		query = `SELECT id, name FROM d2.unique_items WHERE image_url IS NULL OR image_url = '' ORDER BY id`
+		query = `SELECT id, name FROM ` + tableUniqueItems + ` WHERE image_url IS NULL OR image_url = '' ORDER BY id`
 	}
-	rows, err := u.repo.pool.Query(ctx, query)
+	rows, err := u.repo.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -355,11 +492,11 @@ func (u *IconUploader) loadAllUniques(ctx context.Context) ([]ItemWithoutImage,
 }
 
 func (u *IconUploader) loadAllSets(ctx context.Context) ([]ItemWithoutImage, error) {
-	query := `SELECT id, name FROM d2.set_items ORDER BY id`
+	query := `SELECT id, name FROM ` + tableSetItems + ` ORDER BY id`
 	if !u.force {
-		query = `SELECT id, name FROM d2.set_items WHERE image_url IS NULL OR image_url = '' ORDER BY id`
+		query = `SELECT id, name FROM ` + tableSetItems + ` WHERE image_url IS NULL OR image_url = '' ORDER BY id`
 	}
-	rows, err := u.repo.pool.Query(ctx, query)
+	rows, err := u.repo.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -378,11 +515,11 @@ func (u *IconUploader) loadAllSets(ctx context.Context) ([]ItemWithoutImage, err
 }
 
 func (u *IconUploader) loadAllBases(ctx context.Context) ([]ItemWithoutImage, error) {
-	query := `SELECT code, name FROM d2.item_bases ORDER BY code`
+	query := `SELECT code, name FROM ` + tableItemBases + ` ORDER BY code`
 	if !u.force {
-		query = `SELECT code, name FROM d2.item_bases WHERE image_url IS NULL OR image_url = '' ORDER BY code`
+		query = `SELECT code, name FROM ` + tableItemBases + ` WHERE image_url IS NULL OR image_url = '' ORDER BY code`
 	}
-	rows, err := u.repo.pool.Query(ctx, query)
+	rows, err := u.repo.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -401,11 +538,11 @@ func (u *IconUploader) loadAllBases(ctx context.Context) ([]ItemWithoutImage, er
 }
 
 func (u *IconUploader) loadAllRunes(ctx context.Context) ([]ItemWithoutImage, error) {
-	query := `SELECT id, code, name FROM d2.runes ORDER BY id`
+	query := `SELECT id, code, name FROM ` + tableRunes + ` ORDER BY id`
 	if !u.force {
-		query = `SELECT id, code, name FROM d2.runes WHERE image_url IS NULL OR image_url = '' ORDER BY id`
+		query = `SELECT id, code, name FROM ` + tableRunes + ` WHERE image_url IS NULL OR image_url = '' ORDER BY id`
 	}
-	rows, err := u.repo.pool.Query(ctx, query)
+	rows, err := u.repo.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -424,11 +561,11 @@ func (u *IconUploader) loadAllRunes(ctx context.Context) ([]ItemWithoutImage, er
 }
 
 func (u *IconUploader) loadAllGems(ctx context.Context) ([]ItemWithoutImage, error) {
-	query := `SELECT id, code, name FROM d2.gems ORDER BY id`
+	query := `SELECT id, code, name FROM ` + tableGems + ` ORDER BY id`
 	if !u.force {
-		query = `SELECT id, code, name FROM d2.gems WHERE image_url IS NULL OR image_url = '' ORDER BY id`
+		query = `SELECT id, code, name FROM ` + tableGems + ` WHERE image_url IS NULL OR image_url = '' ORDER BY id`
 	}
-	rows, err := u.repo.pool.Query(ctx, query)
+	rows, err := u.repo.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
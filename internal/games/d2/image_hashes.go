@@ -0,0 +1,55 @@
+package d2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ImageHash maps the content hash of an uploaded image to its public URLs
+type ImageHash struct {
+	Hash     string
+	URL      string
+	ThumbURL string // Empty when no thumbnail was uploaded
+}
+
+// HashImage returns the hex SHA-256 of image bytes
+func HashImage(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetImageURLByHash returns the upload recorded for a content hash, or nil
+// when the image hasn't been uploaded yet
+func (r *Repository) GetImageURLByHash(ctx context.Context, hash string) (*ImageHash, error) {
+	ih := ImageHash{Hash: hash}
+	err := r.db.QueryRow(ctx, `
+		SELECT url, COALESCE(thumb_url, '')
+		FROM `+tableImageHashes+`
+		WHERE hash = $1`, hash).Scan(&ih.URL, &ih.ThumbURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("image hash lookup failed: %w", err)
+	}
+	return &ih, nil
+}
+
+// PutImageHash records an uploaded image. The first upload of a hash wins;
+// later uploads of the same bytes keep pointing at it.
+func (r *Repository) PutImageHash(ctx context.Context, ih ImageHash) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableImageHashes+` (hash, url, thumb_url)
+		VALUES ($1, $2, NULLIF($3, ''))
+		ON CONFLICT (hash) DO NOTHING`,
+		ih.Hash, ih.URL, ih.ThumbURL)
+	if err != nil {
+		return fmt.Errorf("image hash insert failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package d2
+
+import "github.com/ruanpelissoli/lootstash-catalog-api/internal/metrics"
+
+// Import metric names, exposed at /metrics
+const (
+	metricItemsImported  = "d2_items_imported_total"
+	metricImportErrors   = "d2_import_errors_total"
+	metricImagesUploaded = "d2_images_uploaded_total"
+	metricImports        = "d2_imports_total"
+)
+
+func init() {
+	metrics.Default.NewCounter(metricItemsImported, "Rows imported, by category")
+	metrics.Default.NewCounter(metricImportErrors, "Rows or import steps that failed")
+	metrics.Default.NewCounter(metricImagesUploaded, "Item images uploaded by imports")
+	metrics.Default.NewCounter(metricImports, "Import runs, by outcome")
+}
+
+// recordImportMetrics adds a finished import's counts to the metrics
+// registry. Partial results of failed imports are recorded too.
+func recordImportMetrics(result *ImportResult, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.Default.Inc(metricImports, metrics.Labels{"outcome": outcome})
+	if result == nil {
+		return
+	}
+
+	for category, stats := range map[string]ImportStats{
+		"item_types":     result.ItemTypes,
+		"item_bases":     result.ItemBases,
+		"unique_items":   result.UniqueItems,
+		"set_bonuses":    result.SetBonuses,
+		"set_items":      result.SetItems,
+		"runewords":      result.Runewords,
+		"runes":          result.Runes,
+		"gems":           result.Gems,
+		"runeword_bases": result.RunewordBases,
+		"stats":          result.Stats,
+	} {
+		metrics.Default.Add(metricItemsImported, metrics.Labels{"category": category}, float64(stats.Imported))
+	}
+	metrics.Default.Add(metricImportErrors, nil, float64(len(result.Errors)))
+	metrics.Default.Add(metricImagesUploaded, nil, float64(result.ImagesUploaded))
+}
@@ -0,0 +1,86 @@
+package d2
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultImportBatchSize is the default number of items per progress batch
+const DefaultImportBatchSize = 250
+
+// BatchProgress describes one finished batch of an import loop
+type BatchProgress struct {
+	Stage   string        // Import loop name ("uniques", "set_items", ...)
+	Batch   int           // 1-based batch number
+	Done    int           // Items processed so far, including this batch
+	Total   int           // Items in the loop
+	Elapsed time.Duration // Time spent on this batch
+}
+
+// ProgressReporter receives per-batch progress from the import loops
+type ProgressReporter interface {
+	BatchDone(p BatchProgress)
+}
+
+// PrintProgressReporter prints batch progress to stdout
+type PrintProgressReporter struct{}
+
+// BatchDone prints one progress line for the batch
+func (PrintProgressReporter) BatchDone(p BatchProgress) {
+	fmt.Printf("      %s: %d/%d (batch %d in %s)\n",
+		p.Stage, p.Done, p.Total, p.Batch, p.Elapsed.Round(time.Millisecond))
+}
+
+// batchTracker splits an import loop into batches and reports each one as
+// it completes. Call next at the top of every iteration (before any
+// continue) and finish after the loop.
+type batchTracker struct {
+	stage    string
+	size     int
+	total    int
+	done     int
+	batch    int
+	start    time.Time
+	reporter ProgressReporter
+}
+
+// newBatchTracker starts tracking a loop over total items. A nil reporter or
+// a size below 1 disables reporting.
+func (h *HTMLImporterV2) newBatchTracker(stage string, total int) *batchTracker {
+	return &batchTracker{
+		stage:    stage,
+		size:     h.batchSize,
+		total:    total,
+		start:    time.Now(),
+		reporter: h.progress,
+	}
+}
+
+// next marks the start of an item, closing the previous batch when full
+func (b *batchTracker) next() {
+	if b.done > 0 && b.size > 0 && b.done%b.size == 0 {
+		b.report()
+	}
+	b.done++
+}
+
+// finish reports the final, possibly partial, batch
+func (b *batchTracker) finish() {
+	if b.size > 0 && b.done > b.batch*b.size {
+		b.report()
+	}
+}
+
+func (b *batchTracker) report() {
+	b.batch++
+	if b.reporter != nil && b.size > 0 {
+		b.reporter.BatchDone(BatchProgress{
+			Stage:   b.stage,
+			Batch:   b.batch,
+			Done:    b.done,
+			Total:   b.total,
+			Elapsed: time.Since(b.start),
+		})
+	}
+	b.start = time.Now()
+}
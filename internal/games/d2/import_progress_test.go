@@ -0,0 +1,58 @@
+package d2
+
+import "testing"
+
+// recordingReporter keeps every batch it's given
+type recordingReporter struct{ batches []BatchProgress }
+
+func (r *recordingReporter) BatchDone(p BatchProgress) { r.batches = append(r.batches, p) }
+
+func TestBatchTrackerLargeImport(t *testing.T) {
+	tests := []struct {
+		name        string
+		total, size int
+		wantBatches int
+		wantLast    int // Items in the final batch
+	}{
+		{"even batches", 10000, 250, 40, 250},
+		{"partial last batch", 10001, 250, 41, 1},
+		{"one batch", 40, 250, 1, 40},
+		{"disabled", 10000, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingReporter{}
+			h := &HTMLImporterV2{batchSize: tt.size, progress: rec}
+
+			progress := h.newBatchTracker("uniques", tt.total)
+			for i := 0; i < tt.total; i++ {
+				progress.next()
+			}
+			progress.finish()
+
+			if len(rec.batches) != tt.wantBatches {
+				t.Fatalf("got %d batches, want %d", len(rec.batches), tt.wantBatches)
+			}
+			if tt.wantBatches == 0 {
+				return
+			}
+			for i, b := range rec.batches[:len(rec.batches)-1] {
+				if b.Batch != i+1 || b.Stage != "uniques" || b.Total != tt.total || b.Done != (i+1)*tt.size {
+					t.Errorf("batch %d = %+v", i+1, b)
+				}
+			}
+			last := rec.batches[len(rec.batches)-1]
+			if last.Batch != tt.wantBatches || last.Done != tt.total || last.Done-(last.Batch-1)*tt.size != tt.wantLast {
+				t.Errorf("last batch = %+v, want %d items ending at %d", last, tt.wantLast, tt.total)
+			}
+		})
+	}
+
+	// A nil reporter disables reporting without breaking the loop
+	h := &HTMLImporterV2{batchSize: 10}
+	progress := h.newBatchTracker("runes", 100)
+	for i := 0; i < 100; i++ {
+		progress.next()
+	}
+	progress.finish()
+}
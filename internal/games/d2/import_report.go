@@ -0,0 +1,110 @@
+package d2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SetReportPath sets a file to write the import result to as JSON after
+// ImportAll. Empty disables the report.
+func (h *HTMLImporterV2) SetReportPath(path string) {
+	h.reportPath = path
+}
+
+// importError prints a failed row or step and records it in result
+func (h *HTMLImporterV2) importError(result *ImportResult, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Printf("    %s\n", msg)
+	if result == nil {
+		return
+	}
+	h.mu.Lock()
+	result.Errors = append(result.Errors, msg)
+	h.mu.Unlock()
+}
+
+// rowError records a failed row write like importError. In a transactional
+// import the failed statement has aborted the transaction, so every later
+// write would fail too; the error is returned to stop the import and roll it
+// back. Otherwise it returns nil and the import moves on to the next row.
+func (h *HTMLImporterV2) rowError(result *ImportResult, format string, args ...interface{}) error {
+	h.importError(result, format, args...)
+	if !h.inTransaction() {
+		return nil
+	}
+	return fmt.Errorf(format, args...)
+}
+
+// ToJSON renders the result as indented JSON. Lists are sorted so reports
+// from separate runs diff cleanly; empty lists render as [] rather than null.
+func (r *ImportResult) ToJSON() ([]byte, error) {
+	out := *r
+	out.Errors = sortedStrings(r.Errors)
+	out.NewStatCodes = sortedStrings(r.NewStatCodes)
+
+	out.StaleItems = append(make([]StaleItem, 0, len(r.StaleItems)), r.StaleItems...)
+	sort.Slice(out.StaleItems, func(i, j int) bool {
+		if out.StaleItems[i].Type != out.StaleItems[j].Type {
+			return out.StaleItems[i].Type < out.StaleItems[j].Type
+		}
+		return out.StaleItems[i].Key < out.StaleItems[j].Key
+	})
+	if out.RawPropertySamples == nil {
+		out.RawPropertySamples = []RawPropertySample{}
+	}
+	if out.Orphans == nil {
+		out.Orphans = []OrphanReport{}
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// reportOrphans records and prints uniques and set items left pointing at a
+// base code that doesn't exist
+func (h *HTMLImporterV2) reportOrphans(ctx context.Context, result *ImportResult) error {
+	fmt.Println("\n  Checking base code references...")
+	orphans, err := h.repo.FindOrphanedItems(ctx)
+	if err != nil {
+		return err
+	}
+	result.Orphans = orphans
+
+	if len(orphans) == 0 {
+		fmt.Println("    All uniques and set items reference an existing base")
+		return nil
+	}
+	fmt.Printf("    %d items reference a missing base:\n", len(orphans))
+	for _, o := range orphans {
+		code := o.BaseCode
+		if code == "" {
+			code = "(none)"
+		}
+		fmt.Printf("      %s: %s -> %s (%s)\n", o.Table, o.Name, code, o.BaseName)
+	}
+	return nil
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append(make([]string, 0, len(values)), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// writeReport writes the result to the configured report path, if any
+func (h *HTMLImporterV2) writeReport(result *ImportResult) error {
+	if h.reportPath == "" {
+		return nil
+	}
+	data, err := result.ToJSON()
+	if err != nil {
+		return fmt.Errorf("encode import report: %w", err)
+	}
+	if err := os.WriteFile(h.reportPath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write import report: %w", err)
+	}
+	fmt.Printf("    Wrote import report to %s\n", h.reportPath)
+	return nil
+}
@@ -0,0 +1,126 @@
+package d2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+)
+
+// urlStorage "uploads" images by returning a URL for their path
+type urlStorage struct{}
+
+func (urlStorage) UploadImage(_ context.Context, path string, _ []byte, _ string) (string, error) {
+	return "https://cdn.test/" + path, nil
+}
+
+func (urlStorage) GetPublicURL(path string) string { return "https://cdn.test/" + path }
+
+func (urlStorage) FileExists(context.Context, string) (bool, error) { return false, nil }
+
+func TestContentHashIgnoresImporterFilledFields(t *testing.T) {
+	parsed := &UniqueItem{Name: "Harlequin Crest", BaseCode: "uap", Enabled: true,
+		Properties: []Property{{Code: "allskills", Min: 2, Max: 2}}}
+	imported := *parsed
+	imported.ID, imported.IndexID, imported.ImageURL = 7, 113, "https://cdn.test/d2/unique/harlequin-crest.png"
+
+	a, err := ContentHash(parsed)
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+	if b, _ := ContentHash(&imported); a != b {
+		t.Error("hash changed with id, index_id and image_url")
+	}
+
+	edited := *parsed
+	edited.Properties = []Property{{Code: "allskills", Min: 3, Max: 3}}
+	if c, _ := ContentHash(&edited); a == c {
+		t.Error("hash unchanged after a property edit")
+	}
+}
+
+func TestImportIncrementalSecondRunSkipsUpserts(t *testing.T) {
+	dir := t.TempDir()
+	page := `<html><body><article class="element-item">
+  <div data-background-image="/images/uniques/harlequin.png"></div>
+  <h3 class="z-sort-name"><a class="z-uniques-title">Harlequin Crest</a></h3>
+  <h4>Unique<br><span class="z-white">Shako</span></h4>
+  <p class="z-smallstats">+2 To All Skills</p>
+</article></body></html>`
+	if err := os.WriteFile(filepath.Join(dir, "uniques.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "icons"), 0o755); err != nil {
+		t.Fatalf("mkdir icons: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "icons", "harlequin.png"), []byte("png"), 0o644); err != nil {
+		t.Fatalf("write icon: %v", err)
+	}
+
+	run := func(db *dbtest.Fake, hasImage bool) *ImportResult {
+		t.Helper()
+		repo := NewRepository(db)
+		h := NewHTMLImporterV2(repo, NewStatRegistry(repo), urlStorage{}, false)
+		h.iconsPath = filepath.Join(dir, "icons")
+		h.incremental = true
+		if hasImage {
+			h.existingImageURLs = map[string]bool{h.names.Normalize("Harlequin Crest"): true}
+		}
+		result := &ImportResult{}
+		if err := h.importUniques(context.Background(), dir, result); err != nil {
+			t.Fatalf("importUniques: %v", err)
+		}
+		return result
+	}
+
+	// The first run uploads the image and stores the hash
+	first := dbtest.NewFake()
+	if result := run(first, false); result.ImagesUploaded != 1 {
+		t.Fatalf("first run uploaded %d images, want 1", result.ImagesUploaded)
+	}
+	if n := first.Count("INSERT INTO d2.unique_items"); n != 1 {
+		t.Fatalf("first run ran %d unique upserts, want 1", n)
+	}
+	var hash string
+	for _, call := range first.Calls() {
+		if strings.Contains(call.SQL, "SET content_hash = $2") {
+			hash = call.Args[1].(string)
+		}
+	}
+	if hash == "" {
+		t.Fatal("first run stored no content hash")
+	}
+
+	// The second run finds the image already stored, so ImageURL is empty
+	second := dbtest.NewFake().On("SELECT content_hash FROM d2.unique_items", []interface{}{hash})
+	result := run(second, true)
+	if n := second.Count("INSERT INTO d2.unique_items"); n != 0 {
+		t.Errorf("second run ran %d unique upserts, want 0", n)
+	}
+	if result.UniqueItems.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", result.UniqueItems.Unchanged)
+	}
+}
+
+func TestSoftDeleteClearsContentHash(t *testing.T) {
+	db := dbtest.NewFake()
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	for _, itemType := range PurgeableItemTypes() {
+		if _, err := repo.PurgeItemType(ctx, itemType); err != nil {
+			t.Fatalf("PurgeItemType(%s): %v", itemType, err)
+		}
+		if _, err := repo.SoftDeleteItemsByKey(ctx, itemType, []string{"x"}); err != nil {
+			t.Fatalf("SoftDeleteItemsByKey(%s): %v", itemType, err)
+		}
+	}
+	for _, call := range db.Calls() {
+		if !strings.Contains(call.SQL, "content_hash = NULL") {
+			t.Errorf("soft delete keeps the content hash, so an unchanged row is never restored: %s", call.SQL)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package d2
+
+import "sort"
+
+// ItemTypeHierarchy resolves the equiv1/equiv2 parent links between item
+// types, e.g. "pole" (Polearm) -> "mele" (Melee Weapon) -> "weap" (Weapon).
+type ItemTypeHierarchy struct {
+	types    map[string]*ItemType
+	children map[string][]string
+}
+
+// NewItemTypeHierarchy indexes item types by code and their direct children
+func NewItemTypeHierarchy(types []ItemType) *ItemTypeHierarchy {
+	h := &ItemTypeHierarchy{
+		types:    make(map[string]*ItemType, len(types)),
+		children: make(map[string][]string),
+	}
+	for i := range types {
+		it := &types[i]
+		h.types[it.Code] = it
+		for _, parent := range []string{it.Equiv1, it.Equiv2} {
+			if parent != "" && parent != it.Code {
+				h.children[parent] = append(h.children[parent], it.Code)
+			}
+		}
+	}
+	for _, codes := range h.children {
+		sort.Strings(codes)
+	}
+	return h
+}
+
+// Get returns the item type with the given code, or nil
+func (h *ItemTypeHierarchy) Get(code string) *ItemType {
+	return h.types[code]
+}
+
+// Ancestors returns every type code reachable through equiv1/equiv2,
+// nearest first. Cycles in the source data are ignored.
+func (h *ItemTypeHierarchy) Ancestors(code string) []string {
+	seen := map[string]bool{code: true}
+	ancestors := make([]string, 0)
+	queue := []string{code}
+	for len(queue) > 0 {
+		it := h.types[queue[0]]
+		queue = queue[1:]
+		if it == nil {
+			continue
+		}
+		for _, parent := range []string{it.Equiv1, it.Equiv2} {
+			if parent == "" || seen[parent] {
+				continue
+			}
+			seen[parent] = true
+			ancestors = append(ancestors, parent)
+			queue = append(queue, parent)
+		}
+	}
+	return ancestors
+}
+
+// Children returns the codes of types that list code as equiv1 or equiv2
+func (h *ItemTypeHierarchy) Children(code string) []string {
+	return h.children[code]
+}
@@ -0,0 +1,115 @@
+package d2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeRule is a single step of item name normalization
+type NormalizeRule func(string) string
+
+// NameNormalizer applies an ordered list of normalization rules to item names.
+// Different data sources can compose their own rules; NormalizeItemName uses
+// DefaultNormalizeRules, which several features (image dedup, name matching)
+// depend on, so changes to the defaults affect dedup correctness.
+type NameNormalizer struct {
+	rules []NormalizeRule
+}
+
+// NewNameNormalizer creates a normalizer that applies the given rules in order
+func NewNameNormalizer(rules ...NormalizeRule) *NameNormalizer {
+	return &NameNormalizer{rules: rules}
+}
+
+// With returns a new normalizer with additional rules appended
+func (n *NameNormalizer) With(rules ...NormalizeRule) *NameNormalizer {
+	combined := make([]NormalizeRule, 0, len(n.rules)+len(rules))
+	combined = append(combined, n.rules...)
+	combined = append(combined, rules...)
+	return &NameNormalizer{rules: combined}
+}
+
+// Normalize applies all rules to name
+func (n *NameNormalizer) Normalize(name string) string {
+	for _, rule := range n.rules {
+		name = rule(name)
+	}
+	return name
+}
+
+// DefaultNormalizeRules are the rules used by NormalizeItemName
+var DefaultNormalizeRules = []NormalizeRule{
+	LowerTrim,
+	StraightenQuotes,
+}
+
+// DefaultNameNormalizer is the normalizer used by NormalizeItemName
+var DefaultNameNormalizer = NewNameNormalizer(DefaultNormalizeRules...)
+
+// OptionalNormalizeRules are the rules that can be enabled on top of the
+// defaults, by name
+var OptionalNormalizeRules = map[string]NormalizeRule{
+	"collapse-whitespace": CollapseWhitespace,
+	"fold-diacritics":     FoldDiacritics,
+}
+
+// ParseNormalizeRules looks up optional rules by name, keeping their order
+func ParseNormalizeRules(names []string) ([]NormalizeRule, error) {
+	rules := make([]NormalizeRule, 0, len(names))
+	for _, name := range names {
+		rule, ok := OptionalNormalizeRules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown normalize rule %q", name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// LowerTrim lowercases and trims surrounding whitespace
+func LowerTrim(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// curlyQuoteReplacer converts curly quotes to straight quotes (using Unicode code points)
+var curlyQuoteReplacer = strings.NewReplacer(
+	"\u2018", "'", // Left single quote
+	"\u2019", "'", // Right single quote
+	"\u201C", "\"", // Left double quote
+	"\u201D", "\"", // Right double quote
+)
+
+// StraightenQuotes converts curly quotes to straight quotes
+func StraightenQuotes(s string) string {
+	return curlyQuoteReplacer.Replace(s)
+}
+
+// CollapseWhitespace replaces runs of whitespace with a single space
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// diacriticFolds maps common accented Latin letters to their ASCII base
+var diacriticFolds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'À': 'A', 'Á': 'A', 'Â': 'A', 'Ã': 'A', 'Ä': 'A', 'Å': 'A',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'È': 'E', 'É': 'E', 'Ê': 'E', 'Ë': 'E',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'Ì': 'I', 'Í': 'I', 'Î': 'I', 'Ï': 'I',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'Ò': 'O', 'Ó': 'O', 'Ô': 'O', 'Õ': 'O', 'Ö': 'O', 'Ø': 'O',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'Ù': 'U', 'Ú': 'U', 'Û': 'U', 'Ü': 'U',
+	'ñ': 'n', 'Ñ': 'N', 'ç': 'c', 'Ç': 'C', 'ý': 'y', 'ÿ': 'y', 'Ý': 'Y',
+}
+
+// FoldDiacritics replaces accented Latin letters with their unaccented form
+func FoldDiacritics(s string) string {
+	return strings.Map(func(r rune) rune {
+		if folded, ok := diacriticFolds[r]; ok {
+			return folded
+		}
+		return r
+	}, s)
+}
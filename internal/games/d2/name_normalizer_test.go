@@ -0,0 +1,70 @@
+package d2
+
+import "testing"
+
+// TestNormalizeItemNameDefaults pins the default normalization that image
+// dedup and name matching rely on
+func TestNormalizeItemNameDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lowercases", "Harlequin Crest", "harlequin crest"},
+		{"trims surrounding whitespace", "  Shako \t\n", "shako"},
+		{"keeps inner whitespace runs", "Tal  Rasha's\tWrappings", "tal  rasha's\twrappings"},
+		{"straightens curly single quotes", "Tal Rasha’s ‘Guardianship’", "tal rasha's 'guardianship'"},
+		{"straightens curly double quotes", "“The” Oculus", "\"the\" oculus"},
+		{"keeps diacritics", "Éclair Ñ", "éclair ñ"},
+		{"keeps punctuation", "Bul-Kathos' Children", "bul-kathos' children"},
+		{"empty", "", ""},
+		{"whitespace only", "   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeItemName(tt.in); got != tt.want {
+				t.Errorf("NormalizeItemName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameNormalizerOptionalRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		in    string
+		want  string
+	}{
+		{"collapse whitespace", []string{"collapse-whitespace"}, " Tal  Rasha's\tWrappings ", "tal rasha's wrappings"},
+		{"fold diacritics", []string{"fold-diacritics"}, "Éclair Ñ", "eclair n"},
+		{"both", []string{"fold-diacritics", "collapse-whitespace"}, "Crème   Brûlée", "creme brulee"},
+		{"none keeps defaults", nil, " Shako ", "shako"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseNormalizeRules(tt.rules)
+			if err != nil {
+				t.Fatalf("ParseNormalizeRules(%v) error: %v", tt.rules, err)
+			}
+			if got := DefaultNameNormalizer.With(rules...).Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameNormalizerWithLeavesReceiverUnchanged(t *testing.T) {
+	base := NewNameNormalizer(LowerTrim)
+	base.With(CollapseWhitespace)
+
+	if got := base.Normalize("A  B"); got != "a  b" {
+		t.Errorf("base normalizer changed after With: got %q", got)
+	}
+}
+
+func TestParseNormalizeRulesRejectsUnknown(t *testing.T) {
+	if _, err := ParseNormalizeRules([]string{"collapse-whitespace", "roman-numerals"}); err == nil {
+		t.Fatal("expected an error for an unknown rule")
+	}
+}
@@ -0,0 +1,115 @@
+package d2
+
+import (
+	"sort"
+	"strings"
+)
+
+// StatRequirement is a minimum value wanted for a stat
+type StatRequirement struct {
+	Code string // Stat code (aliases are resolved)
+	Min  int    // Minimum wanted value
+}
+
+// StatCandidate is an item carrying at least one requested stat, with its
+// properties loaded for scoring
+type StatCandidate struct {
+	SearchResult
+	Properties []Property
+}
+
+// RankedItem is a candidate scored against a set of stat requirements
+type RankedItem struct {
+	SearchResult
+	Met    int            // Number of requirements met
+	Score  float64        // Sum of value/min ratios across requirements
+	Values map[string]int // Best value per requirement code
+}
+
+// optimizeSlotTypes maps gear slots to the item type codes that fill them.
+// Runewords also match on their parent types ("armo", "weap").
+var optimizeSlotTypes = map[string][]string{
+	"helm":   {"helm", "circ", "pelt", "phlm", "armo"},
+	"armor":  {"tors", "armo"},
+	"shield": {"shie", "head", "ashd", "armo"},
+	"gloves": {"glov"},
+	"boots":  {"boot"},
+	"belt":   {"belt"},
+	"weapon": {
+		"weap", "mele", "miss", "swor", "axe", "mace", "pole", "staf", "scep",
+		"wand", "bow", "xbow", "knif", "tkni", "jave", "spea", "h2h", "orb",
+		"amaz", "hamm", "club",
+	},
+}
+
+// OptimizeSlots returns the supported gear slots in a stable order
+func OptimizeSlots() []string {
+	slots := make([]string, 0, len(optimizeSlotTypes))
+	for slot := range optimizeSlotTypes {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+	return slots
+}
+
+// SlotItemTypes returns the item type codes for a gear slot, or nil if the
+// slot is unknown
+func SlotItemTypes(slot string) []string {
+	return optimizeSlotTypes[strings.ToLower(slot)]
+}
+
+// statValue returns the best (max roll) value an item has for a stat code,
+// summing properties that share the code or one of its aliases
+func statValue(props []Property, code string) int {
+	group := StatCodeGroup(code)
+	total := 0
+	for _, p := range props {
+		for _, c := range group {
+			if p.Code == c {
+				total += p.Max
+				break
+			}
+		}
+	}
+	return total
+}
+
+// RankStatCandidates scores candidates against the requirements and sorts
+// them by requirements met, then by magnitude. This is a heuristic ranking
+// for finding gear, not a solver for a full build.
+func RankStatCandidates(candidates []StatCandidate, reqs []StatRequirement) []RankedItem {
+	ranked := make([]RankedItem, 0, len(candidates))
+	for _, cand := range candidates {
+		item := RankedItem{
+			SearchResult: cand.SearchResult,
+			Values:       make(map[string]int, len(reqs)),
+		}
+		for _, req := range reqs {
+			value := statValue(cand.Properties, req.Code)
+			item.Values[req.Code] = value
+			if value <= 0 {
+				continue
+			}
+			if value >= req.Min {
+				item.Met++
+			}
+			min := req.Min
+			if min < 1 {
+				min = 1
+			}
+			item.Score += float64(value) / float64(min)
+		}
+		ranked = append(ranked, item)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Met != ranked[j].Met {
+			return ranked[i].Met > ranked[j].Met
+		}
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	return ranked
+}
@@ -0,0 +1,61 @@
+package d2
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultPlaceholderPatterns match placeholder/unused rows that appear in the
+// source data (e.g. "Expansion" separators, "Not Used" slots). Patterns are
+// case-insensitive regular expressions and are also used by the database
+// cleanup query, so keep them to syntax supported by both Go and PostgreSQL.
+var DefaultPlaceholderPatterns = []string{
+	`^expansion`,
+	`^not used`,
+	`^unused`,
+	`^dummy`,
+	`^placeholder`,
+}
+
+// PlaceholderFilter identifies placeholder item names that should not be imported
+type PlaceholderFilter struct {
+	patterns []string
+	regexes  []*regexp.Regexp
+}
+
+// NewPlaceholderFilter compiles the given patterns into a filter
+func NewPlaceholderFilter(patterns []string) (*PlaceholderFilter, error) {
+	f := &PlaceholderFilter{patterns: patterns}
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder pattern %q: %w", p, err)
+		}
+		f.regexes = append(f.regexes, re)
+	}
+	return f, nil
+}
+
+// DefaultPlaceholderFilter is built from DefaultPlaceholderPatterns
+var DefaultPlaceholderFilter, _ = NewPlaceholderFilter(DefaultPlaceholderPatterns)
+
+// IsPlaceholder reports whether name matches any placeholder pattern
+func (f *PlaceholderFilter) IsPlaceholder(name string) bool {
+	if f == nil {
+		return false
+	}
+	for _, re := range f.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns returns the raw patterns of the filter
+func (f *PlaceholderFilter) Patterns() []string {
+	if f == nil {
+		return nil
+	}
+	return f.patterns
+}
@@ -0,0 +1,69 @@
+package d2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+const (
+	placeholderGrid = 5  // Cells per side of the identicon pattern
+	placeholderCell = 16 // Pixel size of one cell
+	placeholderPad  = 8  // Border around the pattern
+)
+
+// placeholderBackground is the dark tile color behind every placeholder
+var placeholderBackground = color.RGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff}
+
+// GeneratePlaceholderPNG renders an identicon-style PNG for an item without an
+// icon. The pattern and color are seeded from a hash of the item type and name,
+// so the same item always gets the same tile and different items look distinct.
+func GeneratePlaceholderPNG(itemType, name string) ([]byte, error) {
+	seed := sha256.Sum256([]byte(strings.ToLower(itemType) + ":" + strings.ToLower(name)))
+
+	// Keep the foreground bright enough to stand out from the background
+	fg := color.RGBA{
+		R: 0x60 + seed[0]%0xa0,
+		G: 0x60 + seed[1]%0xa0,
+		B: 0x60 + seed[2]%0xa0,
+		A: 0xff,
+	}
+
+	size := placeholderGrid*placeholderCell + 2*placeholderPad
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: placeholderBackground}, image.Point{}, draw.Src)
+
+	// Fill the left half (plus middle column) from the hash bits and mirror it
+	half := (placeholderGrid + 1) / 2
+	bit := 0
+	for col := 0; col < half; col++ {
+		for row := 0; row < placeholderGrid; row++ {
+			on := seed[3+bit/8]&(1<<(bit%8)) != 0
+			bit++
+			if !on {
+				continue
+			}
+			fillPlaceholderCell(img, col, row, fg)
+			fillPlaceholderCell(img, placeholderGrid-1-col, row, fg)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode placeholder png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fillPlaceholderCell paints one grid cell of the identicon
+func fillPlaceholderCell(img *image.RGBA, col, row int, c color.Color) {
+	x := placeholderPad + col*placeholderCell
+	y := placeholderPad + row*placeholderCell
+	rect := image.Rect(x, y, x+placeholderCell, y+placeholderCell)
+	draw.Draw(img, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
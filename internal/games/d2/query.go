@@ -3,24 +3,55 @@ package d2
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // SearchResult represents a unified search result from any item type
 type SearchResult struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`     // "unique", "set", "runeword", "rune", "gem", "base"
-	Category string `json:"category"` // Item category: "helm", "armor", etc.
-	BaseName string `json:"baseName,omitempty"`
-	ImageURL string `json:"imageUrl,omitempty"`
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`     // "unique", "set", "runeword", "rune", "gem", "base"
+	Category  string  `json:"category"` // Item category: "helm", "armor", etc.
+	BaseName  string  `json:"baseName,omitempty"`
+	ImageURL  string  `json:"imageUrl,omitempty"`
+	MatchType string  `json:"matchType"`       // "name", "code", "base", "description", "stat" or "fuzzy"
+	Score     float64 `json:"score,omitempty"` // Relevance tier for name searches, trigram similarity for fuzzy
+}
+
+// Relevance scores for SearchItems, highest first. Results are ordered by
+// score, so exact matches lead and description matches trail.
+const (
+	ScoreExact       = 1.0  // Name or code equals the query
+	ScorePrefix      = 0.75 // Name or code starts with the query
+	ScoreSubstring   = 0.5  // Name contains the query
+	ScoreBaseName    = 0.25 // Only the base name (uniques and sets) contains the query
+	ScoreDescription = 0.1  // Only the description (base and quest items) matches the query
+)
+
+// SearchOptions controls optional search behavior
+type SearchOptions struct {
+	IncludeQuest       bool // Include quest items (excluded by default)
+	IncludeDescription bool // Also full-text match base and quest item descriptions
 }
 
-// SearchItems searches across all item types by name
-func (r *Repository) SearchItems(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+// descriptionMatchSQL full-text matches an item_bases description against
+// the raw query ($2) when $10 is set. Backed by idx_item_bases_description_fts,
+// so the expression must stay identical to the index definition.
+const descriptionMatchSQL = `($10 AND to_tsvector('english', COALESCE(description, '')) @@ plainto_tsquery('english', $2))`
+
+// SearchItems searches across all item types by name. Runes, gems, base and
+// quest items also match by code prefix (e.g. "r30" for Ber), and with
+// IncludeDescription by their description. An item matching several ways is
+// returned once, with the best match type.
+func (r *Repository) SearchItems(ctx context.Context, query string, limit int, opts SearchOptions) ([]SearchResult, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -30,6 +61,7 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 
 	// Prepare the search pattern for ILIKE
 	pattern := "%" + strings.ToLower(query) + "%"
+	codePattern := strings.ToLower(query) + "%"
 
 	// Union query across all item types
 	sql := `
@@ -38,18 +70,19 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				name,
+				NULL as code,
 				'unique' as type,
 				COALESCE(
 					(SELECT it.name
-					 FROM d2.item_types it
-					 JOIN d2.item_bases ib ON ib.item_type = it.code
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
 					 WHERE ib.code = unique_items.base_code LIMIT 1),
 					'Unknown'
 				) as category,
 				base_name,
 				image_url
-			FROM d2.unique_items
-			WHERE enabled = true AND LOWER(name) LIKE $1
+			FROM ` + tableUniqueItems + `
+			WHERE enabled = true AND (LOWER(name) LIKE $1 OR LOWER(base_name) LIKE $1)
 
 			UNION ALL
 
@@ -57,18 +90,19 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				name,
+				NULL as code,
 				'set' as type,
 				COALESCE(
 					(SELECT it.name
-					 FROM d2.item_types it
-					 JOIN d2.item_bases ib ON ib.item_type = it.code
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
 					 WHERE ib.code = set_items.base_code LIMIT 1),
 					'Unknown'
 				) as category,
 				base_name,
 				image_url
-			FROM d2.set_items
-			WHERE LOWER(name) LIKE $1
+			FROM ` + tableSetItems + `
+			WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(base_name) LIKE $1)
 
 			UNION ALL
 
@@ -76,11 +110,12 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				display_name as name,
+				NULL as code,
 				'runeword' as type,
 				'Runeword' as category,
 				NULL as base_name,
 				image_url
-			FROM d2.runewords
+			FROM ` + tableRunewords + `
 			WHERE complete = true AND LOWER(display_name) LIKE $1
 
 			UNION ALL
@@ -89,12 +124,13 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				name,
+				code,
 				'rune' as type,
 				'Rune' as category,
 				NULL as base_name,
 				image_url
-			FROM d2.runes
-			WHERE LOWER(name) LIKE $1
+			FROM ` + tableRunes + `
+			WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $5)
 
 			UNION ALL
 
@@ -102,12 +138,13 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				name,
+				code,
 				'gem' as type,
 				'Gem' as category,
 				NULL as base_name,
 				image_url
-			FROM d2.gems
-			WHERE LOWER(name) LIKE $1
+			FROM ` + tableGems + `
+			WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $5)
 
 			UNION ALL
 
@@ -115,47 +152,61 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 			SELECT
 				id,
 				name,
+				code,
 				'base' as type,
 				COALESCE(
 					(SELECT it.name
-					 FROM d2.item_types it
+					 FROM ` + tableItemTypes + ` it
 					 WHERE it.code = item_bases.item_type LIMIT 1),
 					category
 				) as category,
 				NULL as base_name,
 				image_url
-			FROM d2.item_bases
-			WHERE spawnable = true AND tradable = true AND LOWER(name) LIKE $1
-				AND NOT EXISTS (SELECT 1 FROM d2.gems g WHERE g.code = item_bases.code)
-				AND NOT EXISTS (SELECT 1 FROM d2.runes r WHERE r.code = item_bases.code)
+			FROM ` + tableItemBases + `
+			WHERE spawnable = true AND tradable = true AND quest_item IS NOT TRUE AND placeholder IS NOT TRUE
+				AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $5 OR ` + descriptionMatchSQL + `)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableGems + ` g WHERE g.code = item_bases.code)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableRunes + ` r WHERE r.code = item_bases.code)
 
 			UNION ALL
 
-			-- Quest items
+			-- Quest items (only when requested)
 			SELECT
 				id,
 				name,
+				code,
 				'quest' as type,
 				'Quest' as category,
 				NULL as base_name,
 				image_url
-			FROM d2.item_bases
-			WHERE quest_item = true AND LOWER(name) LIKE $1
+			FROM ` + tableItemBases + `
+			WHERE $4 AND quest_item = true AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $5 OR ` + descriptionMatchSQL + `)
 		)
-		SELECT id, name, type, category, base_name, image_url
-		FROM all_items
-		ORDER BY
-			CASE
-				WHEN LOWER(name) = LOWER($2) THEN 0  -- Exact match first
-				WHEN LOWER(name) LIKE LOWER($2) || '%' THEN 1  -- Starts with
-				ELSE 2
-			END,
-			type,
-			name
+		SELECT id, name, type, category, base_name, image_url, match_type, score
+		FROM (
+			SELECT
+				*,
+				CASE
+					WHEN LOWER(name) LIKE $1 THEN 'name'
+					WHEN LOWER(code) LIKE $5 THEN 'code'
+					WHEN LOWER(base_name) LIKE $1 THEN 'base'
+					ELSE 'description'
+				END as match_type,
+				CASE
+					WHEN LOWER(name) = LOWER($2) OR LOWER(code) = LOWER($2) THEN $6::float8  -- Exact name or code
+					WHEN LOWER(name) LIKE LOWER($2) || '%' OR LOWER(code) LIKE $5 THEN $7::float8  -- Starts with
+					WHEN LOWER(name) LIKE $1 THEN $8::float8  -- Name contains
+					WHEN LOWER(base_name) LIKE $1 THEN $9::float8  -- Base name contains
+					ELSE $11::float8  -- Description matches
+				END as score
+			FROM all_items
+		) ranked
+		ORDER BY score DESC, type, name
 		LIMIT $3
 	`
 
-	rows, err := r.pool.Query(ctx, sql, pattern, query, limit)
+	rows, err := r.db.Query(ctx, sql, pattern, query, limit, opts.IncludeQuest, codePattern,
+		ScoreExact, ScorePrefix, ScoreSubstring, ScoreBaseName, opts.IncludeDescription, ScoreDescription)
 	if err != nil {
 		return nil, fmt.Errorf("search items query failed: %w", err)
 	}
@@ -165,7 +216,7 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 	for rows.Next() {
 		var sr SearchResult
 		var baseName, imageURL *string
-		err := rows.Scan(&sr.ID, &sr.Name, &sr.Type, &sr.Category, &baseName, &imageURL)
+		err := rows.Scan(&sr.ID, &sr.Name, &sr.Type, &sr.Category, &baseName, &imageURL, &sr.MatchType, &sr.Score)
 		if err != nil {
 			return nil, fmt.Errorf("scan search result failed: %w", err)
 		}
@@ -181,30 +232,166 @@ func (r *Repository) SearchItems(ctx context.Context, query string, limit int) (
 	return results, rows.Err()
 }
 
-// GetUniqueItem retrieves a unique item by ID with all its properties
-func (r *Repository) GetUniqueItem(ctx context.Context, id int) (*UniqueItem, error) {
+// fuzzyMinSimilarity is the lowest trigram similarity a fuzzy match may have
+const fuzzyMinSimilarity = 0.3
+
+// undefinedFunctionCode is the Postgres error for a missing function or
+// operator, raised when the pg_trgm extension isn't installed
+const undefinedFunctionCode = "42883"
+
+// SearchItemsFuzzy searches item names by trigram similarity, for queries
+// with typos ("enigam" -> Enigma). Requires the pg_trgm extension; when it's
+// missing no results are returned rather than an error. Each source table is
+// filtered with the % operator, which the LOWER(name) trigram indexes serve,
+// so only candidates above the threshold are ranked.
+func (r *Repository) SearchItemsFuzzy(ctx context.Context, query string, limit int, opts SearchOptions) ([]SearchResult, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
 	sql := `
-		SELECT
+		WITH all_items AS (
+			SELECT id, name, 'unique' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
+					 WHERE ib.code = unique_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url
+			FROM ` + tableUniqueItems + `
+			WHERE enabled = true AND LOWER(name) % LOWER($1)
+
+			UNION ALL
+
+			SELECT id, name, 'set' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
+					 WHERE ib.code = set_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url
+			FROM ` + tableSetItems + `
+			WHERE enabled IS NOT FALSE AND LOWER(name) % LOWER($1)
+
+			UNION ALL
+
+			SELECT id, display_name as name, 'runeword' as type, 'Runeword' as category,
+				NULL as base_name, image_url
+			FROM ` + tableRunewords + `
+			WHERE complete = true AND LOWER(display_name) % LOWER($1)
+
+			UNION ALL
+
+			SELECT id, name, 'rune' as type, 'Rune' as category, NULL as base_name, image_url
+			FROM ` + tableRunes + `
+			WHERE enabled IS NOT FALSE AND LOWER(name) % LOWER($1)
+
+			UNION ALL
+
+			SELECT id, name, 'gem' as type, 'Gem' as category, NULL as base_name, image_url
+			FROM ` + tableGems + `
+			WHERE enabled IS NOT FALSE AND LOWER(name) % LOWER($1)
+
+			UNION ALL
+
+			SELECT id, name, 'base' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM ` + tableItemTypes + ` it
+					 WHERE it.code = item_bases.item_type LIMIT 1),
+					category
+				) as category,
+				NULL as base_name, image_url
+			FROM ` + tableItemBases + `
+			WHERE spawnable = true AND tradable = true AND quest_item IS NOT TRUE AND placeholder IS NOT TRUE
+				AND LOWER(name) % LOWER($1)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableGems + ` g WHERE g.code = item_bases.code)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableRunes + ` r WHERE r.code = item_bases.code)
+
+			UNION ALL
+
+			SELECT id, name, 'quest' as type, 'Quest' as category, NULL as base_name, image_url
+			FROM ` + tableItemBases + `
+			WHERE $3 AND quest_item = true AND LOWER(name) % LOWER($1)
+		)
+		SELECT id, name, type, category, base_name, image_url,
+			similarity(LOWER(name), LOWER($1)) as score
+		FROM all_items
+		ORDER BY score DESC, type, name
+		LIMIT $2
+	`
+
+	var results []SearchResult
+	err := r.InTx(ctx, func(tx *Repository) error {
+		// % matches at pg_trgm.similarity_threshold; setting it locally keeps
+		// it from leaking to other queries on the pooled connection
+		threshold := strconv.FormatFloat(fuzzyMinSimilarity, 'f', -1, 64)
+		if _, err := tx.db.Exec(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, threshold); err != nil {
+			return err
+		}
+
+		rows, err := tx.db.Query(ctx, sql, query, limit, opts.IncludeQuest)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			sr := SearchResult{MatchType: "fuzzy"}
+			var baseName, imageURL *string
+			var score float32
+			if err := rows.Scan(&sr.ID, &sr.Name, &sr.Type, &sr.Category, &baseName, &imageURL, &score); err != nil {
+				return fmt.Errorf("scan fuzzy search result failed: %w", err)
+			}
+			if baseName != nil {
+				sr.BaseName = *baseName
+			}
+			if imageURL != nil {
+				sr.ImageURL = *imageURL
+			}
+			sr.Score = math.Round(float64(score)*1000) / 1000
+			results = append(results, sr)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == undefinedFunctionCode {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fuzzy search query failed: %w", err)
+	}
+	return results, nil
+}
+
+// uniqueItemColumns is the column list scanned by scanUniqueItem
+const uniqueItemColumns = `
 			id, index_id, name, base_code, base_name, level, level_req, rarity,
 			enabled, ladder_only, first_ladder_season, last_ladder_season,
 			properties, inv_transform, chr_transform, inv_file, image_url,
-			cost_mult, cost_add, created_at, updated_at
-		FROM d2.unique_items
-		WHERE id = $1
-	`
+			cost_mult, cost_add, created_at, updated_at`
 
+// scanUniqueItem scans one row selected with uniqueItemColumns
+func scanUniqueItem(row pgx.Row) (*UniqueItem, error) {
 	var ui UniqueItem
 	var baseName, invTransform, chrTransform, invFile, imageURL *string
 	var propsJSON []byte
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
+	err := row.Scan(
 		&ui.ID, &ui.IndexID, &ui.Name, &ui.BaseCode, &baseName, &ui.Level, &ui.LevelReq, &ui.Rarity,
 		&ui.Enabled, &ui.LadderOnly, &ui.FirstLadderSeason, &ui.LastLadderSeason,
 		&propsJSON, &invTransform, &chrTransform, &invFile, &imageURL,
 		&ui.CostMult, &ui.CostAdd, &ui.CreatedAt, &ui.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get unique item failed: %w", err)
+		return nil, err
 	}
 
 	if baseName != nil {
@@ -232,41 +419,49 @@ func (r *Repository) GetUniqueItem(ctx context.Context, id int) (*UniqueItem, er
 	return &ui, nil
 }
 
+// GetUniqueItem retrieves a unique item by ID with all its properties
+func (r *Repository) GetUniqueItem(ctx context.Context, id int) (*UniqueItem, error) {
+	sql := `SELECT ` + uniqueItemColumns + ` FROM ` + tableUniqueItems + ` WHERE id = $1`
+
+	ui, err := scanUniqueItem(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get unique item failed: %w", err)
+	}
+	return ui, nil
+}
+
 // GetUniqueItemByName retrieves a unique item by name
 func (r *Repository) GetUniqueItemByName(ctx context.Context, name string) (*UniqueItem, error) {
 	sql := `
-		SELECT id FROM d2.unique_items WHERE LOWER(name) = LOWER($1) AND enabled = true LIMIT 1
+		SELECT id FROM ` + tableUniqueItems + ` WHERE LOWER(name) = LOWER($1) AND enabled = true LIMIT 1
 	`
 	var id int
-	err := r.pool.QueryRow(ctx, sql, name).Scan(&id)
+	err := r.db.QueryRow(ctx, sql, name).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 	return r.GetUniqueItem(ctx, id)
 }
 
-// GetSetItem retrieves a set item by ID with all its properties
-func (r *Repository) GetSetItem(ctx context.Context, id int) (*SetItem, error) {
-	sql := `
-		SELECT
+// setItemColumns is the column list scanned by scanSetItem
+const setItemColumns = `
 			id, index_id, name, set_name, base_code, base_name, level, level_req, rarity,
 			properties, bonus_properties, inv_transform, chr_transform, inv_file, image_url,
-			cost_mult, cost_add, created_at, updated_at
-		FROM d2.set_items
-		WHERE id = $1
-	`
+			cost_mult, cost_add, created_at, updated_at`
 
+// scanSetItem scans one row selected with setItemColumns
+func scanSetItem(row pgx.Row) (*SetItem, error) {
 	var si SetItem
 	var baseName, invTransform, chrTransform, invFile, imageURL *string
 	var propsJSON, bonusPropsJSON []byte
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
+	err := row.Scan(
 		&si.ID, &si.IndexID, &si.Name, &si.SetName, &si.BaseCode, &baseName, &si.Level, &si.LevelReq, &si.Rarity,
 		&propsJSON, &bonusPropsJSON, &invTransform, &chrTransform, &invFile, &imageURL,
 		&si.CostMult, &si.CostAdd, &si.CreatedAt, &si.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get set item failed: %w", err)
+		return nil, err
 	}
 
 	if baseName != nil {
@@ -299,28 +494,155 @@ func (r *Repository) GetSetItem(ctx context.Context, id int) (*SetItem, error) {
 	return &si, nil
 }
 
-// GetRuneword retrieves a runeword by ID with all its properties
-func (r *Repository) GetRuneword(ctx context.Context, id int) (*Runeword, error) {
+// GetSetItem retrieves a set item by ID with all its properties
+func (r *Repository) GetSetItem(ctx context.Context, id int) (*SetItem, error) {
+	sql := `SELECT ` + setItemColumns + ` FROM ` + tableSetItems + ` WHERE id = $1`
+
+	si, err := scanSetItem(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get set item failed: %w", err)
+	}
+	return si, nil
+}
+
+// GetSetBonusByName retrieves a set definition (partial and full bonuses) by set name
+func (r *Repository) GetSetBonusByName(ctx context.Context, name string) (*SetBonus, error) {
 	sql := `
-		SELECT
+		SELECT id, index_id, name, version, partial_bonuses, full_bonuses, created_at, updated_at
+		FROM ` + tableSetBonuses + `
+		WHERE LOWER(name) = LOWER($1)
+	`
+
+	var sb SetBonus
+	var partialJSON, fullJSON []byte
+
+	err := r.db.QueryRow(ctx, sql, name).Scan(
+		&sb.ID, &sb.IndexID, &sb.Name, &sb.Version, &partialJSON, &fullJSON, &sb.CreatedAt, &sb.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get set bonus failed: %w", err)
+	}
+
+	if len(partialJSON) > 0 {
+		if err := json.Unmarshal(partialJSON, &sb.PartialBonuses); err != nil {
+			return nil, fmt.Errorf("unmarshal partial bonuses failed: %w", err)
+		}
+	}
+	if len(fullJSON) > 0 {
+		if err := json.Unmarshal(fullJSON, &sb.FullBonuses); err != nil {
+			return nil, fmt.Errorf("unmarshal full bonuses failed: %w", err)
+		}
+	}
+
+	return &sb, nil
+}
+
+// GetAllSetBonuses retrieves every set definition ordered by name
+func (r *Repository) GetAllSetBonuses(ctx context.Context) ([]SetBonus, error) {
+	rows, err := r.db.Query(ctx, `SELECT name FROM `+tableSetBonuses+` ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	bonuses := make([]SetBonus, 0, len(names))
+	for _, name := range names {
+		sb, err := r.GetSetBonusByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		bonuses = append(bonuses, *sb)
+	}
+	return bonuses, nil
+}
+
+// GetSetItemNames returns the names of enabled set items keyed by lowercased
+// set name, each list ordered by item name
+func (r *Repository) GetSetItemNames(ctx context.Context) (map[string][]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT set_name, name FROM `+tableSetItems+`
+		WHERE enabled IS NOT FALSE
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var setName, name string
+		if err := rows.Scan(&setName, &name); err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(setName)
+		result[key] = append(result[key], name)
+	}
+	return result, rows.Err()
+}
+
+// GetSetItemsBySetName retrieves all items belonging to a set
+func (r *Repository) GetSetItemsBySetName(ctx context.Context, setName string) ([]SetItem, error) {
+	sql := `SELECT id FROM ` + tableSetItems + ` WHERE enabled IS NOT FALSE AND LOWER(set_name) = LOWER($1) ORDER BY name`
+	rows, err := r.db.Query(ctx, sql, setName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]SetItem, 0, len(ids))
+	for _, id := range ids {
+		item, err := r.GetSetItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// runewordColumns is the column list scanned by scanRuneword
+const runewordColumns = `
 			id, name, display_name, complete, ladder_only, first_ladder_season, last_ladder_season,
 			valid_item_types, excluded_item_types, runes, properties, image_url,
-			created_at, updated_at
-		FROM d2.runewords
-		WHERE id = $1
-	`
+			created_at, updated_at`
 
+// scanRuneword scans one row selected with runewordColumns
+func scanRuneword(row pgx.Row) (*Runeword, error) {
 	var rw Runeword
 	var imageURL *string
 	var validTypesJSON, excludedTypesJSON, runesJSON, propsJSON []byte
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
+	err := row.Scan(
 		&rw.ID, &rw.Name, &rw.DisplayName, &rw.Complete, &rw.LadderOnly, &rw.FirstLadderSeason, &rw.LastLadderSeason,
 		&validTypesJSON, &excludedTypesJSON, &runesJSON, &propsJSON, &imageURL,
 		&rw.CreatedAt, &rw.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get runeword failed: %w", err)
+		return nil, err
 	}
 
 	if imageURL != nil {
@@ -351,41 +673,49 @@ func (r *Repository) GetRuneword(ctx context.Context, id int) (*Runeword, error)
 	return &rw, nil
 }
 
+// GetRuneword retrieves a runeword by ID with all its properties
+func (r *Repository) GetRuneword(ctx context.Context, id int) (*Runeword, error) {
+	sql := `SELECT ` + runewordColumns + ` FROM ` + tableRunewords + ` WHERE id = $1`
+
+	rw, err := scanRuneword(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get runeword failed: %w", err)
+	}
+	return rw, nil
+}
+
 // GetRunewordByName retrieves a runeword by name
 func (r *Repository) GetRunewordByName(ctx context.Context, name string) (*Runeword, error) {
 	sql := `
-		SELECT id FROM d2.runewords WHERE LOWER(display_name) = LOWER($1) AND complete = true LIMIT 1
+		SELECT id FROM ` + tableRunewords + ` WHERE LOWER(display_name) = LOWER($1) AND complete = true LIMIT 1
 	`
 	var id int
-	err := r.pool.QueryRow(ctx, sql, name).Scan(&id)
+	err := r.db.QueryRow(ctx, sql, name).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 	return r.GetRuneword(ctx, id)
 }
 
-// GetRune retrieves a rune by ID
-func (r *Repository) GetRune(ctx context.Context, id int) (*Rune, error) {
-	sql := `
-		SELECT
+// runeColumns is the column list scanned by scanRune
+const runeColumns = `
 			id, code, name, rune_number, level, level_req,
 			weapon_mods, helm_mods, shield_mods,
-			inv_file, image_url, cost, created_at, updated_at
-		FROM d2.runes
-		WHERE id = $1
-	`
+			inv_file, image_url, cost, created_at, updated_at`
 
+// scanRune scans one row selected with runeColumns
+func scanRune(row pgx.Row) (*Rune, error) {
 	var rn Rune
 	var invFile, imageURL *string
 	var weaponJSON, helmJSON, shieldJSON []byte
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
+	err := row.Scan(
 		&rn.ID, &rn.Code, &rn.Name, &rn.RuneNumber, &rn.Level, &rn.LevelReq,
 		&weaponJSON, &helmJSON, &shieldJSON,
 		&invFile, &imageURL, &rn.Cost, &rn.CreatedAt, &rn.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get rune failed: %w", err)
+		return nil, err
 	}
 
 	if invFile != nil {
@@ -414,39 +744,78 @@ func (r *Repository) GetRune(ctx context.Context, id int) (*Rune, error) {
 	return &rn, nil
 }
 
+// GetRune retrieves a rune by ID
+func (r *Repository) GetRune(ctx context.Context, id int) (*Rune, error) {
+	sql := `SELECT ` + runeColumns + ` FROM ` + tableRunes + ` WHERE id = $1`
+
+	rn, err := scanRune(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get rune failed: %w", err)
+	}
+	return rn, nil
+}
+
 // GetRuneByName retrieves a rune by name (e.g., "Ber")
 func (r *Repository) GetRuneByName(ctx context.Context, name string) (*Rune, error) {
-	sql := `SELECT id FROM d2.runes WHERE LOWER(name) = LOWER($1) LIMIT 1`
+	sql := `SELECT id FROM ` + tableRunes + ` WHERE LOWER(name) = LOWER($1) LIMIT 1`
 	var id int
-	err := r.pool.QueryRow(ctx, sql, name).Scan(&id)
+	err := r.db.QueryRow(ctx, sql, name).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 	return r.GetRune(ctx, id)
 }
 
-// GetGem retrieves a gem by ID
-func (r *Repository) GetGem(ctx context.Context, id int) (*Gem, error) {
-	sql := `
-		SELECT
-			id, code, name, gem_type, quality,
+// GetRuneByCode retrieves a rune by code (e.g., "r30")
+func (r *Repository) GetRuneByCode(ctx context.Context, code string) (*Rune, error) {
+	sql := `SELECT id FROM ` + tableRunes + ` WHERE LOWER(code) = LOWER($1) LIMIT 1`
+	var id int
+	err := r.db.QueryRow(ctx, sql, code).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetRune(ctx, id)
+}
+
+// GetRunewordsContainingRune retrieves all complete runewords that use the given rune code
+func (r *Repository) GetRunewordsContainingRune(ctx context.Context, runeCode string) ([]Runeword, error) {
+	sql := `SELECT ` + runewordColumns + ` FROM ` + tableRunewords + ` WHERE complete = true AND runes ? $1 ORDER BY display_name`
+	rows, err := r.db.Query(ctx, sql, runeCode)
+	if err != nil {
+		return nil, fmt.Errorf("get runewords containing rune failed: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Runeword, 0)
+	for rows.Next() {
+		item, err := scanRuneword(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan runeword failed: %w", err)
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// gemColumns is the column list scanned by scanGem
+const gemColumns = `
+			id, code, name, gem_type, quality, COALESCE(is_skull, false),
 			weapon_mods, helm_mods, shield_mods,
-			transform, inv_file, image_url, created_at, updated_at
-		FROM d2.gems
-		WHERE id = $1
-	`
+			transform, inv_file, image_url, created_at, updated_at`
 
+// scanGem scans one row selected with gemColumns
+func scanGem(row pgx.Row) (*Gem, error) {
 	var g Gem
 	var invFile, imageURL *string
 	var weaponJSON, helmJSON, shieldJSON []byte
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
-		&g.ID, &g.Code, &g.Name, &g.GemType, &g.Quality,
+	err := row.Scan(
+		&g.ID, &g.Code, &g.Name, &g.GemType, &g.Quality, &g.IsSkull,
 		&weaponJSON, &helmJSON, &shieldJSON,
 		&g.Transform, &invFile, &imageURL, &g.CreatedAt, &g.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get gem failed: %w", err)
+		return nil, err
 	}
 
 	if invFile != nil {
@@ -475,10 +844,30 @@ func (r *Repository) GetGem(ctx context.Context, id int) (*Gem, error) {
 	return &g, nil
 }
 
+// GetGem retrieves a gem by ID
+func (r *Repository) GetGem(ctx context.Context, id int) (*Gem, error) {
+	sql := `SELECT ` + gemColumns + ` FROM ` + tableGems + ` WHERE id = $1`
+
+	g, err := scanGem(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get gem failed: %w", err)
+	}
+	return g, nil
+}
+
 // GetItemBase retrieves a base item by ID
 func (r *Repository) GetItemBase(ctx context.Context, id int) (*ItemBase, error) {
-	sql := `
-		SELECT
+	sql := `SELECT ` + itemBaseColumns + ` FROM ` + tableItemBases + ` WHERE id = $1`
+
+	ib, err := scanItemBase(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		return nil, fmt.Errorf("get item base failed: %w", err)
+	}
+	return ib, nil
+}
+
+// itemBaseColumns is the column list scanned by scanItemBase
+const itemBaseColumns = `
 			id, code, name, item_type, item_type2, category,
 			COALESCE(tier, 'Normal'), COALESCE(type_tags, '{}'), class_specific, COALESCE(tradable, true),
 			level, level_req, str_req, dex_req, durability,
@@ -488,16 +877,15 @@ func (r *Repository) GetItemBase(ctx context.Context, id int) (*ItemBase, error)
 			normal_code, exceptional_code, elite_code,
 			inv_width, inv_height, inv_file, flippy_file, unique_inv_file, set_inv_file,
 			image_url, icon_variants, spawnable, stackable, useable, throwable, quest_item,
-			rarity, cost, description, created_at, updated_at
-		FROM d2.item_bases
-		WHERE id = $1
-	`
+			COALESCE(placeholder, false), rarity, cost, description, created_at, updated_at`
 
+// scanItemBase scans one row selected with itemBaseColumns
+func scanItemBase(row pgx.Row) (*ItemBase, error) {
 	var ib ItemBase
 	var itemType2, normalCode, exceptionalCode, eliteCode *string
 	var invFile, flippyFile, uniqueInvFile, setInvFile, imageURL, description, classSpecific *string
 
-	err := r.pool.QueryRow(ctx, sql, id).Scan(
+	err := row.Scan(
 		&ib.ID, &ib.Code, &ib.Name, &ib.ItemType, &itemType2, &ib.Category,
 		&ib.Tier, &ib.TypeTags, &classSpecific, &ib.Tradable,
 		&ib.Level, &ib.LevelReq, &ib.StrReq, &ib.DexReq, &ib.Durability,
@@ -507,10 +895,10 @@ func (r *Repository) GetItemBase(ctx context.Context, id int) (*ItemBase, error)
 		&normalCode, &exceptionalCode, &eliteCode,
 		&ib.InvWidth, &ib.InvHeight, &invFile, &flippyFile, &uniqueInvFile, &setInvFile,
 		&imageURL, &ib.IconVariants, &ib.Spawnable, &ib.Stackable, &ib.Useable, &ib.Throwable, &ib.QuestItem,
-		&ib.Rarity, &ib.Cost, &description, &ib.CreatedAt, &ib.UpdatedAt,
+		&ib.Placeholder, &ib.Rarity, &ib.Cost, &description, &ib.CreatedAt, &ib.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get item base failed: %w", err)
+		return nil, err
 	}
 
 	if itemType2 != nil {
@@ -550,38 +938,63 @@ func (r *Repository) GetItemBase(ctx context.Context, id int) (*ItemBase, error)
 	return &ib, nil
 }
 
+// GetItemBasesByCodes retrieves base items for the given codes in one query,
+// keyed by code. Codes with no matching base are omitted from the map.
+func (r *Repository) GetItemBasesByCodes(ctx context.Context, codes []string) (map[string]*ItemBase, error) {
+	result := make(map[string]*ItemBase)
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	sql := `SELECT ` + itemBaseColumns + ` FROM ` + tableItemBases + ` WHERE code = ANY($1) ORDER BY id`
+	rows, err := r.db.Query(ctx, sql, codes)
+	if err != nil {
+		return nil, fmt.Errorf("get item bases by codes failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ib, err := scanItemBase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan item base failed: %w", err)
+		}
+		// Match GetItemBaseByCode: the first row for a code wins
+		if _, ok := result[ib.Code]; !ok {
+			result[ib.Code] = ib
+		}
+	}
+	return result, rows.Err()
+}
+
 // GetItemBaseByCode retrieves a base item by code
 func (r *Repository) GetItemBaseByCode(ctx context.Context, code string) (*ItemBase, error) {
-	sql := `SELECT id FROM d2.item_bases WHERE code = $1 LIMIT 1`
+	sql := `SELECT id FROM ` + tableItemBases + ` WHERE code = $1 LIMIT 1`
 	var id int
-	err := r.pool.QueryRow(ctx, sql, code).Scan(&id)
+	err := r.db.QueryRow(ctx, sql, code).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 	return r.GetItemBase(ctx, id)
 }
 
-// GetItemType retrieves an item type by code
-func (r *Repository) GetItemType(ctx context.Context, code string) (*ItemType, error) {
-	sql := `
-		SELECT
+// itemTypeColumns is the column list scanned by scanItemType
+const itemTypeColumns = `
 			id, code, name, equiv1, equiv2, body_loc1, body_loc2,
 			can_be_magic, can_be_rare, max_sockets_normal, max_sockets_nightmare, max_sockets_hell,
-			staff_mods, class_restriction, store_page, created_at, updated_at
-		FROM d2.item_types
-		WHERE code = $1
-	`
+			staff_mods, class_restriction, store_page, created_at, updated_at`
 
+// scanItemType scans one row selected with itemTypeColumns
+func scanItemType(row pgx.Row) (*ItemType, error) {
 	var it ItemType
 	var equiv1, equiv2, bodyLoc1, bodyLoc2, staffMods, classRestriction, storePage *string
 
-	err := r.pool.QueryRow(ctx, sql, code).Scan(
+	err := row.Scan(
 		&it.ID, &it.Code, &it.Name, &equiv1, &equiv2, &bodyLoc1, &bodyLoc2,
 		&it.CanBeMagic, &it.CanBeRare, &it.MaxSocketsNormal, &it.MaxSocketsNightmare, &it.MaxSocketsHell,
 		&staffMods, &classRestriction, &storePage, &it.CreatedAt, &it.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get item type failed: %w", err)
+		return nil, err
 	}
 
 	if equiv1 != nil {
@@ -609,10 +1022,127 @@ func (r *Repository) GetItemType(ctx context.Context, code string) (*ItemType, e
 	return &it, nil
 }
 
+// GetItemType retrieves an item type by code
+func (r *Repository) GetItemType(ctx context.Context, code string) (*ItemType, error) {
+	sql := `SELECT ` + itemTypeColumns + ` FROM ` + tableItemTypes + ` WHERE code = $1`
+
+	it, err := scanItemType(r.db.QueryRow(ctx, sql, code))
+	if err != nil {
+		return nil, fmt.Errorf("get item type failed: %w", err)
+	}
+	return it, nil
+}
+
+// GetRuneUpgradePath returns the rune with the given ID followed by every
+// higher rune up to Zod, ordered by rune number
+func (r *Repository) GetRuneUpgradePath(ctx context.Context, id int) ([]Rune, error) {
+	sql := `
+		SELECT id FROM ` + tableRunes + `
+		WHERE enabled IS NOT FALSE
+			AND rune_number >= (SELECT rune_number FROM ` + tableRunes + ` WHERE id = $1)
+		ORDER BY rune_number
+	`
+	rows, err := r.db.Query(ctx, sql, id)
+	if err != nil {
+		return nil, fmt.Errorf("get rune upgrade path failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var runeID int
+		if err := rows.Scan(&runeID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, runeID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	path := make([]Rune, 0, len(ids))
+	for _, runeID := range ids {
+		rn, err := r.GetRune(ctx, runeID)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, *rn)
+	}
+	return path, nil
+}
+
+// GetGemProgression returns every quality of a gem type ordered from
+// chipped to perfect (GemQualities order)
+func (r *Repository) GetGemProgression(ctx context.Context, gemType string) ([]Gem, error) {
+	sql := `
+		SELECT id FROM ` + tableGems + `
+		WHERE enabled IS NOT FALSE AND gem_type = $1
+		ORDER BY COALESCE(array_position($2::text[], quality), 0), id
+	`
+	rows, err := r.db.Query(ctx, sql, gemType, GemQualities)
+	if err != nil {
+		return nil, fmt.Errorf("get gem progression failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	gems := make([]Gem, 0, len(ids))
+	for _, id := range ids {
+		g, err := r.GetGem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		gems = append(gems, *g)
+	}
+	return gems, nil
+}
+
+// GetAllItemTypes retrieves all item types ordered by code
+func (r *Repository) GetAllItemTypes(ctx context.Context) ([]ItemType, error) {
+	rows, err := r.db.Query(ctx, `SELECT code FROM `+tableItemTypes+` ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	types := make([]ItemType, 0, len(codes))
+	for _, code := range codes {
+		it, err := r.GetItemType(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, *it)
+	}
+	return types, nil
+}
+
 // GetAllRunes retrieves all runes ordered by rune number
 func (r *Repository) GetAllRunes(ctx context.Context) ([]Rune, error) {
-	sql := `SELECT id FROM d2.runes ORDER BY rune_number`
-	rows, err := r.pool.Query(ctx, sql)
+	sql := `SELECT id FROM ` + tableRunes + ` WHERE enabled IS NOT FALSE ORDER BY rune_number`
+	rows, err := r.db.Query(ctx, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -633,11 +1163,14 @@ func (r *Repository) GetAllRunes(ctx context.Context) ([]Rune, error) {
 	return runes, rows.Err()
 }
 
-// GetAllGems retrieves all gems ordered by quality and type
+// GetAllGems retrieves all gems ordered by quality and type, with skulls
+// listed after the gems
 func (r *Repository) GetAllGems(ctx context.Context) ([]Gem, error) {
 	sql := `
-		SELECT id FROM d2.gems
+		SELECT id FROM ` + tableGems + `
+		WHERE enabled IS NOT FALSE
 		ORDER BY
+			COALESCE(is_skull, false),
 			CASE quality
 				WHEN 'perfect' THEN 1
 				WHEN 'flawless' THEN 2
@@ -648,7 +1181,7 @@ func (r *Repository) GetAllGems(ctx context.Context) ([]Gem, error) {
 			END,
 			gem_type
 	`
-	rows, err := r.pool.Query(ctx, sql)
+	rows, err := r.db.Query(ctx, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -669,16 +1202,46 @@ func (r *Repository) GetAllGems(ctx context.Context) ([]Gem, error) {
 	return gems, rows.Err()
 }
 
-// GetAllItemBases retrieves all base items with optional category filter
-func (r *Repository) GetAllItemBases(ctx context.Context, category string) ([]ItemBase, error) {
-	var rows pgx.Rows
-	var err error
+// SocketRange is an inclusive range of maximum socket counts
+type SocketRange struct {
+	Min int
+	Max int
+}
+
+// ItemBaseListOptions filters GetAllItemBases. The zero value lists every
+// spawnable non-quest base.
+type ItemBaseListOptions struct {
+	Category     string       // "armor", "weapon" or "misc"; empty for all
+	IncludeQuest bool         // Include quest items
+	Sockets      *SocketRange // Only bases whose max_sockets falls in the range
+	UsableBy     string       // Only bases this class can use: its own class-specific ones plus unrestricted
 
-	if category != "" {
-		rows, err = r.pool.Query(ctx, `SELECT id FROM d2.item_bases WHERE spawnable = true AND category = $1 ORDER BY name`, category)
-	} else {
-		rows, err = r.pool.Query(ctx, `SELECT id FROM d2.item_bases WHERE spawnable = true ORDER BY category, name`)
+	IncludePlaceholders bool // Include placeholder rows kept by an import
+}
+
+// GetAllItemBases retrieves all base items matching the options, ordered by
+// category and name.
+func (r *Repository) GetAllItemBases(ctx context.Context, opts ItemBaseListOptions) ([]ItemBase, error) {
+	args := []interface{}{opts.IncludeQuest}
+	sql := `SELECT id FROM ` + tableItemBases + ` WHERE spawnable = true AND ($1 OR quest_item IS NOT TRUE)`
+	if !opts.IncludePlaceholders {
+		sql += " AND placeholder IS NOT TRUE"
+	}
+	if opts.Category != "" {
+		args = append(args, opts.Category)
+		sql += fmt.Sprintf(" AND category = $%d", len(args))
+	}
+	if opts.Sockets != nil {
+		args = append(args, opts.Sockets.Min, opts.Sockets.Max)
+		sql += fmt.Sprintf(" AND COALESCE(max_sockets, 0) BETWEEN $%d AND $%d", len(args)-1, len(args))
+	}
+	if opts.UsableBy != "" {
+		args = append(args, opts.UsableBy)
+		sql += fmt.Sprintf(" AND COALESCE(class_specific, '') IN ('', $%d)", len(args))
 	}
+	sql += " ORDER BY category, name"
+
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -699,10 +1262,55 @@ func (r *Repository) GetAllItemBases(ctx context.Context, category string) ([]It
 	return items, rows.Err()
 }
 
-// GetAllUniqueItems retrieves all unique items
-func (r *Repository) GetAllUniqueItems(ctx context.Context) ([]UniqueItem, error) {
-	sql := `SELECT id FROM d2.unique_items WHERE enabled = true ORDER BY name`
-	rows, err := r.pool.Query(ctx, sql)
+// LadderFilter narrows list queries by ladder availability. The zero value
+// matches everything.
+type LadderFilter struct {
+	Ladder *bool // true = ladder-only items, false = non-ladder items, nil = both
+	Season *int  // Only items available in this ladder season
+}
+
+// where returns SQL conditions (each prefixed with " AND ") for the filter,
+// numbering placeholders after the existing args, and the extended args
+func (f LadderFilter) where(args []interface{}) (string, []interface{}) {
+	var sql strings.Builder
+	if f.Ladder != nil {
+		args = append(args, *f.Ladder)
+		fmt.Fprintf(&sql, " AND COALESCE(ladder_only, false) = $%d", len(args))
+	}
+	if f.Season != nil {
+		// Items without a first season predate ladder tracking and are
+		// available in every season
+		args = append(args, *f.Season)
+		n := len(args)
+		fmt.Fprintf(&sql, " AND (first_ladder_season IS NULL OR first_ladder_season <= $%d)"+
+			" AND (last_ladder_season IS NULL OR last_ladder_season >= $%d)", n, n)
+	}
+	return sql.String(), args
+}
+
+// UniqueListOptions filters the unique item list
+type UniqueListOptions struct {
+	Ladder          LadderFilter
+	IncludeDisabled bool   // Include disabled uniques (quest bases); admin only
+	UsableBy        string // Only uniques on a base this class can use (see ItemBaseListOptions)
+}
+
+// GetAllUniqueItems retrieves all unique items matching the options
+func (r *Repository) GetAllUniqueItems(ctx context.Context, opts UniqueListOptions) ([]UniqueItem, error) {
+	cond, args := opts.Ladder.where(nil)
+	enabled := "enabled = true"
+	if opts.IncludeDisabled {
+		enabled = "TRUE"
+	}
+	if opts.UsableBy != "" {
+		// Uniques whose base is missing from item_bases stay listed
+		args = append(args, opts.UsableBy)
+		cond += fmt.Sprintf(` AND NOT EXISTS (
+			SELECT 1 FROM `+tableItemBases+` ib
+			WHERE ib.code = unique_items.base_code AND COALESCE(ib.class_specific, '') NOT IN ('', $%d))`, len(args))
+	}
+	sql := `SELECT id FROM ` + tableUniqueItems + ` WHERE ` + enabled + cond + ` ORDER BY name`
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -723,10 +1331,52 @@ func (r *Repository) GetAllUniqueItems(ctx context.Context) ([]UniqueItem, error
 	return items, rows.Err()
 }
 
+// StreamUniqueItems calls fn with each enabled unique item, ordered by name,
+// as rows are read from a single query
+func (r *Repository) StreamUniqueItems(ctx context.Context, fn func(*UniqueItem) error) error {
+	rows, err := r.db.Query(ctx, `SELECT `+uniqueItemColumns+` FROM `+tableUniqueItems+` WHERE enabled = true ORDER BY name`)
+	if err != nil {
+		return fmt.Errorf("stream unique items failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanUniqueItem(rows)
+		if err != nil {
+			return fmt.Errorf("stream unique items failed: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// StreamSetItems calls fn with each enabled set item, ordered by set and
+// name, as rows are read from a single query
+func (r *Repository) StreamSetItems(ctx context.Context, fn func(*SetItem) error) error {
+	rows, err := r.db.Query(ctx, `SELECT `+setItemColumns+` FROM `+tableSetItems+` WHERE enabled IS NOT FALSE ORDER BY set_name, name`)
+	if err != nil {
+		return fmt.Errorf("stream set items failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSetItem(rows)
+		if err != nil {
+			return fmt.Errorf("stream set items failed: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // GetAllSetItems retrieves all set items
 func (r *Repository) GetAllSetItems(ctx context.Context) ([]SetItem, error) {
-	sql := `SELECT id FROM d2.set_items ORDER BY set_name, name`
-	rows, err := r.pool.Query(ctx, sql)
+	sql := `SELECT id FROM ` + tableSetItems + ` WHERE enabled IS NOT FALSE ORDER BY set_name, name`
+	rows, err := r.db.Query(ctx, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -747,10 +1397,76 @@ func (r *Repository) GetAllSetItems(ctx context.Context) ([]SetItem, error) {
 	return items, rows.Err()
 }
 
-// GetAllRunewordsForList retrieves all runewords for listing
-func (r *Repository) GetAllRunewordsForList(ctx context.Context) ([]Runeword, error) {
-	sql := `SELECT id FROM d2.runewords WHERE complete = true ORDER BY display_name`
-	rows, err := r.pool.Query(ctx, sql)
+// GetUniqueItemsByBaseCode retrieves all enabled unique items built on a base
+func (r *Repository) GetUniqueItemsByBaseCode(ctx context.Context, baseCode string) ([]UniqueItem, error) {
+	sql := `SELECT id FROM ` + tableUniqueItems + ` WHERE enabled = true AND base_code = $1 ORDER BY name`
+	rows, err := r.db.Query(ctx, sql, baseCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]UniqueItem, 0, len(ids))
+	for _, id := range ids {
+		item, err := r.GetUniqueItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// GetSetItemsByBaseCode retrieves all set items built on a base
+func (r *Repository) GetSetItemsByBaseCode(ctx context.Context, baseCode string) ([]SetItem, error) {
+	sql := `SELECT id FROM ` + tableSetItems + ` WHERE enabled IS NOT FALSE AND base_code = $1 ORDER BY set_name, name`
+	rows, err := r.db.Query(ctx, sql, baseCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]SetItem, 0, len(ids))
+	for _, id := range ids {
+		item, err := r.GetSetItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// GetAllRunewordsForList retrieves all runewords matching the ladder filter
+// for listing
+func (r *Repository) GetAllRunewordsForList(ctx context.Context, filter LadderFilter) ([]Runeword, error) {
+	cond, args := filter.where(nil)
+	sql := `SELECT id FROM ` + tableRunewords + ` WHERE complete = true` + cond + ` ORDER BY display_name`
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -771,31 +1487,518 @@ func (r *Repository) GetAllRunewordsForList(ctx context.Context) ([]Runeword, er
 	return items, rows.Err()
 }
 
+// StreamRunewords calls fn with each complete runeword, ordered by display
+// name, as rows are read from a single query
+func (r *Repository) StreamRunewords(ctx context.Context, fn func(*Runeword) error) error {
+	rows, err := r.db.Query(ctx, `SELECT `+runewordColumns+` FROM `+tableRunewords+` WHERE complete = true ORDER BY display_name`)
+	if err != nil {
+		return fmt.Errorf("stream runewords failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rw, err := scanRuneword(rows)
+		if err != nil {
+			return fmt.Errorf("stream runewords failed: %w", err)
+		}
+		if err := fn(rw); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// StatSearchOptions controls a search for items by the stats they carry
+type StatSearchOptions struct {
+	Codes    []string // Stat codes (aliases are resolved)
+	MatchAny bool     // Match items with any of the stats instead of all of them
+	Limit    int
+	Offset   int
+
+	// Thresholds must all hold regardless of MatchAny
+	Thresholds []StatThreshold
+	// CompareMin compares thresholds against a property's min roll instead
+	// of its max roll
+	CompareMin bool
+}
+
+// StatThreshold requires an item to carry a stat whose value falls within
+// the bounds. Params (e.g. the skill of a skill-specific stat) are ignored.
+type StatThreshold struct {
+	Code string // Stat code (aliases are resolved)
+	Min  *int   // Inclusive lower bound, nil for none
+	Max  *int   // Inclusive upper bound, nil for none
+}
+
+// SearchItemsByStats finds uniques, set items and runewords whose properties
+// contain all (or, with MatchAny, any) of the requested stat codes and meet
+// every threshold. Returns the requested page and the total number of matches.
+func (r *Repository) SearchItemsByStats(ctx context.Context, opts StatSearchOptions) ([]SearchResult, int, error) {
+	if len(opts.Codes) == 0 && len(opts.Thresholds) == 0 {
+		return nil, 0, nil
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.Limit > 100 {
+		opts.Limit = 100
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	// One EXISTS per requested stat, each matching the stat or any of its aliases
+	args := make([]interface{}, 0, len(opts.Codes)+2)
+	conds := make([]string, 0, len(opts.Codes))
+	for _, code := range opts.Codes {
+		args = append(args, StatCodeGroup(code))
+		conds = append(conds, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM jsonb_array_elements(properties) p WHERE p->>'code' = ANY($%d))", len(args)))
+	}
+	joiner := " AND "
+	if opts.MatchAny {
+		joiner = " OR "
+	}
+	filters := make([]string, 0, len(opts.Thresholds)+1)
+	if len(conds) > 0 {
+		filters = append(filters, "("+strings.Join(conds, joiner)+")")
+	}
+
+	// One EXISTS per threshold, comparing the chosen roll bound numerically
+	value := "(p->>'max')::int"
+	if opts.CompareMin {
+		value = "(p->>'min')::int"
+	}
+	for _, t := range opts.Thresholds {
+		args = append(args, StatCodeGroup(t.Code))
+		cond := fmt.Sprintf("p->>'code' = ANY($%d)", len(args))
+		if t.Min != nil {
+			args = append(args, *t.Min)
+			cond += fmt.Sprintf(" AND %s >= $%d", value, len(args))
+		}
+		if t.Max != nil {
+			args = append(args, *t.Max)
+			cond += fmt.Sprintf(" AND %s <= $%d", value, len(args))
+		}
+		filters = append(filters, "EXISTS (SELECT 1 FROM jsonb_array_elements(properties) p WHERE "+cond+")")
+	}
+	statFilter := strings.Join(filters, " AND ")
+
+	itemsSQL := `
+		WITH all_items AS (
+			SELECT
+				id, name, 'unique' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
+					 WHERE ib.code = unique_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url
+			FROM ` + tableUniqueItems + `
+			WHERE enabled = true AND ` + statFilter + `
+
+			UNION ALL
+
+			SELECT
+				id, name, 'set' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM ` + tableItemTypes + ` it
+					 JOIN ` + tableItemBases + ` ib ON ib.item_type = it.code
+					 WHERE ib.code = set_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url
+			FROM ` + tableSetItems + `
+			WHERE enabled IS NOT FALSE AND ` + statFilter + `
+
+			UNION ALL
+
+			SELECT
+				id, display_name as name, 'runeword' as type, 'Runeword' as category,
+				NULL as base_name, image_url
+			FROM ` + tableRunewords + `
+			WHERE complete = true AND ` + statFilter + `
+		)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, itemsSQL+` SELECT COUNT(*) FROM all_items`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count stat search failed: %w", err)
+	}
+
+	args = append(args, opts.Limit, opts.Offset)
+	pageSQL := itemsSQL + fmt.Sprintf(`
+		SELECT id, name, type, category, base_name, image_url
+		FROM all_items
+		ORDER BY type, name
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, pageSQL, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("stat search query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		sr := SearchResult{MatchType: "stat"}
+		var baseName, imageURL *string
+		if err := rows.Scan(&sr.ID, &sr.Name, &sr.Type, &sr.Category, &baseName, &imageURL); err != nil {
+			return nil, 0, fmt.Errorf("scan stat search result failed: %w", err)
+		}
+		if baseName != nil {
+			sr.BaseName = *baseName
+		}
+		if imageURL != nil {
+			sr.ImageURL = *imageURL
+		}
+		results = append(results, sr)
+	}
+
+	return results, total, rows.Err()
+}
+
+// maxStatCandidates caps the candidates loaded for in-memory ranking
+const maxStatCandidates = 1000
+
+// StatCandidateOptions controls which items are loaded as stat candidates
+type StatCandidateOptions struct {
+	Codes     []string // Stat codes; items need at least one (aliases are resolved)
+	ItemTypes []string // Optional base item type codes to restrict to
+	Types     []string // Optional item types: unique, set, runeword (default all)
+}
+
+// GetStatCandidates loads uniques, set items and runewords that carry any of
+// the requested stats, with their properties, for ranking by the caller.
+// Uniques and set items match ItemTypes on their base; runewords on their
+// valid item types.
+func (r *Repository) GetStatCandidates(ctx context.Context, opts StatCandidateOptions) ([]StatCandidate, error) {
+	if len(opts.Codes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(opts.Codes)+1)
+	conds := make([]string, 0, len(opts.Codes))
+	for _, code := range opts.Codes {
+		args = append(args, StatCodeGroup(code))
+		conds = append(conds, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM jsonb_array_elements(properties) p WHERE p->>'code' = ANY($%d))", len(args)))
+	}
+	statFilter := "(" + strings.Join(conds, " OR ") + ")"
+
+	// Uniques and set items match on their base's type, runewords on valid types
+	baseTypeFilter := func(table string) string { return "" }
+	runewordTypeFilter := ""
+	if len(opts.ItemTypes) > 0 {
+		args = append(args, opts.ItemTypes)
+		typesArg := len(args)
+		baseTypeFilter = func(table string) string {
+			return fmt.Sprintf(` AND EXISTS (SELECT 1 FROM `+tableItemBases+` ib WHERE ib.code = %s.base_code AND ib.item_type = ANY($%d))`, table, typesArg)
+		}
+		runewordTypeFilter = fmt.Sprintf(` AND EXISTS (SELECT 1 FROM jsonb_array_elements_text(valid_item_types) t WHERE t = ANY($%d))`, typesArg)
+	}
+
+	wanted := make(map[string]bool)
+	for _, t := range opts.Types {
+		wanted[t] = true
+	}
+	include := func(t string) bool { return len(wanted) == 0 || wanted[t] }
+
+	var parts []string
+	if include("unique") {
+		parts = append(parts, `
+			SELECT id, name, 'unique' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM `+tableItemTypes+` it
+					 JOIN `+tableItemBases+` ib ON ib.item_type = it.code
+					 WHERE ib.code = unique_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url, properties
+			FROM `+tableUniqueItems+`
+			WHERE enabled = true AND `+statFilter+baseTypeFilter("unique_items"))
+	}
+	if include("set") {
+		parts = append(parts, `
+			SELECT id, name, 'set' as type,
+				COALESCE(
+					(SELECT it.name
+					 FROM `+tableItemTypes+` it
+					 JOIN `+tableItemBases+` ib ON ib.item_type = it.code
+					 WHERE ib.code = set_items.base_code LIMIT 1),
+					'Unknown'
+				) as category,
+				base_name, image_url, properties
+			FROM `+tableSetItems+`
+			WHERE enabled IS NOT FALSE AND `+statFilter+baseTypeFilter("set_items"))
+	}
+	if include("runeword") {
+		parts = append(parts, `
+			SELECT id, display_name as name, 'runeword' as type, 'Runeword' as category,
+				NULL as base_name, image_url, properties
+			FROM `+tableRunewords+`
+			WHERE complete = true AND `+statFilter+runewordTypeFilter)
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	sql := strings.Join(parts, "\n\t\t\tUNION ALL\n") + fmt.Sprintf("\n\t\tLIMIT %d", maxStatCandidates)
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("stat candidates query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []StatCandidate
+	for rows.Next() {
+		cand := StatCandidate{SearchResult: SearchResult{MatchType: "stat"}}
+		var baseName, imageURL *string
+		var propsJSON []byte
+		if err := rows.Scan(&cand.ID, &cand.Name, &cand.Type, &cand.Category, &baseName, &imageURL, &propsJSON); err != nil {
+			return nil, fmt.Errorf("scan stat candidate failed: %w", err)
+		}
+		if baseName != nil {
+			cand.BaseName = *baseName
+		}
+		if imageURL != nil {
+			cand.ImageURL = *imageURL
+		}
+		if len(propsJSON) > 0 {
+			if err := json.Unmarshal(propsJSON, &cand.Properties); err != nil {
+				return nil, fmt.Errorf("unmarshal properties failed: %w", err)
+			}
+		}
+		candidates = append(candidates, cand)
+	}
+
+	return candidates, rows.Err()
+}
+
+// countDescriptionMatchSQL is descriptionMatchSQL with CountSearchResults'
+// parameter numbers
+const countDescriptionMatchSQL = `($4 AND to_tsvector('english', COALESCE(description, '')) @@ plainto_tsquery('english', $5))`
+
 // CountSearchResults counts total results for a search query
-func (r *Repository) CountSearchResults(ctx context.Context, query string) (int, error) {
+func (r *Repository) CountSearchResults(ctx context.Context, query string, opts SearchOptions) (int, error) {
 	pattern := "%" + strings.ToLower(query) + "%"
+	codePattern := strings.ToLower(query) + "%"
 
 	sql := `
 		SELECT COUNT(*) FROM (
-			SELECT id FROM d2.unique_items WHERE enabled = true AND LOWER(name) LIKE $1
+			SELECT id FROM ` + tableUniqueItems + ` WHERE enabled = true AND (LOWER(name) LIKE $1 OR LOWER(base_name) LIKE $1)
 			UNION ALL
-			SELECT id FROM d2.set_items WHERE LOWER(name) LIKE $1
+			SELECT id FROM ` + tableSetItems + ` WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(base_name) LIKE $1)
 			UNION ALL
-			SELECT id FROM d2.runewords WHERE complete = true AND LOWER(display_name) LIKE $1
+			SELECT id FROM ` + tableRunewords + ` WHERE complete = true AND LOWER(display_name) LIKE $1
 			UNION ALL
-			SELECT id FROM d2.runes WHERE LOWER(name) LIKE $1
+			SELECT id FROM ` + tableRunes + ` WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $3)
 			UNION ALL
-			SELECT id FROM d2.gems WHERE LOWER(name) LIKE $1
+			SELECT id FROM ` + tableGems + ` WHERE enabled IS NOT FALSE AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $3)
 			UNION ALL
-			SELECT id FROM d2.item_bases WHERE spawnable = true AND tradable = true AND LOWER(name) LIKE $1
-				AND NOT EXISTS (SELECT 1 FROM d2.gems g WHERE g.code = item_bases.code)
-				AND NOT EXISTS (SELECT 1 FROM d2.runes r WHERE r.code = item_bases.code)
+			SELECT id FROM ` + tableItemBases + ` WHERE spawnable = true AND tradable = true AND quest_item IS NOT TRUE AND placeholder IS NOT TRUE
+				AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $3 OR ` + countDescriptionMatchSQL + `)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableGems + ` g WHERE g.code = item_bases.code)
+				AND NOT EXISTS (SELECT 1 FROM ` + tableRunes + ` r WHERE r.code = item_bases.code)
 			UNION ALL
-			SELECT id FROM d2.item_bases WHERE quest_item = true AND LOWER(name) LIKE $1
+			SELECT id FROM ` + tableItemBases + ` WHERE $2 AND quest_item = true AND (LOWER(name) LIKE $1 OR LOWER(code) LIKE $3 OR ` + countDescriptionMatchSQL + `)
 		) AS all_items
 	`
 
 	var count int
-	err := r.pool.QueryRow(ctx, sql, pattern).Scan(&count)
+	err := r.db.QueryRow(ctx, sql, pattern, opts.IncludeQuest, codePattern, opts.IncludeDescription, query).Scan(&count)
 	return count, err
 }
+
+// StatRange is the lowest and highest value a stat takes across stored items
+type StatRange struct {
+	Code  string // Primary stat code
+	Min   int    // Lowest min roll
+	Max   int    // Highest max roll
+	Count int    // Number of properties carrying the stat
+}
+
+// GetStatObservedRange returns the min/max a stat (and its aliases) takes
+// across enabled uniques, set items and complete runewords. Returns nil when
+// no stored item carries the stat.
+func (r *Repository) GetStatObservedRange(ctx context.Context, code string) (*StatRange, error) {
+	group := StatCodeGroup(code)
+	sql := `
+		WITH props AS (
+			SELECT p FROM ` + tableUniqueItems + `, jsonb_array_elements(properties) p
+			WHERE enabled = true
+			UNION ALL
+			SELECT p FROM ` + tableSetItems + `, jsonb_array_elements(properties) p
+			WHERE enabled IS NOT FALSE
+			UNION ALL
+			SELECT p FROM ` + tableRunewords + `, jsonb_array_elements(properties) p
+			WHERE complete = true
+		)
+		SELECT
+			COUNT(*),
+			COALESCE(MIN(LEAST((p->>'min')::int, (p->>'max')::int)), 0),
+			COALESCE(MAX(GREATEST((p->>'min')::int, (p->>'max')::int)), 0)
+		FROM props
+		WHERE p->>'code' = ANY($1)
+	`
+
+	sr := StatRange{Code: group[0]}
+	if err := r.db.QueryRow(ctx, sql, group).Scan(&sr.Count, &sr.Min, &sr.Max); err != nil {
+		return nil, fmt.Errorf("stat range query failed: %w", err)
+	}
+	if sr.Count == 0 {
+		return nil, nil
+	}
+	return &sr, nil
+}
+
+// StatUsage is how many stored items carry a property code
+type StatUsage struct {
+	Code       string
+	ItemCount  int    // Enabled uniques, set items and complete runewords with the code
+	SampleText string // One stored display text for the code, may be empty
+}
+
+// GetStatUsage counts the items carrying each property code across enabled
+// uniques, set items (including their bonuses) and complete runewords
+func (r *Repository) GetStatUsage(ctx context.Context) ([]StatUsage, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH props AS (
+			SELECT 'unique' AS type, id, p FROM `+tableUniqueItems+`, jsonb_array_elements(properties) p
+			WHERE enabled = true
+			UNION ALL
+			SELECT 'set', id, p FROM `+tableSetItems+`,
+				jsonb_array_elements(COALESCE(properties, '[]'::jsonb) || COALESCE(bonus_properties, '[]'::jsonb)) p
+			WHERE enabled IS NOT FALSE
+			UNION ALL
+			SELECT 'runeword', id, p FROM `+tableRunewords+`, jsonb_array_elements(properties) p
+			WHERE complete = true
+		)
+		SELECT p->>'code', COUNT(DISTINCT (type, id)), COALESCE(MAX(p->>'displayText'), '')
+		FROM props
+		WHERE p->>'code' IS NOT NULL
+		GROUP BY p->>'code'
+		ORDER BY p->>'code'`)
+	if err != nil {
+		return nil, fmt.Errorf("stat usage query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []StatUsage
+	for rows.Next() {
+		var u StatUsage
+		if err := rows.Scan(&u.Code, &u.ItemCount, &u.SampleText); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// GetCatalogUpdatedAt returns the latest update time across uniques, set
+// items and runewords. Imports touch every row they upsert, so a change in
+// this value marks a new import. Returns the zero time for an empty catalog.
+func (r *Repository) GetCatalogUpdatedAt(ctx context.Context) (time.Time, error) {
+	var updatedAt *time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT GREATEST(
+			(SELECT MAX(updated_at) FROM `+tableUniqueItems+`),
+			(SELECT MAX(updated_at) FROM `+tableSetItems+`),
+			(SELECT MAX(updated_at) FROM `+tableRunewords+`)
+		)`).Scan(&updatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("catalog updated_at query failed: %w", err)
+	}
+	if updatedAt == nil {
+		return time.Time{}, nil
+	}
+	return *updatedAt, nil
+}
+
+// OrphanReport is a unique or set item whose base_code matches no item base
+type OrphanReport struct {
+	Table    string `json:"table"` // "unique_items" or "set_items"
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	BaseCode string `json:"base_code"` // Dangling code, empty when the base never resolved
+	BaseName string `json:"base_name,omitempty"`
+}
+
+// FindOrphanedItems returns enabled uniques and set items whose base_code is
+// empty or missing from item_bases, ordered by table and name
+func (r *Repository) FindOrphanedItems(ctx context.Context) ([]OrphanReport, error) {
+	sql := `
+		SELECT 'unique_items', id, name, COALESCE(base_code, ''), COALESCE(base_name, '')
+		FROM ` + tableUniqueItems + ` u
+		WHERE enabled = true
+			AND NOT EXISTS (SELECT 1 FROM ` + tableItemBases + ` b WHERE b.code = u.base_code)
+
+		UNION ALL
+
+		SELECT 'set_items', id, name, COALESCE(base_code, ''), COALESCE(base_name, '')
+		FROM ` + tableSetItems + ` s
+		WHERE enabled IS NOT FALSE
+			AND NOT EXISTS (SELECT 1 FROM ` + tableItemBases + ` b WHERE b.code = s.base_code)
+
+		ORDER BY 1, 3
+	`
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("find orphaned items failed: %w", err)
+	}
+	defer rows.Close()
+
+	orphans := make([]OrphanReport, 0)
+	for rows.Next() {
+		var o OrphanReport
+		if err := rows.Scan(&o.Table, &o.ID, &o.Name, &o.BaseCode, &o.BaseName); err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, o)
+	}
+	return orphans, rows.Err()
+}
+
+// etagTables lists the tables GetTableVersion may query
+var etagTables = map[string]bool{
+	"item_types":   true,
+	"item_bases":   true,
+	"unique_items": true,
+	"set_bonuses":  true,
+	"set_items":    true,
+	"runewords":    true,
+	"runes":        true,
+	"gems":         true,
+	"stats":        true,
+	"classes":      true,
+}
+
+// TableVersion identifies the content of a d2 table for cache validation
+type TableVersion struct {
+	UpdatedAt time.Time // newest updated_at, zero when the table is empty
+	Rows      int64
+}
+
+// GetTableVersion returns the most recent updated_at and the row count of a
+// d2 table. Imports and admin writes bump updated_at; the count also changes
+// when rows are hard-deleted, which leaves the remaining timestamps as they were.
+func (r *Repository) GetTableVersion(ctx context.Context, table string) (TableVersion, error) {
+	if !etagTables[table] {
+		return TableVersion{}, fmt.Errorf("unknown table %q", table)
+	}
+
+	var v TableVersion
+	var updatedAt *time.Time
+	err := r.db.QueryRow(ctx, "SELECT MAX(updated_at), COUNT(*) FROM "+QualifiedTable(table)).Scan(&updatedAt, &v.Rows)
+	if err != nil {
+		return TableVersion{}, fmt.Errorf("get table version failed: %w", err)
+	}
+	if updatedAt != nil {
+		v.UpdatedAt = *updatedAt
+	}
+	return v, nil
+}
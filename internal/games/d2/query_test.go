@@ -0,0 +1,269 @@
+package d2
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+)
+
+// runewordRow is a row selected with runewordColumns
+func runewordRow(id int, name string, runes []string, props []Property) []interface{} {
+	return []interface{}{
+		id, "HTMLRuneword_" + name, name, true, false, nil, nil,
+		dbtest.JSON([]string{"weap"}), nil, dbtest.JSON(runes), dbtest.JSON(props), nil,
+		nil, nil,
+	}
+}
+
+func TestGetRunewordsContainingRune(t *testing.T) {
+	db := dbtest.NewFake().On("runes ? $1",
+		runewordRow(1, "Enigma", []string{"r31", "r06", "r30"}, []Property{{Code: "allskills", Min: 2, Max: 2}}),
+		runewordRow(2, "Infinity", []string{"r30", "r23", "r30", "r24"}, nil),
+	)
+
+	got, err := NewRepository(db).GetRunewordsContainingRune(context.Background(), "r30")
+	if err != nil {
+		t.Fatalf("GetRunewordsContainingRune: %v", err)
+	}
+
+	if calls := db.Calls(); len(calls) != 1 {
+		t.Fatalf("ran %d queries, want 1", len(calls))
+	} else if calls[0].Args[0] != "r30" {
+		t.Errorf("queried rune %v, want r30", calls[0].Args[0])
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d runewords, want 2", len(got))
+	}
+	if got[0].DisplayName != "Enigma" || got[1].DisplayName != "Infinity" {
+		t.Errorf("got %q and %q, want Enigma and Infinity", got[0].DisplayName, got[1].DisplayName)
+	}
+	if want := []string{"r30", "r23", "r30", "r24"}; !reflect.DeepEqual(got[1].Runes, want) {
+		t.Errorf("Infinity runes = %v, want %v", got[1].Runes, want)
+	}
+	if len(got[0].Properties) != 1 || got[0].Properties[0].Code != "allskills" {
+		t.Errorf("Enigma properties = %+v", got[0].Properties)
+	}
+}
+
+func TestGetRunewordsContainingRuneNone(t *testing.T) {
+	got, err := NewRepository(dbtest.NewFake()).GetRunewordsContainingRune(context.Background(), "r01")
+	if err != nil {
+		t.Fatalf("GetRunewordsContainingRune: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("got %v, want an empty list", got)
+	}
+}
+
+func TestSearchItemsFuzzyFiltersEachTableByThreshold(t *testing.T) {
+	db := dbtest.NewFake().On("similarity(", []interface{}{7, "Enigma", "runeword", "Runeword", nil, nil, float32(0.5454)})
+
+	got, err := NewRepository(db).SearchItemsFuzzy(context.Background(), "enigam", 20, SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchItemsFuzzy: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "Enigma" || got[0].Score != 0.545 {
+		t.Errorf("got %+v, want Enigma scored 0.545", got)
+	}
+
+	calls := db.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("ran %d statements, want 2", len(calls))
+	}
+	if !strings.Contains(calls[0].SQL, "pg_trgm.similarity_threshold") || calls[0].Args[0] != "0.3" {
+		t.Errorf("first statement = %q %v, want the similarity threshold set to 0.3", calls[0].SQL, calls[0].Args)
+	}
+	// One % filter per source table lets the trigram indexes serve the search
+	if n := strings.Count(calls[1].SQL, "% LOWER($1)"); n != 7 {
+		t.Errorf("search filters %d tables with %%, want 7", n)
+	}
+}
+
+func TestSearchItemsFuzzyWithoutTrigramExtension(t *testing.T) {
+	db := dbtest.NewFake().OnError("similarity(", &pgconn.PgError{Code: undefinedFunctionCode})
+
+	got, err := NewRepository(db).SearchItemsFuzzy(context.Background(), "enigam", 20, SearchOptions{})
+	if err != nil || got != nil {
+		t.Errorf("got %v, %v; want no results and no error", got, err)
+	}
+}
+
+func TestSearchItemsRanksExactNameAbovePrefix(t *testing.T) {
+	repo := NewRepository(dbtest.Tx(t))
+	ctx := context.Background()
+
+	if err := repo.UpsertRune(ctx, &Rune{Code: "r30", Name: "Ber", RuneNumber: 30, Level: 63, LevelReq: 63}); err != nil {
+		t.Fatalf("UpsertRune: %v", err)
+	}
+	axe := &ItemBase{
+		Code: "7wa", Name: "Berserker Axe", ItemType: "axe", Category: "weapon",
+		Tier: "Elite", TypeTags: []string{}, Tradable: true, Spawnable: true,
+	}
+	if err := repo.UpsertItemBase(ctx, axe); err != nil {
+		t.Fatalf("UpsertItemBase: %v", err)
+	}
+
+	results, err := repo.SearchItems(ctx, "ber", 100, SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchItems: %v", err)
+	}
+	rank := make(map[string]int)
+	for i, r := range results {
+		if _, seen := rank[r.Name]; !seen {
+			rank[r.Name] = i
+		}
+	}
+	ber, ok := rank["Ber"]
+	if !ok {
+		t.Fatalf("Ber missing from results %+v", results)
+	}
+	axeRank, ok := rank["Berserker Axe"]
+	if !ok {
+		t.Fatalf("Berserker Axe missing from results %+v", results)
+	}
+	if ber > axeRank {
+		t.Errorf("Ber ranked %d, below Berserker Axe at %d", ber, axeRank)
+	}
+	if results[ber].Score != ScoreExact || results[axeRank].Score != ScorePrefix {
+		t.Errorf("scores = %v and %v, want %v and %v", results[ber].Score, results[axeRank].Score, ScoreExact, ScorePrefix)
+	}
+}
+
+func TestQuestItemsExcludedByDefault(t *testing.T) {
+	repo := NewRepository(dbtest.Tx(t))
+	ctx := context.Background()
+
+	cube := &ItemBase{
+		Code: "box", Name: "Horadric Cube", ItemType: "ques", Category: "misc",
+		TypeTags: []string{}, Spawnable: true, QuestItem: true,
+	}
+	if err := repo.UpsertItemBase(ctx, cube); err != nil {
+		t.Fatalf("UpsertItemBase: %v", err)
+	}
+	hasCube := func(names []string) bool {
+		for _, name := range names {
+			if name == "Horadric Cube" {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, includeQuest := range []bool{false, true} {
+		results, err := repo.SearchItems(ctx, "horadric cube", 100, SearchOptions{IncludeQuest: includeQuest})
+		if err != nil {
+			t.Fatalf("SearchItems: %v", err)
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if hasCube(names) != includeQuest {
+			t.Errorf("search with IncludeQuest=%v returned %v", includeQuest, names)
+		}
+
+		bases, err := repo.GetAllItemBases(ctx, ItemBaseListOptions{IncludeQuest: includeQuest})
+		if err != nil {
+			t.Fatalf("GetAllItemBases: %v", err)
+		}
+		names = names[:0]
+		for _, b := range bases {
+			names = append(names, b.Name)
+		}
+		if hasCube(names) != includeQuest {
+			t.Errorf("bases with IncludeQuest=%v: Horadric Cube listed = %v", includeQuest, !includeQuest)
+		}
+	}
+
+	quests, err := repo.GetAllQuestItems(ctx)
+	if err != nil {
+		t.Fatalf("GetAllQuestItems: %v", err)
+	}
+	var names []string
+	for _, q := range quests {
+		names = append(names, q.Name)
+	}
+	if !hasCube(names) {
+		t.Errorf("quest items = %v, want the Horadric Cube", names)
+	}
+}
+
+func TestSearchItemsByCode(t *testing.T) {
+	repo := NewRepository(dbtest.Tx(t))
+	ctx := context.Background()
+
+	if err := repo.UpsertRune(ctx, &Rune{Code: "r30", Name: "Ber", RuneNumber: 30, Level: 63, LevelReq: 63}); err != nil {
+		t.Fatalf("UpsertRune: %v", err)
+	}
+	blade := &ItemBase{
+		Code: "7ws", Name: "Phase Blade", ItemType: "swor", Category: "weapon",
+		Tier: "Elite", TypeTags: []string{}, Tradable: true, Spawnable: true,
+	}
+	if err := repo.UpsertItemBase(ctx, blade); err != nil {
+		t.Fatalf("UpsertItemBase: %v", err)
+	}
+
+	for code, want := range map[string]string{"r30": "Ber", "7ws": "Phase Blade", "7WS": "Phase Blade"} {
+		results, err := repo.SearchItems(ctx, code, 20, SearchOptions{})
+		if err != nil {
+			t.Fatalf("SearchItems(%q): %v", code, err)
+		}
+		if len(results) == 0 || results[0].Name != want || results[0].MatchType != "code" {
+			t.Errorf("SearchItems(%q) = %+v, want %s first with a code match", code, results, want)
+		}
+	}
+}
+
+func TestSearchItemsPassesCodePrefix(t *testing.T) {
+	db := dbtest.NewFake().On("-- Quest items", []interface{}{30, "Ber", "rune", "Rune", nil, nil, "code", ScoreExact})
+
+	got, err := NewRepository(db).SearchItems(context.Background(), "R30", 20, SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchItems: %v", err)
+	}
+	if len(got) != 1 || got[0].MatchType != "code" {
+		t.Errorf("got %+v, want Ber as a code match", got)
+	}
+	// Codes match by lowercased prefix, names anywhere in the name
+	if args := db.Calls()[0].Args; args[0] != "%r30%" || args[4] != "r30%" {
+		t.Errorf("patterns = %v and %v, want %%r30%% and r30%%", args[0], args[4])
+	}
+}
+
+func TestSearchItemsByStatsResolvesAliases(t *testing.T) {
+	db := dbtest.NewFake().
+		On("SELECT COUNT(*) FROM all_items", []interface{}{1}).
+		On("ORDER BY type, name", []interface{}{12, "Arachnid Mesh", "unique", "Belt", nil, nil})
+
+	atLeast := 20
+	got, total, err := NewRepository(db).SearchItemsByStats(context.Background(), StatSearchOptions{
+		Codes:      []string{"cast2"},
+		Thresholds: []StatThreshold{{Code: "swing1", Min: &atLeast}},
+	})
+	if err != nil {
+		t.Fatalf("SearchItemsByStats: %v", err)
+	}
+	if total != 1 || len(got) != 1 || got[0].Name != "Arachnid Mesh" {
+		t.Errorf("got %+v (total %d), want Arachnid Mesh", got, total)
+	}
+
+	// Aliases match through their canonical code and its whole alias group
+	for _, call := range db.Calls() {
+		if want := StatCodeGroup("fcr"); !reflect.DeepEqual(call.Args[0], want) {
+			t.Errorf("stat codes = %v, want %v", call.Args[0], want)
+		}
+		if want := StatCodeGroup("ias"); !reflect.DeepEqual(call.Args[1], want) {
+			t.Errorf("threshold codes = %v, want %v", call.Args[1], want)
+		}
+		if call.Args[2] != 20 {
+			t.Errorf("threshold min = %v, want 20", call.Args[2])
+		}
+	}
+	if got := StatCodeGroup("cast2"); len(got) == 0 || got[0] != "fcr" {
+		t.Errorf("StatCodeGroup(cast2) = %v, want fcr first", got)
+	}
+}
@@ -0,0 +1,73 @@
+package d2
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxRawPropertySamples caps the unmatched lines kept in ImportResult
+const maxRawPropertySamples = 50
+
+// rawPropertyReportSize is how many unmatched lines are printed after import
+const rawPropertyReportSize = 15
+
+// RawPropertySample is a property line the reverse translator couldn't match
+type RawPropertySample struct {
+	Text  string `json:"text"`  // Original input line
+	Count int    `json:"count"` // Times the line was seen during the import
+}
+
+// reverseTranslate reverse-translates one line, recording it if unmatched
+func (h *HTMLImporterV2) reverseTranslate(line string) Property {
+	prop := h.reverseTranslator.ReverseTranslate(line)
+	h.recordRaw(prop)
+	return prop
+}
+
+// reverseTranslateLines reverse-translates lines, recording unmatched ones
+func (h *HTMLImporterV2) reverseTranslateLines(lines []string) []Property {
+	props := h.reverseTranslator.ReverseTranslateLines(lines)
+	for _, p := range props {
+		h.recordRaw(p)
+	}
+	return props
+}
+
+// recordRaw counts a property that fell back to the raw code
+func (h *HTMLImporterV2) recordRaw(prop Property) {
+	if prop.Code != "raw" || prop.DisplayText == "" {
+		return
+	}
+	h.rawLines[prop.DisplayText]++
+}
+
+// reportRawProperties fills the raw property counts and the most frequent
+// unmatched lines into result, and prints the top offenders
+func (h *HTMLImporterV2) reportRawProperties(result *ImportResult) {
+	samples := make([]RawPropertySample, 0, len(h.rawLines))
+	for text, count := range h.rawLines {
+		result.RawProperties += count
+		samples = append(samples, RawPropertySample{Text: text, Count: count})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Count != samples[j].Count {
+			return samples[i].Count > samples[j].Count
+		}
+		return samples[i].Text < samples[j].Text
+	})
+	if len(samples) > maxRawPropertySamples {
+		samples = samples[:maxRawPropertySamples]
+	}
+	result.RawPropertySamples = samples
+
+	if len(samples) == 0 {
+		return
+	}
+	fmt.Printf("    Unmatched properties: %d lines (%d distinct), top offenders:\n", result.RawProperties, len(h.rawLines))
+	for i, s := range samples {
+		if i == rawPropertyReportSize {
+			break
+		}
+		fmt.Printf("      %4dx  %s\n", s.Count, s.Text)
+	}
+}
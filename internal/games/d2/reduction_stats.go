@@ -0,0 +1,60 @@
+package d2
+
+// reductionStatCodes are stats that lower something (requirements, enemy
+// resistance, damage taken). By convention they're stored as positive
+// magnitudes and the display template supplies the wording or minus sign:
+// ease=20 renders "Requirements -20%", never "Requirements --20%".
+var reductionStatCodes = map[string]bool{
+	"ease":         true,
+	"reduce-ac":    true,
+	"red-dmg":      true,
+	"red-dmg%":     true,
+	"red-mag":      true,
+	"res-pois-len": true,
+	"cheap":        true,
+	"pierce-fire":  true,
+	"pierce-cold":  true,
+	"pierce-ltng":  true,
+	"pierce-pois":  true,
+	"pierce-mag":   true,
+}
+
+// IsReductionStat reports whether a stat code is stored as a positive
+// reduction magnitude
+func IsReductionStat(code string) bool {
+	return reductionStatCodes[code]
+}
+
+// NormalizeReductionSign converts a reduction stat stored with negative
+// values (as in the game's data files) to the canonical positive magnitude,
+// keeping Min <= Max. Other properties are returned unchanged.
+func NormalizeReductionSign(prop Property) Property {
+	if !IsReductionStat(prop.Code) || prop.Min > 0 || prop.Max > 0 {
+		return prop
+	}
+	if prop.Min == 0 && prop.Max == 0 {
+		return prop
+	}
+	prop.Min, prop.Max = -prop.Max, -prop.Min
+	return prop
+}
+
+// ApplyRequirementReduction lowers a strength or dexterity requirement by the
+// item's ease ("Requirements -X%") properties, truncating like the game. A
+// ranged roll uses its smallest reduction, so the result is a requirement
+// every roll meets.
+func ApplyRequirementReduction(req int, props []Property) int {
+	ease := 0
+	for _, p := range props {
+		if p.Code == "ease" {
+			ease += NormalizeReductionSign(p).Min
+		}
+	}
+	if ease <= 0 || req <= 0 {
+		return req
+	}
+	if ease >= 100 {
+		return 0
+	}
+	return req * (100 - ease) / 100
+}
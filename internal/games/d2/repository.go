@@ -3,30 +3,62 @@ package d2
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// SchemaName is the Postgres schema holding the D2 catalog. Every query
+// reaches its tables through the table constants or QualifiedTable, so the
+// catalog can move to another schema by changing this one value.
+const SchemaName = "d2"
+
+// Catalog tables, qualified with SchemaName
+const (
+	tableItemTypes     = SchemaName + ".item_types"
+	tableItemBases     = SchemaName + ".item_bases"
+	tableUniqueItems   = SchemaName + ".unique_items"
+	tableSetBonuses    = SchemaName + ".set_bonuses"
+	tableSetItems      = SchemaName + ".set_items"
+	tableRunewords     = SchemaName + ".runewords"
+	tableRunewordBases = SchemaName + ".runeword_bases"
+	tableRunes         = SchemaName + ".runes"
+	tableGems          = SchemaName + ".gems"
+	tableStats         = SchemaName + ".stats"
+	tableClasses       = SchemaName + ".classes"
+	tableProfiles      = SchemaName + ".profiles"
+	tableImageHashes   = SchemaName + ".image_hashes"
+)
+
+// QualifiedTable prefixes a table name with SchemaName, for queries that pick
+// the table at runtime
+func QualifiedTable(name string) string {
+	return SchemaName + "." + name
+}
+
 type Repository struct {
-	pool *pgxpool.Pool
+	db DBTX
+
+	// skills is shared with the repository's transactions
+	skills *SkillNames
 }
 
-func NewRepository(pool *pgxpool.Pool) *Repository {
-	return &Repository{pool: pool}
+// NewRepository returns a repository querying db, usually a *pgxpool.Pool
+func NewRepository(db DBTX) *Repository {
+	return &Repository{db: db, skills: &SkillNames{}}
 }
 
 // ItemType operations
 func (r *Repository) ItemTypeExists(ctx context.Context, code string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.item_types WHERE code = $1)", code).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableItemTypes+" WHERE code = $1)", code).Scan(&exists)
 	return exists, err
 }
 
 func (r *Repository) UpsertItemType(ctx context.Context, it *ItemType) error {
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.item_types (code, name, equiv1, equiv2, body_loc1, body_loc2, can_be_magic, can_be_rare,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableItemTypes+` (code, name, equiv1, equiv2, body_loc1, body_loc2, can_be_magic, can_be_rare,
 			max_sockets_normal, max_sockets_nightmare, max_sockets_hell, staff_mods, class_restriction, store_page)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		ON CONFLICT (code) DO UPDATE SET
@@ -53,21 +85,21 @@ func (r *Repository) UpsertItemType(ctx context.Context, it *ItemType) error {
 // ItemBase operations
 func (r *Repository) ItemBaseExists(ctx context.Context, code string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.item_bases WHERE code = $1)", code).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableItemBases+" WHERE code = $1)", code).Scan(&exists)
 	return exists, err
 }
 
 func (r *Repository) UpsertItemBase(ctx context.Context, ib *ItemBase) error {
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.item_bases (code, name, item_type, item_type2, category, tier, type_tags, class_specific, tradable,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableItemBases+` (code, name, item_type, item_type2, category, tier, type_tags, class_specific, tradable,
 			level, level_req, str_req, dex_req,
 			durability, min_ac, max_ac, min_dam, max_dam, two_hand_min_dam, two_hand_max_dam, range_adder, speed,
 			str_bonus, dex_bonus, max_sockets, gem_apply_type, normal_code, exceptional_code, elite_code,
 			inv_width, inv_height, inv_file, flippy_file, unique_inv_file, set_inv_file, image_url,
-			spawnable, stackable, useable, throwable, quest_item, rarity, cost)
+			spawnable, stackable, useable, throwable, quest_item, rarity, cost, placeholder)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
 			$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39,
-			$40, $41, $42, $43)
+			$40, $41, $42, $43, $44)
 		ON CONFLICT (code) DO UPDATE SET
 			name = EXCLUDED.name,
 			item_type = EXCLUDED.item_type,
@@ -103,7 +135,7 @@ func (r *Repository) UpsertItemBase(ctx context.Context, ib *ItemBase) error {
 			flippy_file = EXCLUDED.flippy_file,
 			unique_inv_file = EXCLUDED.unique_inv_file,
 			set_inv_file = EXCLUDED.set_inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.item_bases.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableItemBases+`.image_url),
 			spawnable = EXCLUDED.spawnable,
 			stackable = EXCLUDED.stackable,
 			useable = EXCLUDED.useable,
@@ -111,6 +143,7 @@ func (r *Repository) UpsertItemBase(ctx context.Context, ib *ItemBase) error {
 			quest_item = EXCLUDED.quest_item,
 			rarity = EXCLUDED.rarity,
 			cost = EXCLUDED.cost,
+			placeholder = EXCLUDED.placeholder,
 			updated_at = NOW()`,
 		ib.Code, ib.Name, ib.ItemType, nullString(ib.ItemType2), ib.Category,
 		nullString(ib.Tier), ib.TypeTags, nullString(ib.ClassSpecific), ib.Tradable,
@@ -119,21 +152,21 @@ func (r *Repository) UpsertItemBase(ctx context.Context, ib *ItemBase) error {
 		ib.StrBonus, ib.DexBonus, ib.MaxSockets, ib.GemApplyType, nullString(ib.NormalCode), nullString(ib.ExceptionalCode),
 		nullString(ib.EliteCode), ib.InvWidth, ib.InvHeight, nullString(ib.InvFile), nullString(ib.FlippyFile),
 		nullString(ib.UniqueInvFile), nullString(ib.SetInvFile), nullString(ib.ImageURL),
-		ib.Spawnable, ib.Stackable, ib.Useable, ib.Throwable, ib.QuestItem, ib.Rarity, ib.Cost)
+		ib.Spawnable, ib.Stackable, ib.Useable, ib.Throwable, ib.QuestItem, ib.Rarity, ib.Cost, ib.Placeholder)
 	return err
 }
 
 // UniqueItem operations
 func (r *Repository) UniqueItemExists(ctx context.Context, indexID int) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.unique_items WHERE index_id = $1)", indexID).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableUniqueItems+" WHERE index_id = $1)", indexID).Scan(&exists)
 	return exists, err
 }
 
 func (r *Repository) UpsertUniqueItem(ctx context.Context, ui *UniqueItem) error {
 	propsJSON, _ := json.Marshal(ui.Properties)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.unique_items (index_id, name, base_code, base_name, level, level_req, rarity, enabled,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableUniqueItems+` (index_id, name, base_code, base_name, level, level_req, rarity, enabled,
 			ladder_only, first_ladder_season, last_ladder_season, properties, inv_transform, chr_transform,
 			inv_file, image_url, cost_mult, cost_add)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
@@ -152,7 +185,7 @@ func (r *Repository) UpsertUniqueItem(ctx context.Context, ui *UniqueItem) error
 			inv_transform = EXCLUDED.inv_transform,
 			chr_transform = EXCLUDED.chr_transform,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.unique_items.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableUniqueItems+`.image_url),
 			cost_mult = EXCLUDED.cost_mult,
 			cost_add = EXCLUDED.cost_add,
 			updated_at = NOW()`,
@@ -166,14 +199,14 @@ func (r *Repository) UpsertUniqueItem(ctx context.Context, ui *UniqueItem) error
 // UpsertUniqueItemByName upserts a unique item using name as the conflict key
 func (r *Repository) UpsertUniqueItemByName(ctx context.Context, ui *UniqueItem) error {
 	propsJSON, _ := json.Marshal(ui.Properties)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.unique_items (index_id, name, base_code, base_name, level, level_req, rarity, enabled,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableUniqueItems+` (index_id, name, base_code, base_name, level, level_req, rarity, enabled,
 			ladder_only, first_ladder_season, last_ladder_season, properties, inv_transform, chr_transform,
 			inv_file, image_url, cost_mult, cost_add)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		ON CONFLICT (name) DO UPDATE SET
-			base_code = CASE WHEN EXCLUDED.base_code != '' THEN EXCLUDED.base_code ELSE d2.unique_items.base_code END,
-			base_name = COALESCE(EXCLUDED.base_name, d2.unique_items.base_name),
+			base_code = CASE WHEN EXCLUDED.base_code != '' THEN EXCLUDED.base_code ELSE `+tableUniqueItems+`.base_code END,
+			base_name = COALESCE(EXCLUDED.base_name, `+tableUniqueItems+`.base_name),
 			level = EXCLUDED.level,
 			level_req = EXCLUDED.level_req,
 			rarity = EXCLUDED.rarity,
@@ -185,7 +218,7 @@ func (r *Repository) UpsertUniqueItemByName(ctx context.Context, ui *UniqueItem)
 			inv_transform = EXCLUDED.inv_transform,
 			chr_transform = EXCLUDED.chr_transform,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.unique_items.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableUniqueItems+`.image_url),
 			cost_mult = EXCLUDED.cost_mult,
 			cost_add = EXCLUDED.cost_add,
 			updated_at = NOW()`,
@@ -199,15 +232,15 @@ func (r *Repository) UpsertUniqueItemByName(ctx context.Context, ui *UniqueItem)
 // SetBonus operations
 func (r *Repository) SetBonusExists(ctx context.Context, name string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.set_bonuses WHERE name = $1)", name).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableSetBonuses+" WHERE name = $1)", name).Scan(&exists)
 	return exists, err
 }
 
 func (r *Repository) UpsertSetBonus(ctx context.Context, sb *SetBonus) error {
 	partialJSON, _ := json.Marshal(sb.PartialBonuses)
 	fullJSON, _ := json.Marshal(sb.FullBonuses)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.set_bonuses (index_id, name, version, partial_bonuses, full_bonuses)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableSetBonuses+` (index_id, name, version, partial_bonuses, full_bonuses)
 		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (name) DO UPDATE SET
 			version = EXCLUDED.version,
@@ -221,15 +254,15 @@ func (r *Repository) UpsertSetBonus(ctx context.Context, sb *SetBonus) error {
 // SetItem operations
 func (r *Repository) SetItemExists(ctx context.Context, indexID int) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.set_items WHERE index_id = $1)", indexID).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableSetItems+" WHERE index_id = $1)", indexID).Scan(&exists)
 	return exists, err
 }
 
 func (r *Repository) UpsertSetItem(ctx context.Context, si *SetItem) error {
 	propsJSON, _ := json.Marshal(si.Properties)
 	bonusJSON, _ := json.Marshal(si.BonusProperties)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.set_items (index_id, name, set_name, base_code, base_name, level, level_req, rarity,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableSetItems+` (index_id, name, set_name, base_code, base_name, level, level_req, rarity,
 			properties, bonus_properties, inv_transform, chr_transform, inv_file, image_url, cost_mult, cost_add)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (index_id) DO UPDATE SET
@@ -245,9 +278,10 @@ func (r *Repository) UpsertSetItem(ctx context.Context, si *SetItem) error {
 			inv_transform = EXCLUDED.inv_transform,
 			chr_transform = EXCLUDED.chr_transform,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.set_items.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableSetItems+`.image_url),
 			cost_mult = EXCLUDED.cost_mult,
 			cost_add = EXCLUDED.cost_add,
+			enabled = true,
 			updated_at = NOW()`,
 		si.IndexID, si.Name, si.SetName, si.BaseCode, nullString(si.BaseName), si.Level, si.LevelReq, si.Rarity,
 		string(propsJSON), string(bonusJSON), nullString(si.InvTransform), nullString(si.ChrTransform),
@@ -259,14 +293,14 @@ func (r *Repository) UpsertSetItem(ctx context.Context, si *SetItem) error {
 func (r *Repository) UpsertSetItemByName(ctx context.Context, si *SetItem) error {
 	propsJSON, _ := json.Marshal(si.Properties)
 	bonusJSON, _ := json.Marshal(si.BonusProperties)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.set_items (index_id, name, set_name, base_code, base_name, level, level_req, rarity,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableSetItems+` (index_id, name, set_name, base_code, base_name, level, level_req, rarity,
 			properties, bonus_properties, inv_transform, chr_transform, inv_file, image_url, cost_mult, cost_add)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (name) DO UPDATE SET
 			set_name = EXCLUDED.set_name,
-			base_code = CASE WHEN EXCLUDED.base_code != '' THEN EXCLUDED.base_code ELSE d2.set_items.base_code END,
-			base_name = COALESCE(EXCLUDED.base_name, d2.set_items.base_name),
+			base_code = CASE WHEN EXCLUDED.base_code != '' THEN EXCLUDED.base_code ELSE `+tableSetItems+`.base_code END,
+			base_name = COALESCE(EXCLUDED.base_name, `+tableSetItems+`.base_name),
 			level = EXCLUDED.level,
 			level_req = EXCLUDED.level_req,
 			rarity = EXCLUDED.rarity,
@@ -275,9 +309,10 @@ func (r *Repository) UpsertSetItemByName(ctx context.Context, si *SetItem) error
 			inv_transform = EXCLUDED.inv_transform,
 			chr_transform = EXCLUDED.chr_transform,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.set_items.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableSetItems+`.image_url),
 			cost_mult = EXCLUDED.cost_mult,
 			cost_add = EXCLUDED.cost_add,
+			enabled = true,
 			updated_at = NOW()`,
 		si.IndexID, si.Name, si.SetName, si.BaseCode, nullString(si.BaseName), si.Level, si.LevelReq, si.Rarity,
 		string(propsJSON), string(bonusJSON), nullString(si.InvTransform), nullString(si.ChrTransform),
@@ -288,17 +323,49 @@ func (r *Repository) UpsertSetItemByName(ctx context.Context, si *SetItem) error
 // Runeword operations
 func (r *Repository) RunewordExists(ctx context.Context, name string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.runewords WHERE name = $1)", name).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableRunewords+" WHERE name = $1)", name).Scan(&exists)
 	return exists, err
 }
 
+// GetRunewordIDByName returns the ID of the runeword with the given internal
+// name (the upsert key), complete or not
+func (r *Repository) GetRunewordIDByName(ctx context.Context, name string) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx, "SELECT id FROM "+tableRunewords+" WHERE name = $1", name).Scan(&id)
+	return id, err
+}
+
+// UnknownTypeTags returns the tags that no base item carries in type_tags, in
+// input order. A runeword restricted to only unknown tags can't have bases.
+func (r *Repository) UnknownTypeTags(ctx context.Context, tags []string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT t.tag
+		FROM unnest($1::text[]) WITH ORDINALITY AS t(tag, pos)
+		WHERE NOT EXISTS (SELECT 1 FROM `+tableItemBases+` WHERE type_tags @> ARRAY[t.tag])
+		ORDER BY t.pos`, tags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var unknown []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		unknown = append(unknown, tag)
+	}
+	return unknown, rows.Err()
+}
+
 func (r *Repository) UpsertRuneword(ctx context.Context, rw *Runeword) error {
 	validTypesJSON, _ := json.Marshal(rw.ValidItemTypes)
 	excludedTypesJSON, _ := json.Marshal(rw.ExcludedItemTypes)
 	runesJSON, _ := json.Marshal(rw.Runes)
 	propsJSON, _ := json.Marshal(rw.Properties)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.runewords (name, display_name, complete, ladder_only, first_ladder_season, last_ladder_season,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableRunewords+` (name, display_name, complete, ladder_only, first_ladder_season, last_ladder_season,
 			valid_item_types, excluded_item_types, runes, properties, image_url)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (name) DO UPDATE SET
@@ -311,7 +378,7 @@ func (r *Repository) UpsertRuneword(ctx context.Context, rw *Runeword) error {
 			excluded_item_types = EXCLUDED.excluded_item_types,
 			runes = EXCLUDED.runes,
 			properties = EXCLUDED.properties,
-			image_url = COALESCE(EXCLUDED.image_url, d2.runewords.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableRunewords+`.image_url),
 			updated_at = NOW()`,
 		rw.Name, rw.DisplayName, rw.Complete, rw.LadderOnly, rw.FirstLadderSeason, rw.LastLadderSeason,
 		string(validTypesJSON), string(excludedTypesJSON), string(runesJSON), string(propsJSON), nullString(rw.ImageURL))
@@ -321,7 +388,7 @@ func (r *Repository) UpsertRuneword(ctx context.Context, rw *Runeword) error {
 // Rune operations
 func (r *Repository) RuneExists(ctx context.Context, code string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.runes WHERE code = $1)", code).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableRunes+" WHERE code = $1)", code).Scan(&exists)
 	return exists, err
 }
 
@@ -329,8 +396,8 @@ func (r *Repository) UpsertRune(ctx context.Context, rn *Rune) error {
 	weaponJSON, _ := json.Marshal(rn.WeaponMods)
 	helmJSON, _ := json.Marshal(rn.HelmMods)
 	shieldJSON, _ := json.Marshal(rn.ShieldMods)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.runes (code, name, rune_number, level, level_req, weapon_mods, helm_mods, shield_mods, inv_file, image_url, cost)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableRunes+` (code, name, rune_number, level, level_req, weapon_mods, helm_mods, shield_mods, inv_file, image_url, cost)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (code) DO UPDATE SET
 			name = EXCLUDED.name,
@@ -341,8 +408,9 @@ func (r *Repository) UpsertRune(ctx context.Context, rn *Rune) error {
 			helm_mods = EXCLUDED.helm_mods,
 			shield_mods = EXCLUDED.shield_mods,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.runes.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableRunes+`.image_url),
 			cost = EXCLUDED.cost,
+			enabled = true,
 			updated_at = NOW()`,
 		rn.Code, rn.Name, rn.RuneNumber, rn.Level, rn.LevelReq, string(weaponJSON), string(helmJSON), string(shieldJSON),
 		nullString(rn.InvFile), nullString(rn.ImageURL), rn.Cost)
@@ -352,7 +420,7 @@ func (r *Repository) UpsertRune(ctx context.Context, rn *Rune) error {
 // Gem operations
 func (r *Repository) GemExists(ctx context.Context, code string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM d2.gems WHERE code = $1)", code).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM "+tableGems+" WHERE code = $1)", code).Scan(&exists)
 	return exists, err
 }
 
@@ -360,21 +428,23 @@ func (r *Repository) UpsertGem(ctx context.Context, g *Gem) error {
 	weaponJSON, _ := json.Marshal(g.WeaponMods)
 	helmJSON, _ := json.Marshal(g.HelmMods)
 	shieldJSON, _ := json.Marshal(g.ShieldMods)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.gems (code, name, gem_type, quality, weapon_mods, helm_mods, shield_mods, transform, inv_file, image_url)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableGems+` (code, name, gem_type, quality, is_skull, weapon_mods, helm_mods, shield_mods, transform, inv_file, image_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (code) DO UPDATE SET
 			name = EXCLUDED.name,
 			gem_type = EXCLUDED.gem_type,
 			quality = EXCLUDED.quality,
+			is_skull = EXCLUDED.is_skull,
 			weapon_mods = EXCLUDED.weapon_mods,
 			helm_mods = EXCLUDED.helm_mods,
 			shield_mods = EXCLUDED.shield_mods,
 			transform = EXCLUDED.transform,
 			inv_file = EXCLUDED.inv_file,
-			image_url = COALESCE(EXCLUDED.image_url, d2.gems.image_url),
+			image_url = COALESCE(EXCLUDED.image_url, `+tableGems+`.image_url),
+			enabled = true,
 			updated_at = NOW()`,
-		g.Code, g.Name, g.GemType, g.Quality, string(weaponJSON), string(helmJSON), string(shieldJSON),
+		g.Code, g.Name, g.GemType, g.Quality, g.IsSkull, string(weaponJSON), string(helmJSON), string(shieldJSON),
 		g.Transform, nullString(g.InvFile), nullString(g.ImageURL))
 	return err
 }
@@ -383,8 +453,8 @@ func (r *Repository) UpsertGem(ctx context.Context, g *Gem) error {
 
 // UpsertStat inserts or updates a stat in the registry
 func (r *Repository) UpsertStat(ctx context.Context, s *Stat) error {
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.stats (code, name, display_text, category, is_variable, is_parametric, aliases, sort_order)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableStats+` (code, name, display_text, category, is_variable, is_parametric, aliases, sort_order)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (code) DO UPDATE SET
 			name = EXCLUDED.name,
@@ -401,10 +471,10 @@ func (r *Repository) UpsertStat(ctx context.Context, s *Stat) error {
 
 // GetAllStats returns all stats ordered by category and sort_order
 func (r *Repository) GetAllStats(ctx context.Context) ([]Stat, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, code, name, display_text, category, is_variable, is_parametric,
 			COALESCE(aliases, '{}'), sort_order, created_at, updated_at
-		FROM d2.stats
+		FROM `+tableStats+`
 		ORDER BY sort_order, category, name`)
 	if err != nil {
 		return nil, err
@@ -426,10 +496,10 @@ func (r *Repository) GetAllStats(ctx context.Context) ([]Stat, error) {
 // GetStatByCode returns a stat by its code
 func (r *Repository) GetStatByCode(ctx context.Context, code string) (*Stat, error) {
 	var s Stat
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT id, code, name, display_text, category, is_variable, is_parametric,
 			COALESCE(aliases, '{}'), sort_order, created_at, updated_at
-		FROM d2.stats WHERE code = $1`, code).Scan(
+		FROM `+tableStats+` WHERE code = $1`, code).Scan(
 		&s.ID, &s.Code, &s.Name, &s.DisplayText, &s.Category,
 		&s.IsVariable, &s.IsParametric, &s.Aliases, &s.SortOrder, &s.CreatedAt, &s.UpdatedAt)
 	if err != nil {
@@ -440,7 +510,7 @@ func (r *Repository) GetStatByCode(ctx context.Context, code string) (*Stat, err
 
 // GetAllStatCodes returns all existing stat codes as a set
 func (r *Repository) GetAllStatCodes(ctx context.Context) (map[string]bool, error) {
-	rows, err := r.pool.Query(ctx, `SELECT code FROM d2.stats`)
+	rows, err := r.db.Query(ctx, `SELECT code FROM `+tableStats)
 	if err != nil {
 		return nil, err
 	}
@@ -487,20 +557,22 @@ func (r *Repository) UpdateItemBaseVariants(ctx context.Context, code, normalCod
 	setClauses = append(setClauses, "updated_at = NOW()")
 	args = append(args, code)
 
-	query := fmt.Sprintf("UPDATE d2.item_bases SET %s WHERE code = $%d",
+	query := fmt.Sprintf("UPDATE "+tableItemBases+" SET %s WHERE code = $%d",
 		strings.Join(setClauses, ", "), idx)
-	_, err := r.pool.Exec(ctx, query, args...)
+	_, err := r.db.Exec(ctx, query, args...)
 	return err
 }
 
-// GetBasesForRunewordByTypeTags returns base items that match the given type tags and have enough sockets
+// GetBasesForRunewordByTypeTags returns base items that match the given type tags and have enough sockets.
+// The socket filter mirrors CanSocketRuneword: max sockets at least the rune count.
 func (r *Repository) GetBasesForRunewordByTypeTags(ctx context.Context, typeTags []string, minSockets int) ([]ItemBaseForRuneword, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, code, name, item_type, COALESCE(item_type2, ''), category, max_sockets
-		FROM d2.item_bases
+		FROM `+tableItemBases+`
 		WHERE max_sockets >= $1
 		  AND type_tags && $2::text[]
-		  AND spawnable = true`, minSockets, typeTags)
+		  AND spawnable = true
+		  AND placeholder IS NOT TRUE`, minSockets, typeTags)
 	if err != nil {
 		return nil, err
 	}
@@ -549,8 +621,8 @@ type RunewordWithRunes struct {
 
 // GetUniqueItemsWithoutImages returns unique items that don't have images
 func (r *Repository) GetUniqueItemsWithoutImages(ctx context.Context) ([]ItemWithoutImage, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, name FROM d2.unique_items
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name FROM `+tableUniqueItems+`
 		WHERE image_url IS NULL OR image_url = ''
 		ORDER BY id`)
 	if err != nil {
@@ -572,8 +644,8 @@ func (r *Repository) GetUniqueItemsWithoutImages(ctx context.Context) ([]ItemWit
 
 // GetSetItemsWithoutImages returns set items that don't have images
 func (r *Repository) GetSetItemsWithoutImages(ctx context.Context) ([]ItemWithoutImage, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, name FROM d2.set_items
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name FROM `+tableSetItems+`
 		WHERE image_url IS NULL OR image_url = ''
 		ORDER BY id`)
 	if err != nil {
@@ -595,8 +667,8 @@ func (r *Repository) GetSetItemsWithoutImages(ctx context.Context) ([]ItemWithou
 
 // GetItemBasesWithoutImages returns item bases that don't have images
 func (r *Repository) GetItemBasesWithoutImages(ctx context.Context) ([]ItemWithoutImage, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT code, name FROM d2.item_bases
+	rows, err := r.db.Query(ctx, `
+		SELECT code, name FROM `+tableItemBases+`
 		WHERE (image_url IS NULL OR image_url = '')
 		ORDER BY code`)
 	if err != nil {
@@ -618,8 +690,8 @@ func (r *Repository) GetItemBasesWithoutImages(ctx context.Context) ([]ItemWitho
 
 // GetRunesWithoutImages returns runes that don't have images
 func (r *Repository) GetRunesWithoutImages(ctx context.Context) ([]ItemWithoutImage, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, code, name FROM d2.runes
+	rows, err := r.db.Query(ctx, `
+		SELECT id, code, name FROM `+tableRunes+`
 		WHERE image_url IS NULL OR image_url = ''
 		ORDER BY id`)
 	if err != nil {
@@ -641,8 +713,8 @@ func (r *Repository) GetRunesWithoutImages(ctx context.Context) ([]ItemWithoutIm
 
 // GetGemsWithoutImages returns gems that don't have images
 func (r *Repository) GetGemsWithoutImages(ctx context.Context) ([]ItemWithoutImage, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, code, name FROM d2.gems
+	rows, err := r.db.Query(ctx, `
+		SELECT id, code, name FROM `+tableGems+`
 		WHERE image_url IS NULL OR image_url = ''
 		ORDER BY id`)
 	if err != nil {
@@ -662,57 +734,143 @@ func (r *Repository) GetGemsWithoutImages(ctx context.Context) ([]ItemWithoutIma
 	return items, rows.Err()
 }
 
+// imageTables lists the tables with image_url and thumb_url columns
+var imageTables = []string{"item_bases", "unique_items", "set_items", "runewords", "runes", "gems"}
+
+// SetThumbnailURLs stores thumbnail URLs on every row whose image_url is a
+// key of thumbs. Returns the number of rows updated.
+func (r *Repository) SetThumbnailURLs(ctx context.Context, thumbs map[string]string) (int64, error) {
+	if len(thumbs) == 0 {
+		return 0, nil
+	}
+
+	images := make([]string, 0, len(thumbs))
+	thumbURLs := make([]string, 0, len(thumbs))
+	for image, thumb := range thumbs {
+		images = append(images, image)
+		thumbURLs = append(thumbURLs, thumb)
+	}
+
+	var total int64
+	for _, table := range imageTables {
+		tag, err := r.db.Exec(ctx, fmt.Sprintf(`
+			UPDATE %s t SET thumb_url = v.thumb_url
+			FROM unnest($1::text[], $2::text[]) AS v(image_url, thumb_url)
+			WHERE t.image_url = v.image_url
+				AND t.thumb_url IS DISTINCT FROM v.thumb_url`, QualifiedTable(table)),
+			images, thumbURLs)
+		if err != nil {
+			return total, fmt.Errorf("update %s thumbnails failed: %w", table, err)
+		}
+		total += tag.RowsAffected()
+	}
+	return total, nil
+}
+
 // UpdateUniqueItemImageURL updates the image URL for a unique item
 func (r *Repository) UpdateUniqueItemImageURL(ctx context.Context, id int, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.unique_items SET image_url = $1, updated_at = NOW() WHERE id = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableUniqueItems+` SET image_url = $1, updated_at = NOW() WHERE id = $2`,
 		url, id)
 	return err
 }
 
+// ImageURLUpdate sets the image of one catalog row by item type and ID
+type ImageURLUpdate struct {
+	Type string // "unique", "set", "runeword", "rune", "gem", "base" or "quest"
+	ID   int
+	URL  string
+}
+
+// imageTableByType maps item types to the table holding their image
+var imageTableByType = map[string]string{
+	"unique":   "unique_items",
+	"set":      "set_items",
+	"runeword": "runewords",
+	"rune":     "runes",
+	"gem":      "gems",
+	"base":     "item_bases",
+	"quest":    "item_bases",
+}
+
+// IsImageItemType reports whether UpdateImageURLs accepts the item type
+func IsImageItemType(itemType string) bool {
+	_, ok := imageTableByType[itemType]
+	return ok
+}
+
+// UpdateImageURLs sets image URLs in a single transaction and clears the
+// rows' thumbnails, which belonged to the old images. found[i] reports
+// whether updates[i] matched a row; unmatched rows don't abort the
+// transaction. Any query error rolls back every update.
+func (r *Repository) UpdateImageURLs(ctx context.Context, updates []ImageURLUpdate) ([]bool, error) {
+	found := make([]bool, len(updates))
+	err := r.InTx(ctx, func(tx *Repository) error {
+		for i, u := range updates {
+			table, ok := imageTableByType[u.Type]
+			if !ok {
+				return fmt.Errorf("unknown item type %q", u.Type)
+			}
+			tag, err := tx.db.Exec(ctx, fmt.Sprintf(`
+				UPDATE %s SET image_url = $1, thumb_url = NULL, updated_at = NOW()
+				WHERE id = $2`, QualifiedTable(table)),
+				u.URL, u.ID)
+			if err != nil {
+				return fmt.Errorf("update %s %d image failed: %w", u.Type, u.ID, err)
+			}
+			found[i] = tag.RowsAffected() > 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
 // UpdateSetItemImageURL updates the image URL for a set item
 func (r *Repository) UpdateSetItemImageURL(ctx context.Context, id int, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.set_items SET image_url = $1, updated_at = NOW() WHERE id = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableSetItems+` SET image_url = $1, updated_at = NOW() WHERE id = $2`,
 		url, id)
 	return err
 }
 
 // UpdateItemBaseImageURL updates the image URL for an item base
 func (r *Repository) UpdateItemBaseImageURL(ctx context.Context, code string, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.item_bases SET image_url = $1, updated_at = NOW() WHERE code = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableItemBases+` SET image_url = $1, updated_at = NOW() WHERE code = $2`,
 		url, code)
 	return err
 }
 
 // UpdateItemBaseIconVariants updates the icon variants for an item base
 func (r *Repository) UpdateItemBaseIconVariants(ctx context.Context, code string, variants []string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.item_bases SET icon_variants = $1, updated_at = NOW() WHERE code = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableItemBases+` SET icon_variants = $1, updated_at = NOW() WHERE code = $2`,
 		variants, code)
 	return err
 }
 
 // UpdateRuneImageURL updates the image URL for a rune
 func (r *Repository) UpdateRuneImageURL(ctx context.Context, id int, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.runes SET image_url = $1, updated_at = NOW() WHERE id = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableRunes+` SET image_url = $1, updated_at = NOW() WHERE id = $2`,
 		url, id)
 	return err
 }
 
 // UpdateGemImageURL updates the image URL for a gem
 func (r *Repository) UpdateGemImageURL(ctx context.Context, id int, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.gems SET image_url = $1, updated_at = NOW() WHERE id = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableGems+` SET image_url = $1, updated_at = NOW() WHERE id = $2`,
 		url, id)
 	return err
 }
 
 // GetRuneCodeToNameMap returns a mapping of rune codes to rune names (e.g., "r30" -> "Ber")
 func (r *Repository) GetRuneCodeToNameMap(ctx context.Context) (map[string]string, error) {
-	rows, err := r.pool.Query(ctx, `SELECT code, name FROM d2.runes ORDER BY code`)
+	rows, err := r.db.Query(ctx, `SELECT code, name FROM `+tableRunes+` ORDER BY code`)
 	if err != nil {
 		return nil, err
 	}
@@ -737,9 +895,9 @@ func (r *Repository) GetRuneCodeToNameMap(ctx context.Context) (map[string]strin
 
 // GetRunewordsWithoutImages returns runewords that don't have images yet
 func (r *Repository) GetRunewordsWithoutImages(ctx context.Context) ([]RunewordWithRunes, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, name, display_name, runes, COALESCE(image_url, '')
-		FROM d2.runewords
+		FROM `+tableRunewords+`
 		WHERE image_url IS NULL OR image_url = ''
 		ORDER BY id`)
 	if err != nil {
@@ -752,9 +910,9 @@ func (r *Repository) GetRunewordsWithoutImages(ctx context.Context) ([]RunewordW
 
 // GetAllRunewords returns all runewords (for force regeneration)
 func (r *Repository) GetAllRunewords(ctx context.Context) ([]RunewordWithRunes, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, name, display_name, runes, COALESCE(image_url, '')
-		FROM d2.runewords
+		FROM `+tableRunewords+`
 		ORDER BY id`)
 	if err != nil {
 		return nil, err
@@ -782,10 +940,19 @@ func scanRunewords(rows interface{ Next() bool; Scan(dest ...interface{}) error;
 	return runewords, rows.Err()
 }
 
+// UpdateRunewordRunes overwrites the stored rune sequence for a runeword
+func (r *Repository) UpdateRunewordRunes(ctx context.Context, id int, runes []string) error {
+	runesJSON, _ := json.Marshal(runes)
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableRunewords+` SET runes = $1, updated_at = NOW() WHERE id = $2`,
+		string(runesJSON), id)
+	return err
+}
+
 // UpdateRunewordImageURL updates the image URL for a runeword
 func (r *Repository) UpdateRunewordImageURL(ctx context.Context, id int, url string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.runewords SET image_url = $1, updated_at = NOW() WHERE id = $2`,
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableRunewords+` SET image_url = $1, updated_at = NOW() WHERE id = $2`,
 		url, id)
 	return err
 }
@@ -794,25 +961,71 @@ func (r *Repository) UpdateRunewordImageURL(ctx context.Context, id int, url str
 
 // ClearRunewordBases removes all runeword base mappings
 func (r *Repository) ClearRunewordBases(ctx context.Context) error {
-	_, err := r.pool.Exec(ctx, `DELETE FROM d2.runeword_bases`)
+	_, err := r.db.Exec(ctx, `DELETE FROM `+tableRunewordBases)
 	return err
 }
 
 // InsertRunewordBase inserts a runeword-base mapping
 func (r *Repository) InsertRunewordBase(ctx context.Context, rb *RunewordBase) error {
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.runeword_bases (runeword_id, item_base_id, item_base_code, item_base_name, category, max_sockets, required_sockets)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableRunewordBases+` (runeword_id, item_base_id, item_base_code, item_base_name, category, max_sockets, required_sockets)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (runeword_id, item_base_id) DO NOTHING`,
 		rb.RunewordID, rb.ItemBaseID, rb.ItemBaseCode, rb.ItemBaseName, rb.Category, rb.MaxSockets, rb.RequiredSockets)
 	return err
 }
 
+// ComputeBasesForRuneword replaces one runeword's base mappings using the
+// same type_tags and socket matching as the importer, returning how many
+// bases were found. Incomplete runewords and ones without valid item types
+// are left with none.
+func (r *Repository) ComputeBasesForRuneword(ctx context.Context, id int) (int, error) {
+	rw, err := r.GetRuneword(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = r.InTx(ctx, func(tx *Repository) error {
+		if _, err := tx.db.Exec(ctx, `DELETE FROM `+tableRunewordBases+` WHERE runeword_id = $1`, id); err != nil {
+			return err
+		}
+		if !rw.Complete || len(rw.ValidItemTypes) == 0 {
+			return nil
+		}
+
+		bases, err := tx.GetBasesForRunewordByTypeTags(ctx, rw.ValidItemTypes, len(rw.Runes))
+		if err != nil {
+			return err
+		}
+		for _, base := range bases {
+			rb := &RunewordBase{
+				RunewordID:      rw.ID,
+				ItemBaseID:      base.ID,
+				ItemBaseCode:    base.Code,
+				ItemBaseName:    base.Name,
+				Category:        base.Category,
+				MaxSockets:      base.MaxSockets,
+				RequiredSockets: len(rw.Runes),
+			}
+			if err := tx.InsertRunewordBase(ctx, rb); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("compute runeword bases failed: %w", err)
+	}
+	return count, nil
+}
+
 // GetBasesForRuneword returns all valid base items for a runeword
 func (r *Repository) GetBasesForRuneword(ctx context.Context, runewordID int) ([]RunewordBase, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, runeword_id, item_base_id, item_base_code, item_base_name, category, max_sockets, required_sockets, created_at
-		FROM d2.runeword_bases
+		FROM `+tableRunewordBases+`
 		WHERE runeword_id = $1
 		ORDER BY category, item_base_name`, runewordID)
 	if err != nil {
@@ -831,6 +1044,46 @@ func (r *Repository) GetBasesForRuneword(ctx context.Context, runewordID int) ([
 	return bases, rows.Err()
 }
 
+// RunewordForBase is a runeword that a base item can host
+type RunewordForBase struct {
+	RunewordID      int
+	Name            string
+	DisplayName     string
+	LadderOnly      bool
+	Runes           []string // Rune codes in socketing order
+	RequiredSockets int
+	ImageURL        string
+}
+
+// GetRunewordsForBase returns the runewords a base item can host, fewest
+// required sockets first
+func (r *Repository) GetRunewordsForBase(ctx context.Context, baseID int) ([]RunewordForBase, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT rw.id, rw.name, rw.display_name, rw.ladder_only, rw.runes, COALESCE(rw.image_url, ''), rb.required_sockets
+		FROM `+tableRunewordBases+` rb
+		JOIN `+tableRunewords+` rw ON rw.id = rb.runeword_id
+		WHERE rb.item_base_id = $1
+		ORDER BY rb.required_sockets, rw.display_name`, baseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runewords []RunewordForBase
+	for rows.Next() {
+		var rw RunewordForBase
+		var runesJSON []byte
+		if err := rows.Scan(&rw.RunewordID, &rw.Name, &rw.DisplayName, &rw.LadderOnly, &runesJSON, &rw.ImageURL, &rw.RequiredSockets); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(runesJSON, &rw.Runes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal runes for %s: %w", rw.Name, err)
+		}
+		runewords = append(runewords, rw)
+	}
+	return runewords, rows.Err()
+}
+
 // ItemTypeWithEquiv holds item type info with parent types for hierarchy building
 type ItemTypeWithEquiv struct {
 	Code   string
@@ -840,9 +1093,9 @@ type ItemTypeWithEquiv struct {
 
 // GetAllItemTypesWithEquiv returns all item types with their equiv relationships
 func (r *Repository) GetAllItemTypesWithEquiv(ctx context.Context) ([]ItemTypeWithEquiv, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT code, COALESCE(equiv1, ''), COALESCE(equiv2, '')
-		FROM d2.item_types`)
+		FROM `+tableItemTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -872,9 +1125,9 @@ type ItemBaseForRuneword struct {
 
 // GetAllItemBasesForRunewordMatching returns all base items with socket info
 func (r *Repository) GetAllItemBasesForRunewordMatching(ctx context.Context) ([]ItemBaseForRuneword, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, code, name, item_type, COALESCE(item_type2, ''), category, max_sockets
-		FROM d2.item_bases
+		FROM `+tableItemBases+`
 		WHERE max_sockets > 0`)
 	if err != nil {
 		return nil, err
@@ -903,9 +1156,9 @@ type RunewordForMatching struct {
 
 // GetAllRunewordsForMatching returns all runewords with their type requirements
 func (r *Repository) GetAllRunewordsForMatching(ctx context.Context) ([]RunewordForMatching, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, name, valid_item_types, excluded_item_types, runes
-		FROM d2.runewords
+		FROM `+tableRunewords+`
 		WHERE complete = true`)
 	if err != nil {
 		return nil, err
@@ -936,10 +1189,11 @@ func (r *Repository) GetAllRunewordsForMatching(ctx context.Context) ([]Runeword
 
 // RuneInfo holds basic rune display info
 type RuneInfo struct {
-	ID       int
-	Code     string
-	Name     string
-	ImageURL string
+	ID         int
+	Code       string
+	Name       string
+	RuneNumber int
+	ImageURL   string
 }
 
 // GetRunesByCodes returns rune info for the given codes
@@ -948,9 +1202,9 @@ func (r *Repository) GetRunesByCodes(ctx context.Context, codes []string) (map[s
 		return make(map[string]RuneInfo), nil
 	}
 
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, code, name, COALESCE(image_url, '')
-		FROM d2.runes
+	rows, err := r.db.Query(ctx, `
+		SELECT id, code, name, rune_number, COALESCE(image_url, '')
+		FROM `+tableRunes+`
 		WHERE code = ANY($1)`, codes)
 	if err != nil {
 		return nil, err
@@ -960,7 +1214,7 @@ func (r *Repository) GetRunesByCodes(ctx context.Context, codes []string) (map[s
 	result := make(map[string]RuneInfo)
 	for rows.Next() {
 		var ri RuneInfo
-		if err := rows.Scan(&ri.ID, &ri.Code, &ri.Name, &ri.ImageURL); err != nil {
+		if err := rows.Scan(&ri.ID, &ri.Code, &ri.Name, &ri.RuneNumber, &ri.ImageURL); err != nil {
 			return nil, err
 		}
 		result[ri.Code] = ri
@@ -980,9 +1234,9 @@ func (r *Repository) GetItemTypesByCodes(ctx context.Context, codes []string) (m
 		return make(map[string]ItemTypeInfo), nil
 	}
 
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT code, name
-		FROM d2.item_types
+		FROM `+tableItemTypes+`
 		WHERE code = ANY($1)`, codes)
 	if err != nil {
 		return nil, err
@@ -1002,7 +1256,7 @@ func (r *Repository) GetItemTypesByCodes(ctx context.Context, codes []string) (m
 
 // GetAllItemBaseNameToCode returns a mapping of base item names to codes (e.g., "Kris" -> "kri")
 func (r *Repository) GetAllItemBaseNameToCode(ctx context.Context) (map[string]string, error) {
-	rows, err := r.pool.Query(ctx, `SELECT name, code FROM d2.item_bases`)
+	rows, err := r.db.Query(ctx, `SELECT name, code FROM `+tableItemBases)
 	if err != nil {
 		return nil, err
 	}
@@ -1021,7 +1275,7 @@ func (r *Repository) GetAllItemBaseNameToCode(ctx context.Context) (map[string]s
 
 // GetRuneNameToCodeMap returns a mapping of rune names to codes (e.g., "Shael" -> "r13")
 func (r *Repository) GetRuneNameToCodeMap(ctx context.Context) (map[string]string, error) {
-	rows, err := r.pool.Query(ctx, `SELECT name, code FROM d2.runes`)
+	rows, err := r.db.Query(ctx, `SELECT name, code FROM `+tableRunes)
 	if err != nil {
 		return nil, err
 	}
@@ -1045,32 +1299,32 @@ func (r *Repository) GetRuneNameToCodeMap(ctx context.Context) (map[string]strin
 }
 
 
-// GetNamesWithImages returns normalized names that have a non-empty image_url
-func (r *Repository) GetNamesWithImages(ctx context.Context, table, nameColumn string) (map[string]bool, error) {
-	query := fmt.Sprintf("SELECT %s FROM d2.%s WHERE image_url IS NOT NULL AND image_url != ''", nameColumn, table)
-	rows, err := r.pool.Query(ctx, query)
+// GetNamesWithImages returns the names of rows that have a non-empty image_url
+func (r *Repository) GetNamesWithImages(ctx context.Context, table, nameColumn string) ([]string, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE image_url IS NOT NULL AND image_url != ''", nameColumn, QualifiedTable(table))
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	result := make(map[string]bool)
+	var names []string
 	for rows.Next() {
 		var name string
 		if err := rows.Scan(&name); err != nil {
 			return nil, err
 		}
-		result[NormalizeItemName(name)] = true
+		names = append(names, name)
 	}
-	return result, rows.Err()
+	return names, rows.Err()
 }
 
 
 // GetMaxIndexID returns the maximum index_id from a table
 func (r *Repository) GetMaxIndexID(ctx context.Context, table string) (int, error) {
-	query := fmt.Sprintf("SELECT COALESCE(MAX(index_id), 0) FROM d2.%s", table)
+	query := fmt.Sprintf("SELECT COALESCE(MAX(index_id), 0) FROM %s", QualifiedTable(table))
 	var maxID int
-	err := r.pool.QueryRow(ctx, query).Scan(&maxID)
+	err := r.db.QueryRow(ctx, query).Scan(&maxID)
 	return maxID, err
 }
 
@@ -1079,9 +1333,9 @@ func (r *Repository) GetMaxIndexID(ctx context.Context, table string) (int, erro
 // GetProfile retrieves a profile by ID
 func (r *Repository) GetProfile(ctx context.Context, id string) (*Profile, error) {
 	var p Profile
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT id, is_admin, created_at, updated_at
-		FROM d2.profiles WHERE id = $1`, id).Scan(
+		FROM `+tableProfiles+` WHERE id = $1`, id).Scan(
 		&p.ID, &p.IsAdmin, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
@@ -1093,8 +1347,8 @@ func (r *Repository) GetProfile(ctx context.Context, id string) (*Profile, error
 // IsAdmin checks if a user is an admin
 func (r *Repository) IsAdmin(ctx context.Context, id string) (bool, error) {
 	var isAdmin bool
-	err := r.pool.QueryRow(ctx, `
-		SELECT COALESCE(is_admin, false) FROM d2.profiles WHERE id = $1`, id).Scan(&isAdmin)
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(is_admin, false) FROM `+tableProfiles+` WHERE id = $1`, id).Scan(&isAdmin)
 	if err != nil {
 		return false, err
 	}
@@ -1105,9 +1359,9 @@ func (r *Repository) IsAdmin(ctx context.Context, id string) (bool, error) {
 
 // GetAllClasses retrieves all classes
 func (r *Repository) GetAllClasses(ctx context.Context) ([]Class, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, name, skill_suffix, skill_trees, created_at, updated_at
-		FROM d2.classes ORDER BY name`)
+		FROM `+tableClasses+` ORDER BY name`)
 	if err != nil {
 		return nil, err
 	}
@@ -1128,13 +1382,37 @@ func (r *Repository) GetAllClasses(ctx context.Context) ([]Class, error) {
 	return classes, rows.Err()
 }
 
+// SkillNames returns the skill names affix text resolves skill params with.
+// They use the built-in names until LoadSkillNames succeeds.
+func (r *Repository) SkillNames() *SkillNames {
+	return r.skills
+}
+
+// LoadSkillNames loads the class skill trees into SkillNames. Unless reload
+// is set, it does nothing once a load has succeeded. On failure the
+// previously loaded names stay in use.
+func (r *Repository) LoadSkillNames(ctx context.Context, reload bool) error {
+	r.skills.loadMu.Lock()
+	defer r.skills.loadMu.Unlock()
+	if !reload && r.skills.Loaded() {
+		return nil
+	}
+
+	classes, err := r.GetAllClasses(ctx)
+	if err != nil {
+		return fmt.Errorf("load skill names failed: %w", err)
+	}
+	r.skills.Set(classes)
+	return nil
+}
+
 // GetClass retrieves a class by ID
 func (r *Repository) GetClass(ctx context.Context, id string) (*Class, error) {
 	var c Class
 	var skillTreesJSON []byte
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT id, name, skill_suffix, skill_trees, created_at, updated_at
-		FROM d2.classes WHERE id = $1`, id).Scan(
+		FROM `+tableClasses+` WHERE id = $1`, id).Scan(
 		&c.ID, &c.Name, &c.SkillSuffix, &skillTreesJSON, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
@@ -1149,8 +1427,8 @@ func (r *Repository) GetClass(ctx context.Context, id string) (*Class, error) {
 // UpsertClass inserts or updates a class
 func (r *Repository) UpsertClass(ctx context.Context, c *Class) error {
 	skillTreesJSON, _ := json.Marshal(c.SkillTrees)
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO d2.classes (id, name, skill_suffix, skill_trees)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO `+tableClasses+` (id, name, skill_suffix, skill_trees)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (id) DO UPDATE SET
 			name = EXCLUDED.name,
@@ -1165,8 +1443,8 @@ func (r *Repository) UpsertClass(ctx context.Context, c *Class) error {
 
 // GetAllQuestItems retrieves all quest items
 func (r *Repository) GetAllQuestItems(ctx context.Context) ([]ItemBase, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id FROM d2.item_bases WHERE quest_item = true ORDER BY name`)
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM `+tableItemBases+` WHERE quest_item = true ORDER BY name`)
 	if err != nil {
 		return nil, err
 	}
@@ -1190,8 +1468,8 @@ func (r *Repository) GetAllQuestItems(ctx context.Context) ([]ItemBase, error) {
 // CreateQuestItem inserts a new quest item
 func (r *Repository) CreateQuestItem(ctx context.Context, ib *ItemBase) (int, error) {
 	var id int
-	err := r.pool.QueryRow(ctx, `
-		INSERT INTO d2.item_bases (code, name, item_type, category, quest_item, description, image_url)
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO `+tableItemBases+` (code, name, item_type, category, quest_item, description, image_url)
 		VALUES ($1, $2, 'ques', 'misc', true, $3, $4)
 		RETURNING id`,
 		ib.Code, ib.Name, nullString(ib.Description), nullString(ib.ImageURL)).Scan(&id)
@@ -1200,8 +1478,8 @@ func (r *Repository) CreateQuestItem(ctx context.Context, ib *ItemBase) (int, er
 
 // DeleteQuestItem deletes a quest item by ID (only if it is a quest item)
 func (r *Repository) DeleteQuestItem(ctx context.Context, id int) error {
-	result, err := r.pool.Exec(ctx, `
-		DELETE FROM d2.item_bases WHERE id = $1 AND quest_item = true`, id)
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM `+tableItemBases+` WHERE id = $1 AND quest_item = true`, id)
 	if err != nil {
 		return err
 	}
@@ -1211,13 +1489,33 @@ func (r *Repository) DeleteQuestItem(ctx context.Context, id int) error {
 	return nil
 }
 
+// ErrRunewordNotFound is returned by DeleteRuneword when no runeword has the ID
+var ErrRunewordNotFound = errors.New("runeword not found")
+
+// DeleteRuneword deletes a runeword by ID along with its base mappings
+func (r *Repository) DeleteRuneword(ctx context.Context, id int) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		if _, err := tx.db.Exec(ctx, `DELETE FROM `+tableRunewordBases+` WHERE runeword_id = $1`, id); err != nil {
+			return err
+		}
+		result, err := tx.db.Exec(ctx, `DELETE FROM `+tableRunewords+` WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return ErrRunewordNotFound
+		}
+		return nil
+	})
+}
+
 // Admin update operations
 
 // UpdateUniqueItemFields updates specific fields on a unique item
 func (r *Repository) UpdateUniqueItemFields(ctx context.Context, id int, item *UniqueItem) error {
 	propsJSON, _ := json.Marshal(item.Properties)
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.unique_items SET
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableUniqueItems+` SET
 			name = $2, base_code = $3, level_req = $4, ladder_only = $5,
 			properties = $6, image_url = COALESCE($7, image_url),
 			updated_at = NOW()
@@ -1231,8 +1529,8 @@ func (r *Repository) UpdateUniqueItemFields(ctx context.Context, id int, item *U
 func (r *Repository) UpdateSetItemFields(ctx context.Context, id int, item *SetItem) error {
 	propsJSON, _ := json.Marshal(item.Properties)
 	bonusJSON, _ := json.Marshal(item.BonusProperties)
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.set_items SET
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableSetItems+` SET
 			name = $2, set_name = $3, base_code = $4, level_req = $5,
 			properties = $6, bonus_properties = $7,
 			image_url = COALESCE($8, image_url),
@@ -1248,8 +1546,8 @@ func (r *Repository) UpdateRunewordFields(ctx context.Context, id int, item *Run
 	validTypesJSON, _ := json.Marshal(item.ValidItemTypes)
 	runesJSON, _ := json.Marshal(item.Runes)
 	propsJSON, _ := json.Marshal(item.Properties)
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.runewords SET
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableRunewords+` SET
 			name = $2, display_name = $3, ladder_only = $4,
 			valid_item_types = $5, runes = $6, properties = $7,
 			image_url = COALESCE($8, image_url),
@@ -1265,8 +1563,8 @@ func (r *Repository) UpdateRuneFields(ctx context.Context, id int, item *Rune) e
 	weaponJSON, _ := json.Marshal(item.WeaponMods)
 	helmJSON, _ := json.Marshal(item.HelmMods)
 	shieldJSON, _ := json.Marshal(item.ShieldMods)
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.runes SET
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableRunes+` SET
 			code = $2, name = $3, rune_number = $4, level_req = $5,
 			weapon_mods = $6, helm_mods = $7, shield_mods = $8,
 			image_url = COALESCE($9, image_url),
@@ -1282,22 +1580,22 @@ func (r *Repository) UpdateGemFields(ctx context.Context, id int, item *Gem) err
 	weaponJSON, _ := json.Marshal(item.WeaponMods)
 	helmJSON, _ := json.Marshal(item.HelmMods)
 	shieldJSON, _ := json.Marshal(item.ShieldMods)
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.gems SET
-			code = $2, name = $3, gem_type = $4, quality = $5,
-			weapon_mods = $6, helm_mods = $7, shield_mods = $8,
-			image_url = COALESCE($9, image_url),
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableGems+` SET
+			code = $2, name = $3, gem_type = $4, quality = $5, is_skull = $6,
+			weapon_mods = $7, helm_mods = $8, shield_mods = $9,
+			image_url = COALESCE($10, image_url),
 			updated_at = NOW()
 		WHERE id = $1`,
-		id, item.Code, item.Name, item.GemType, item.Quality,
+		id, item.Code, item.Name, item.GemType, item.Quality, item.IsSkull,
 		string(weaponJSON), string(helmJSON), string(shieldJSON), nullString(item.ImageURL))
 	return err
 }
 
 // UpdateItemBaseFields updates specific fields on a base item
 func (r *Repository) UpdateItemBaseFields(ctx context.Context, id int, item *ItemBase) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE d2.item_bases SET
+	_, err := r.db.Exec(ctx, `
+		UPDATE `+tableItemBases+` SET
 			code = $2, name = $3, category = $4, item_type = $5,
 			level_req = $6, str_req = $7, dex_req = $8,
 			min_ac = $9, max_ac = $10, min_dam = $11, max_dam = $12,
@@ -1314,3 +1612,128 @@ func (r *Repository) UpdateItemBaseFields(ctx context.Context, id int, item *Ite
 		nullString(item.Description), nullString(item.ImageURL))
 	return err
 }
+
+// HidePlaceholderItems hides existing rows whose names match any of the given
+// case-insensitive regex patterns. Uniques are disabled, bases are marked
+// non-spawnable, and runewords are marked incomplete so they drop out of
+// listings and search. With keepBases, base rows are left alone: they were
+// imported marked as placeholders. Returns the total number of rows hidden.
+func (r *Repository) HidePlaceholderItems(ctx context.Context, patterns []string, keepBases bool) (int, error) {
+	if len(patterns) == 0 {
+		return 0, nil
+	}
+
+	queries := []string{
+		`UPDATE ` + tableUniqueItems + ` SET enabled = false, updated_at = NOW() WHERE enabled = true AND name ~* ANY($1)`,
+		`UPDATE ` + tableRunewords + ` SET complete = false, updated_at = NOW() WHERE complete = true AND display_name ~* ANY($1)`,
+	}
+	if !keepBases {
+		queries = append(queries, `UPDATE `+tableItemBases+` SET spawnable = false, updated_at = NOW() WHERE spawnable = true AND name ~* ANY($1)`)
+	}
+
+	total := 0
+	for _, q := range queries {
+		result, err := r.db.Exec(ctx, q, patterns)
+		if err != nil {
+			return total, fmt.Errorf("hide placeholder items failed: %w", err)
+		}
+		total += int(result.RowsAffected())
+	}
+	return total, nil
+}
+
+// purgeQueries soft-deletes every row of one item type, using the flag each
+// listing already filters on. A later import's upsert restores the flag; the
+// content hash is cleared so an incremental import doesn't skip that upsert.
+var purgeQueries = map[string]string{
+	"uniques":   `UPDATE ` + tableUniqueItems + ` SET enabled = false, content_hash = NULL, updated_at = NOW() WHERE enabled = true`,
+	"sets":      `UPDATE ` + tableSetItems + ` SET enabled = false, content_hash = NULL, updated_at = NOW() WHERE enabled IS NOT FALSE`,
+	"runewords": `UPDATE ` + tableRunewords + ` SET complete = false, content_hash = NULL, updated_at = NOW() WHERE complete = true`,
+	"runes":     `UPDATE ` + tableRunes + ` SET enabled = false, content_hash = NULL, updated_at = NOW() WHERE enabled IS NOT FALSE`,
+	"gems":      `UPDATE ` + tableGems + ` SET enabled = false, content_hash = NULL, updated_at = NOW() WHERE enabled IS NOT FALSE`,
+	"bases":     `UPDATE ` + tableItemBases + ` SET spawnable = false, content_hash = NULL, updated_at = NOW() WHERE spawnable = true AND quest_item IS NOT TRUE`,
+}
+
+// PurgeableItemTypes returns the item types accepted by PurgeItemType
+func PurgeableItemTypes() []string {
+	return []string{"uniques", "sets", "runewords", "runes", "gems", "bases"}
+}
+
+// PurgeItemType soft-deletes all rows of an item type so a targeted re-import
+// can repopulate it without stale rows. Returns the number of rows affected.
+func (r *Repository) PurgeItemType(ctx context.Context, itemType string) (int, error) {
+	sql, ok := purgeQueries[itemType]
+	if !ok {
+		return 0, fmt.Errorf("unknown item type: %s", itemType)
+	}
+	result, err := r.db.Exec(ctx, sql)
+	if err != nil {
+		return 0, fmt.Errorf("purge %s failed: %w", itemType, err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// itemTypeTable describes how an item type is keyed by the importer and which
+// flag hides it, for reconciling imports against the database.
+type itemTypeTable struct {
+	table   string
+	key     string // column the importer upserts by
+	name    string // display column
+	active  string // condition for rows currently visible
+	disable string // assignment that soft-deletes a row
+}
+
+var itemTypeTables = map[string]itemTypeTable{
+	"uniques":   {QualifiedTable("unique_items"), "name", "name", "enabled = true", "enabled = false"},
+	"sets":      {QualifiedTable("set_items"), "name", "name", "enabled IS NOT FALSE", "enabled = false"},
+	"runewords": {QualifiedTable("runewords"), "name", "display_name", "complete = true", "complete = false"},
+	"runes":     {QualifiedTable("runes"), "code", "name", "enabled IS NOT FALSE", "enabled = false"},
+	"gems":      {QualifiedTable("gems"), "code", "name", "enabled IS NOT FALSE", "enabled = false"},
+	"bases":     {QualifiedTable("item_bases"), "code", "name", "spawnable = true AND quest_item IS NOT TRUE", "spawnable = false"},
+}
+
+// GetActiveItemKeys returns the upsert key -> display name of every visible
+// row of an item type
+func (r *Repository) GetActiveItemKeys(ctx context.Context, itemType string) (map[string]string, error) {
+	t, ok := itemTypeTables[itemType]
+	if !ok {
+		return nil, fmt.Errorf("unknown item type: %s", itemType)
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`SELECT %s, %s FROM %s WHERE %s`, t.key, t.name, t.table, t.active))
+	if err != nil {
+		return nil, fmt.Errorf("get active %s failed: %w", itemType, err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]string)
+	for rows.Next() {
+		var key, name string
+		if err := rows.Scan(&key, &name); err != nil {
+			return nil, err
+		}
+		keys[key] = name
+	}
+	return keys, rows.Err()
+}
+
+// SoftDeleteItemsByKey hides the rows of an item type with the given upsert
+// keys. Their content hash is cleared so the next import restores any that
+// reappear, even when unchanged.
+func (r *Repository) SoftDeleteItemsByKey(ctx context.Context, itemType string, keys []string) (int, error) {
+	t, ok := itemTypeTables[itemType]
+	if !ok {
+		return 0, fmt.Errorf("unknown item type: %s", itemType)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.db.Exec(ctx,
+		fmt.Sprintf(`UPDATE %s SET %s, content_hash = NULL, updated_at = NOW() WHERE %s AND %s = ANY($1)`, t.table, t.disable, t.active, t.key),
+		keys)
+	if err != nil {
+		return 0, fmt.Errorf("soft delete %s failed: %w", itemType, err)
+	}
+	return int(result.RowsAffected()), nil
+}
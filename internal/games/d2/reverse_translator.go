@@ -11,6 +11,11 @@ import (
 // classSuffixRegex matches trailing class suffixes like "(Warlock only)", "(Amazon Only)", etc.
 var classSuffixRegex = regexp.MustCompile(`\s*\((Amazon|Sorceress|Necromancer|Paladin|Barbarian|Druid|Assassin|Warlock)(\s+[Oo]nly)?\)\s*$`)
 
+// easeRegex matches requirement reduction lines: "Requirements -20%",
+// "Requirements -(20-30)%" or "Requirements -20-30%", tolerating stray spaces
+// and the Unicode minus the HTML sometimes uses
+var easeRegex = regexp.MustCompile(`(?i)^Requirements\s*[-\x{2212}]\s*\(?\s*(\d+)(?:\s*-\s*(\d+))?\s*\)?\s*%$`)
+
 // ReverseTranslator converts display text back to Property structs
 type ReverseTranslator struct {
 	patterns         []reversePattern
@@ -78,6 +83,9 @@ func buildReversePattern(code, template string) *reversePattern {
 	// Escape regex special chars in the template
 	escaped := regexp.QuoteMeta(template)
 
+	// Reduction templates ("-{value}%") render ranges in parentheses
+	escaped = strings.Replace(escaped, `-\{value\}`, `-\(?\{value\}\)?`, 1)
+
 	// Collect placeholders found in the escaped string with their positions.
 	// We must sort by position so the groups array matches capture group order
 	// in the resulting regex, regardless of placeholder declaration order.
@@ -153,6 +161,10 @@ func (rt *ReverseTranslator) ReverseTranslate(displayText string) Property {
 		return prop
 	}
 
+	if prop, ok := tryEaseMatch(displayText); ok {
+		return prop
+	}
+
 	// Try each pattern
 	for _, p := range rt.patterns {
 		matches := p.regex.FindStringSubmatch(displayText)
@@ -206,6 +218,7 @@ func (rt *ReverseTranslator) ReverseTranslate(displayText string) Property {
 			continue
 		}
 
+		prop = NormalizeReductionSign(prop)
 		prop.DisplayText = displayText
 		return prop
 	}
@@ -214,6 +227,25 @@ func (rt *ReverseTranslator) ReverseTranslate(displayText string) Property {
 	return Property{Code: "raw", DisplayText: displayText}
 }
 
+// tryEaseMatch handles requirement reduction. The generic "Requirements
+// -{value}%" pattern only matches the exact rendering, and a missed line
+// would be stored as raw text instead of the ease stat.
+func tryEaseMatch(text string) (Property, bool) {
+	matches := easeRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return Property{}, false
+	}
+	min, _ := strconv.Atoi(matches[1])
+	max := min
+	if matches[2] != "" {
+		max, _ = strconv.Atoi(matches[2])
+	}
+	if min > max {
+		min, max = max, min
+	}
+	return Property{Code: "ease", Min: min, Max: max, DisplayText: text}, true
+}
+
 // tryPerLevelMatch handles the per-level display format:
 // "(X Per Character Level) Y-Z To Stat (Based On Character Level)"
 func (rt *ReverseTranslator) tryPerLevelMatch(text string) (Property, bool) {
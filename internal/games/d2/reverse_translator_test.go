@@ -0,0 +1,36 @@
+package d2
+
+import "testing"
+
+func TestReverseTranslateRequirementReduction(t *testing.T) {
+	rt := NewReverseTranslator()
+
+	tests := []struct {
+		text     string
+		min, max int
+	}{
+		{"Requirements -20%", 20, 20},
+		{"Requirements -(20-30)%", 20, 30},
+		{"Requirements -20-30%", 20, 30},
+		{"Requirements - 15 %", 15, 15},
+		{"Requirements −20%", 20, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := rt.ReverseTranslate(tt.text)
+			if got.Code != "ease" || got.Min != tt.min || got.Max != tt.max {
+				t.Errorf("ReverseTranslate(%q) = %s %d-%d, want ease %d-%d", tt.text, got.Code, got.Min, got.Max, tt.min, tt.max)
+			}
+			if got.DisplayText != tt.text {
+				t.Errorf("DisplayText = %q, want %q", got.DisplayText, tt.text)
+			}
+		})
+	}
+}
+
+func TestReverseTranslatedEaseLowersRequirements(t *testing.T) {
+	ease := NewReverseTranslator().ReverseTranslate("Requirements -20%")
+	if got := ApplyRequirementReduction(156, []Property{ease}); got != 124 {
+		t.Errorf("ApplyRequirementReduction(156, -20%%) = %d, want 124", got)
+	}
+}
@@ -0,0 +1,51 @@
+package d2
+
+// RuneRecipe is the Horadric Cube recipe that upgrades a rune to the next one
+type RuneRecipe struct {
+	Quantity int    // Runes of the lower tier consumed
+	Gem      string // Gem added to the cube ("Chipped Topaz"); empty when none
+}
+
+// runeUpgradeRecipes maps a rune number to the recipe that turns it into the
+// next rune (3 El -> Eld, ..., 2 Cham + Flawless Emerald -> Zod)
+var runeUpgradeRecipes = map[int]RuneRecipe{
+	1:  {Quantity: 3},                           // El -> Eld
+	2:  {Quantity: 3},                           // Eld -> Tir
+	3:  {Quantity: 3},                           // Tir -> Nef
+	4:  {Quantity: 3},                           // Nef -> Eth
+	5:  {Quantity: 3},                           // Eth -> Ith
+	6:  {Quantity: 3},                           // Ith -> Tal
+	7:  {Quantity: 3},                           // Tal -> Ral
+	8:  {Quantity: 3},                           // Ral -> Ort
+	9:  {Quantity: 3},                           // Ort -> Thul
+	10: {Quantity: 3, Gem: "Chipped Topaz"},     // Thul -> Amn
+	11: {Quantity: 3, Gem: "Chipped Amethyst"},  // Amn -> Sol
+	12: {Quantity: 3, Gem: "Chipped Sapphire"},  // Sol -> Shael
+	13: {Quantity: 3, Gem: "Chipped Ruby"},      // Shael -> Dol
+	14: {Quantity: 3, Gem: "Chipped Emerald"},   // Dol -> Hel
+	15: {Quantity: 2, Gem: "Chipped Diamond"},   // Hel -> Io
+	16: {Quantity: 2, Gem: "Flawed Topaz"},      // Io -> Lum
+	17: {Quantity: 2, Gem: "Flawed Amethyst"},   // Lum -> Ko
+	18: {Quantity: 2, Gem: "Flawed Sapphire"},   // Ko -> Fal
+	19: {Quantity: 2, Gem: "Flawed Ruby"},       // Fal -> Lem
+	20: {Quantity: 2, Gem: "Flawed Emerald"},    // Lem -> Pul
+	21: {Quantity: 2, Gem: "Flawed Diamond"},    // Pul -> Um
+	22: {Quantity: 2, Gem: "Topaz"},             // Um -> Mal
+	23: {Quantity: 2, Gem: "Amethyst"},          // Mal -> Ist
+	24: {Quantity: 2, Gem: "Sapphire"},          // Ist -> Gul
+	25: {Quantity: 2, Gem: "Ruby"},              // Gul -> Vex
+	26: {Quantity: 2, Gem: "Emerald"},           // Vex -> Ohm
+	27: {Quantity: 2, Gem: "Diamond"},           // Ohm -> Lo
+	28: {Quantity: 2, Gem: "Flawless Topaz"},    // Lo -> Sur
+	29: {Quantity: 2, Gem: "Flawless Amethyst"}, // Sur -> Ber
+	30: {Quantity: 2, Gem: "Flawless Sapphire"}, // Ber -> Jah
+	31: {Quantity: 2, Gem: "Flawless Ruby"},     // Jah -> Cham
+	32: {Quantity: 2, Gem: "Flawless Emerald"},  // Cham -> Zod
+}
+
+// RuneUpgradeRecipe returns the cube recipe that upgrades the rune with the
+// given number. ok is false for Zod and unknown rune numbers.
+func RuneUpgradeRecipe(runeNumber int) (RuneRecipe, bool) {
+	recipe, ok := runeUpgradeRecipes[runeNumber]
+	return recipe, ok
+}
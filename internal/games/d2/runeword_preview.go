@@ -0,0 +1,151 @@
+package d2
+
+// RunewordStats holds a runeword's final defense and damage on one base.
+// Each range spans the lowest possible roll (low base value, minimum
+// property rolls) to the highest (high base value, maximum property rolls).
+// Zero values mean the base has no defense or damage of that kind.
+type RunewordStats struct {
+	MinAC         int
+	MaxAC         int
+	MinDam        int
+	MaxDam        int
+	TwoHandMinDam int
+	TwoHandMaxDam int
+}
+
+// RunewordPreview is the result of socketing a runeword into a specific base
+type RunewordPreview struct {
+	RequiredSockets int  // One socket per rune
+	Fits            bool // Base can hold enough sockets for every rune
+
+	Normal   RunewordStats
+	Ethereal *RunewordStats // nil when the base can't be ethereal
+}
+
+// statRange accumulates the min and max rolls of one or more properties
+type statRange struct {
+	min int
+	max int
+}
+
+func (s *statRange) add(p Property) {
+	s.min += p.Min
+	s.max += p.Max
+}
+
+// runewordMods is the subset of runeword properties that change base
+// defense or damage
+type runewordMods struct {
+	defensePct statRange // ac%
+	defense    statRange // ac
+	damagePct  statRange // ed / dmg%
+	damageMin  statRange // dmg-min, dmg-norm min
+	damageMax  statRange // dmg-max, dmg-norm max
+}
+
+func collectRunewordMods(props []Property) runewordMods {
+	var m runewordMods
+	for _, p := range props {
+		switch p.Code {
+		case "ac%":
+			m.defensePct.add(p)
+		case "ac":
+			m.defense.add(p)
+		case "ed", "dmg%":
+			m.damagePct.add(p)
+		case "dmg-min":
+			m.damageMin.add(p)
+		case "dmg-max":
+			m.damageMax.add(p)
+		case "dmg":
+			m.damageMin.add(p)
+			m.damageMax.add(p)
+		case "dmg-norm":
+			// "Adds X-Y Damage" stores the added minimum in Min and the
+			// added maximum in Max, neither is a roll
+			m.damageMin.add(Property{Min: p.Min, Max: p.Min})
+			m.damageMax.add(Property{Min: p.Max, Max: p.Max})
+		}
+	}
+	return m
+}
+
+// CanSocketRuneword reports whether a base can hold a runeword. A runeword
+// only forms when the item has exactly one socket per rune, but a white base
+// can roll any socket count up to its max (lower item levels roll fewer, and
+// the socket quest and cube recipes are capped the same way), so any base
+// whose max sockets reaches the rune count qualifies. A 3-rune runeword fits
+// a 4-socket base rolled with 3 sockets; it never fits a 2-socket base.
+func CanSocketRuneword(maxSockets, runeCount int) bool {
+	return runeCount > 0 && maxSockets >= runeCount
+}
+
+// ComputeRunewordOnBase applies a runeword's enhanced defense, enhanced
+// damage and flat defense/damage properties to a base. Percentages multiply
+// the base value (rounded down) before flat bonuses are added, matching the
+// game. Ethereal bases get the 1.5x multiplier on base values only; flat
+// bonuses from the runeword are not multiplied. The preview is still computed
+// when the base can't hold enough sockets, with Fits reporting the problem.
+func ComputeRunewordOnBase(rw *Runeword, base *ItemBase) RunewordPreview {
+	preview := RunewordPreview{
+		RequiredSockets: len(rw.Runes),
+		Fits:            CanSocketRuneword(base.MaxSockets, len(rw.Runes)),
+	}
+
+	mods := collectRunewordMods(rw.Properties)
+	preview.Normal = mods.apply(RunewordStats{
+		MinAC:         base.MinAC,
+		MaxAC:         base.MaxAC,
+		MinDam:        base.MinDam,
+		MaxDam:        base.MaxDam,
+		TwoHandMinDam: base.TwoHandMinDam,
+		TwoHandMaxDam: base.TwoHandMaxDam,
+	})
+
+	if eth := ComputeEtherealStats(base); eth != nil {
+		stats := mods.apply(RunewordStats{
+			MinAC:         eth.MinAC,
+			MaxAC:         eth.MaxAC,
+			MinDam:        eth.MinDam,
+			MaxDam:        eth.MaxDam,
+			TwoHandMinDam: eth.TwoHandMinDam,
+			TwoHandMaxDam: eth.TwoHandMaxDam,
+		})
+		preview.Ethereal = &stats
+	}
+
+	return preview
+}
+
+// apply turns base values into final values. Kinds the base lacks (no
+// defense on a weapon, no damage on armor) stay zero.
+func (m runewordMods) apply(base RunewordStats) RunewordStats {
+	var out RunewordStats
+	if base.MaxAC > 0 {
+		out.MinAC = enhancedValue(base.MinAC, m.defensePct.min) + m.defense.min
+		out.MaxAC = enhancedValue(base.MaxAC, m.defensePct.max) + m.defense.max
+	}
+	if base.MaxDam > 0 {
+		out.MinDam, out.MaxDam = m.damage(base.MinDam, base.MaxDam)
+	}
+	if base.TwoHandMaxDam > 0 {
+		out.TwoHandMinDam, out.TwoHandMaxDam = m.damage(base.TwoHandMinDam, base.TwoHandMaxDam)
+	}
+	return out
+}
+
+// damage applies enhanced damage and flat damage to one damage range. The
+// game never lets maximum damage drop to or below minimum damage.
+func (m runewordMods) damage(minDam, maxDam int) (int, int) {
+	low := enhancedValue(minDam, m.damagePct.min) + m.damageMin.min
+	high := enhancedValue(maxDam, m.damagePct.max) + m.damageMax.max
+	if high <= low {
+		high = low + 1
+	}
+	return low, high
+}
+
+// enhancedValue applies a percentage bonus, rounding down
+func enhancedValue(v, pct int) int {
+	return v * (100 + pct) / 100
+}
@@ -0,0 +1,50 @@
+package d2
+
+import "testing"
+
+func TestCanSocketRuneword(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxSockets int
+		runeCount  int
+		want       bool
+	}{
+		{"3 runes on a 4-socket base", 4, 3, true},
+		{"3 runes on a 3-socket base", 3, 3, true},
+		{"3 runes on a 2-socket base", 2, 3, false},
+		{"3 runes on an unsocketable base", 0, 3, false},
+		{"no runes", 4, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanSocketRuneword(tt.maxSockets, tt.runeCount); got != tt.want {
+				t.Errorf("CanSocketRuneword(%d, %d) = %v, want %v", tt.maxSockets, tt.runeCount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeRunewordOnBaseFits(t *testing.T) {
+	malice := &Runeword{DisplayName: "Malice", Runes: []string{"r06", "r01", "r05"}}
+
+	tests := []struct {
+		name       string
+		maxSockets int
+		wantFits   bool
+	}{
+		{"4-socket base", 4, true},
+		{"2-socket base", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &ItemBase{Name: "Long Sword", MinDam: 3, MaxDam: 19, MaxSockets: tt.maxSockets}
+			preview := ComputeRunewordOnBase(malice, base)
+			if preview.RequiredSockets != 3 {
+				t.Errorf("RequiredSockets = %d, want 3", preview.RequiredSockets)
+			}
+			if preview.Fits != tt.wantFits {
+				t.Errorf("Fits = %v, want %v", preview.Fits, tt.wantFits)
+			}
+		})
+	}
+}
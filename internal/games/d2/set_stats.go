@@ -0,0 +1,190 @@
+package d2
+
+import (
+	"fmt"
+	"sort"
+)
+
+// takeHighestStatCodes are stats that do not stack across items: only the
+// strongest instance applies (skill procs, charges, auras, oskills, flags).
+var takeHighestStatCodes = map[string]bool{
+	"hit-skill":     true,
+	"gethit-skill":  true,
+	"kill-skill":    true,
+	"death-skill":   true,
+	"levelup-skill": true,
+	"att-skill":     true,
+	"charged":       true,
+	"aura":          true,
+	"oskill":        true,
+	"nofreeze":      true,
+	"indestruct":    true,
+	"ethereal":      true,
+	"teleport":      true,
+
+	"pierce-immunity-cold":   true,
+	"pierce-immunity-fire":   true,
+	"pierce-immunity-light":  true,
+	"pierce-immunity-poison": true,
+	"pierce-immunity-damage": true,
+	"pierce-immunity-magic":  true,
+}
+
+// SumProperties aggregates property lists into one list, combining entries
+// with the same code and param. Additive stats sum their min/max; stats in
+// takeHighestStatCodes keep the highest instance. Raw (untranslated) lines
+// cannot be summed and are de-duplicated by their display text. First-seen
+// order is preserved, and str/dex/vit/enr collapse into all-stats when equal.
+func SumProperties(translator *PropertyTranslator, groups ...[]Property) []Property {
+	index := make(map[string]int)
+	result := make([]Property, 0)
+
+	for _, group := range groups {
+		for _, prop := range group {
+			// Equipped-count tiers don't apply once properties are summed
+			prop.ItemCount = 0
+
+			key := prop.Code + "|" + prop.Param
+			if prop.Code == "raw" {
+				key = "raw|" + prop.DisplayText
+			}
+			// "Or" bonuses can't be summed either; keep each choice once
+			if len(prop.Alternatives) > 0 {
+				key = "or|" + setBonusKey(prop)
+				for _, alt := range prop.Alternatives {
+					key += "|" + setBonusKey(alt)
+				}
+			}
+
+			// Expand all-stats so it combines with individual attributes
+			if prop.Code == "all-stats" && len(prop.Alternatives) == 0 {
+				for _, code := range []string{"str", "dex", "vit", "enr"} {
+					result = mergeProperty(result, index, code+"|", Property{Code: code, Min: prop.Min, Max: prop.Max})
+				}
+				continue
+			}
+
+			result = mergeProperty(result, index, key, prop)
+		}
+	}
+
+	for i := range result {
+		if result[i].Code != "raw" {
+			translator.EnrichProperty(&result[i])
+		}
+	}
+
+	return ApplyCombineRules(result, DefaultCombineRules, translator)
+}
+
+// mergeProperty adds prop into result, combining with an existing entry for key
+func mergeProperty(result []Property, index map[string]int, key string, prop Property) []Property {
+	i, ok := index[key]
+	if !ok {
+		index[key] = len(result)
+		return append(result, prop)
+	}
+
+	existing := &result[i]
+	switch {
+	case prop.Code == "raw" || len(prop.Alternatives) > 0:
+		// Identical raw line or "or" bonus, keep one
+	case takeHighestStatCodes[prop.Code]:
+		if prop.Max > existing.Max || (prop.Max == existing.Max && prop.Min > existing.Min) {
+			*existing = prop
+		}
+	default:
+		existing.Min += prop.Min
+		existing.Max += prop.Max
+	}
+	return result
+}
+
+// CombineSetProperties returns the aggregated stats of a complete set as if
+// every piece were equipped: each member's own and bonus properties plus the
+// set's partial and full bonuses.
+func CombineSetProperties(translator *PropertyTranslator, items []SetItem, bonus *SetBonus) []Property {
+	groups := make([][]Property, 0, len(items)*2+2)
+	for _, item := range items {
+		groups = append(groups, item.Properties, item.BonusProperties)
+	}
+	if bonus != nil {
+		groups = append(groups, bonus.PartialBonuses, FullSetOnlyBonuses(bonus))
+	}
+	return SumProperties(translator, groups...)
+}
+
+// FullSetOnlyBonuses returns the full set bonuses that aren't repeats of a
+// partial bonus. Some sets list their full set completion cumulatively
+// (partial bonuses included), others only list the extra bonuses.
+func FullSetOnlyBonuses(bonus *SetBonus) []Property {
+	if bonus == nil {
+		return nil
+	}
+	partial := make(map[string]int, len(bonus.PartialBonuses))
+	for _, p := range bonus.PartialBonuses {
+		partial[setBonusKey(p)]++
+	}
+	result := make([]Property, 0, len(bonus.FullBonuses))
+	for _, p := range bonus.FullBonuses {
+		key := setBonusKey(p)
+		if partial[key] > 0 {
+			partial[key]--
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// setBonusKey identifies a bonus line for de-duplication
+func setBonusKey(p Property) string {
+	if p.Code == "raw" {
+		return "raw|" + p.DisplayText
+	}
+	return fmt.Sprintf("%s|%s|%d|%d", p.Code, p.Param, p.Min, p.Max)
+}
+
+// SetBonusTier is the set bonus activated at a number of equipped pieces
+type SetBonusTier struct {
+	ItemCount  int
+	Full       bool // The full set completion tier
+	Properties []Property
+}
+
+// SetBonusTiers groups a set's partial bonuses by the equipped count that
+// activates them, in ascending order, followed by the full set tier at
+// totalItems. Partial bonuses without a known count (imported before counts
+// were recorded) are conservatively placed in the full set tier.
+func SetBonusTiers(bonus *SetBonus, totalItems int) []SetBonusTier {
+	if bonus == nil {
+		return nil
+	}
+
+	byCount := make(map[int][]Property)
+	var unknown []Property
+	for _, p := range bonus.PartialBonuses {
+		if p.ItemCount <= 0 || p.ItemCount >= totalItems {
+			unknown = append(unknown, p)
+			continue
+		}
+		byCount[p.ItemCount] = append(byCount[p.ItemCount], p)
+	}
+
+	counts := make([]int, 0, len(byCount))
+	for count := range byCount {
+		counts = append(counts, count)
+	}
+	sort.Ints(counts)
+
+	tiers := make([]SetBonusTier, 0, len(counts)+1)
+	for _, count := range counts {
+		tiers = append(tiers, SetBonusTier{ItemCount: count, Properties: byCount[count]})
+	}
+	tiers = append(tiers, SetBonusTier{
+		ItemCount:  totalItems,
+		Full:       true,
+		Properties: append(unknown, FullSetOnlyBonuses(bonus)...),
+	})
+	return tiers
+}
@@ -0,0 +1,68 @@
+package d2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-catalog-api/internal/dbtest"
+)
+
+// sorceressRow is a d2.classes row whose skill tree spells Fire Ball as one word
+func sorceressRow() []interface{} {
+	trees := []SkillTree{{Name: "Fire Spells", Skills: []string{"Fireball", "Fire Bolt"}}}
+	return []interface{}{"sorceress", "Sorceress", "sor", dbtest.JSON(trees), nil, nil}
+}
+
+func TestSkillNamesPreferClassSpelling(t *testing.T) {
+	fireBall := Property{Code: "skill", Param: "47", Min: 3, Max: 3}
+
+	var skills SkillNames
+	tr := NewPropertyTranslator().WithSkillNames(&skills)
+	if got := tr.Translate(fireBall); got != "+3 To Fire Ball" {
+		t.Errorf("before loading: %q, want the built-in name", got)
+	}
+
+	skills.Set([]Class{{ID: "sorceress", SkillTrees: []SkillTree{{Skills: []string{"Fireball"}}}}})
+	if got := tr.Translate(fireBall); got != "+3 To Fireball" {
+		t.Errorf("after loading: %q, want the skill tree spelling", got)
+	}
+	for param, want := range map[string]string{"Teleport": "Teleport", "9999": "9999", "36": "Fire Bolt"} {
+		if got := skills.Name(param); got != want {
+			t.Errorf("Name(%q) = %q, want %q", param, got, want)
+		}
+	}
+
+	// Other translators keep the built-in names
+	if got := DefaultTranslator.Translate(fireBall); got != "+3 To Fire Ball" {
+		t.Errorf("DefaultTranslator: %q, want the built-in name", got)
+	}
+}
+
+func TestLoadSkillNamesOnce(t *testing.T) {
+	db := dbtest.NewFake().On("FROM d2.classes", sorceressRow())
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := repo.LoadSkillNames(ctx, false); err != nil {
+			t.Fatalf("LoadSkillNames: %v", err)
+		}
+	}
+	if n := db.Count("FROM d2.classes"); n != 1 {
+		t.Errorf("queried classes %d times, want once", n)
+	}
+	if got := repo.SkillNames().Name("47"); got != "Fireball" {
+		t.Errorf("Name(47) = %q, want Fireball", got)
+	}
+
+	// A failed reload keeps the names already loaded
+	failing := NewRepository(dbtest.NewFake().OnError("FROM d2.classes", errors.New("connection refused")))
+	failing.skills = repo.skills
+	if err := failing.LoadSkillNames(ctx, true); err == nil {
+		t.Error("LoadSkillNames succeeded on a failed query")
+	}
+	if got := repo.SkillNames().Name("47"); got != "Fireball" {
+		t.Errorf("after failed reload: Name(47) = %q, want Fireball", got)
+	}
+}
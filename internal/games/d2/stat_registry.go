@@ -3,6 +3,7 @@ package d2
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -12,6 +13,7 @@ import (
 type StatRegistry struct {
 	repo  *Repository
 	known map[string]bool
+	added []string // Codes auto-registered by EnsureStat
 	mu    sync.Mutex
 }
 
@@ -176,9 +178,19 @@ func (sr *StatRegistry) EnsureStat(ctx context.Context, prop Property) error {
 		return fmt.Errorf("ensure stat %s: %w", prop.Code, err)
 	}
 	sr.known[prop.Code] = true
+	sr.added = append(sr.added, prop.Code)
 	return nil
 }
 
+// Added returns the stat codes EnsureStat registered, sorted.
+func (sr *StatRegistry) Added() []string {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	added := append([]string(nil), sr.added...)
+	sort.Strings(added)
+	return added
+}
+
 // IsKnown returns whether a stat code is in the registry.
 func (sr *StatRegistry) IsKnown(code string) bool {
 	sr.mu.Lock()
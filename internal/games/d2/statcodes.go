@@ -1,5 +1,6 @@
 package d2
 
+import "sort"
 
 // StatCodeInfo contains metadata about a stat code for filtering
 type StatCodeInfo struct {
@@ -32,6 +33,32 @@ var StatCategories = []string{
 	"Other",
 }
 
+// statCategoryIcons maps each stat category to an icon hint for the filter UI
+var statCategoryIcons = map[string]string{
+	"Skills":      "skills",
+	"Skill Trees": "skill-tree",
+	"Attributes":  "attributes",
+	"Life & Mana": "life-mana",
+	"Speed":       "speed",
+	"Resistances": "resistances",
+	"Absorb":      "absorb",
+	"Damage":      "damage",
+	"Attack":      "attack",
+	"Defense":     "defense",
+	"Leech":       "leech",
+	"Combat":      "combat",
+	"Magic Find":  "magic-find",
+	"Pierce":      "pierce",
+	"Per Level":   "per-level",
+	"Sunder":      "sunder",
+	"Other":       "other",
+}
+
+// StatCategoryIcon returns the icon hint for a stat category, or "" if none
+func StatCategoryIcon(category string) string {
+	return statCategoryIcons[category]
+}
+
 // FilterableStats returns all stat codes that are useful for marketplace filtering.
 // These are the stats users typically search for when looking for items.
 func FilterableStats() []StatCodeInfo {
@@ -285,3 +312,84 @@ var parametricStatCodes = map[string]bool{
 	"fireskill":        true,
 }
 
+
+// StatCodeGroup returns a stat code together with its aliases, so a lookup by
+// any one of them matches properties stored under the others. Passing an
+// alias resolves to its primary code first. Unknown codes return just themselves.
+func StatCodeGroup(code string) []string {
+	for _, stat := range FilterableStats() {
+		matched := stat.Code == code
+		for _, alias := range stat.Aliases {
+			if alias == code {
+				matched = true
+			}
+		}
+		if matched {
+			return append([]string{stat.Code}, stat.Aliases...)
+		}
+	}
+	return []string{code}
+}
+
+// UnmappedStat is a stat code seen in the stat registry or in item data that
+// has no entry in FilterableStats and isn't parametric, so the filter UI
+// can't offer it
+type UnmappedStat struct {
+	Code        string
+	Name        string // Registry name, empty when the code isn't registered
+	DisplayText string // Registry display text, or a sample from item data
+	ItemCount   int    // Items carrying the code
+	Registered  bool   // Code is persisted in the stat registry
+}
+
+// UnmappedStats cross-references registered stats and stat usage in item
+// data against FilterableStats (including aliases) and the parametric codes.
+// Results are ordered by item count, most used first, then by code.
+func UnmappedStats(registered []Stat, usage []StatUsage) []UnmappedStat {
+	mapped := make(map[string]bool)
+	for _, stat := range FilterableStats() {
+		mapped[stat.Code] = true
+		for _, alias := range stat.Aliases {
+			mapped[alias] = true
+		}
+	}
+
+	byCode := make(map[string]*UnmappedStat)
+	for _, stat := range registered {
+		if mapped[stat.Code] || parametricStatCodes[stat.Code] {
+			continue
+		}
+		byCode[stat.Code] = &UnmappedStat{
+			Code:        stat.Code,
+			Name:        stat.Name,
+			DisplayText: stat.DisplayText,
+			Registered:  true,
+		}
+	}
+	for _, u := range usage {
+		if mapped[u.Code] || parametricStatCodes[u.Code] {
+			continue
+		}
+		entry, ok := byCode[u.Code]
+		if !ok {
+			entry = &UnmappedStat{Code: u.Code}
+			byCode[u.Code] = entry
+		}
+		entry.ItemCount = u.ItemCount
+		if entry.DisplayText == "" {
+			entry.DisplayText = u.SampleText
+		}
+	}
+
+	result := make([]UnmappedStat, 0, len(byCode))
+	for _, entry := range byCode {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ItemCount != result[j].ItemCount {
+			return result[i].ItemCount > result[j].ItemCount
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
@@ -8,12 +8,21 @@ import (
 
 // PropertyTranslator converts internal property codes to human-readable text
 type PropertyTranslator struct {
+	// Language of the generated text, see SupportedLocales
+	locale string
+
 	// Map of property code to display format
 	// Format placeholders: {value}, {min}, {max}, {param}
 	formats map[string]string
 
+	// Per-level display templates, see perLevelCodes
+	perLevel map[string]string
+
 	// Skill tab names indexed by tab number
 	skillTabs map[int]string
+
+	// Names numeric skill params resolve to; nil uses the built-in names
+	skills *SkillNames
 }
 
 // perLevelCodes maps per-level property codes to their display templates.
@@ -37,6 +46,8 @@ var perLevelCodes = map[string]string{
 // NewPropertyTranslator creates a new property translator with D2 property formats
 func NewPropertyTranslator() *PropertyTranslator {
 	return &PropertyTranslator{
+		locale:   DefaultLocale,
+		perLevel: perLevelCodes,
 		formats: map[string]string{
 			// Skills
 			"allskills":       "+{value} To All Skills",
@@ -137,6 +148,7 @@ func NewPropertyTranslator() *PropertyTranslator {
 			"res-cold":       "Cold Resist +{value}%",
 			"res-ltng":       "Lightning Resist +{value}%",
 			"res-pois":       "Poison Resist +{value}%",
+			"res-pois-len":   "Poison Length Reduced By {value}%",
 			"res-all":        "All Resistances +{value}",
 			"res-mag":        "Magic Resist +{value}%",
 			"abs-fire":       "+{value} Fire Absorb",
@@ -183,6 +195,7 @@ func NewPropertyTranslator() *PropertyTranslator {
 			"nofreeze":       "Cannot Be Frozen",
 			"half-freeze":    "Half Freeze Duration",
 			"ignore-ac":      "Ignore Target's Defense",
+			"reduce-ac":      "-{value}% Target Defense",
 			"knock":          "Knockback",
 			"slow":           "Slows Target By {value}%",
 			"howl":           "Hit Causes Monster To Flee {value}%",
@@ -289,8 +302,12 @@ func NewPropertyTranslator() *PropertyTranslator {
 
 // Translate converts a property to human-readable text
 func (t *PropertyTranslator) Translate(prop Property) string {
+	// Reductions are displayed from their positive magnitude; the template
+	// carries the sign
+	prop = NormalizeReductionSign(prop)
+
 	// Handle per-level codes with D2 formula: floor(clvl * raw_value / 8)
-	if template, ok := perLevelCodes[prop.Code]; ok {
+	if template, ok := t.perLevel[prop.Code]; ok {
 		raw := prop.Min
 		if prop.Max > raw {
 			raw = prop.Max
@@ -358,6 +375,9 @@ func (t *PropertyTranslator) Translate(prop Property) string {
 			absSmall := -maxVal // closer to zero = smaller absolute
 			absLarge := -minVal // further from zero = larger absolute
 			valueStr = fmt.Sprintf("-(%d-%d)", absSmall, absLarge)
+		} else if strings.Contains(result, "-{value}") {
+			// Reduction templates carry the minus: "-(10-15)%", not "-10-15%"
+			valueStr = fmt.Sprintf("(%d-%d)", minVal, maxVal)
 		} else {
 			valueStr = fmt.Sprintf("%d-%d", minVal, maxVal)
 		}
@@ -384,7 +404,11 @@ func (t *PropertyTranslator) Translate(prop Property) string {
 	}
 
 	if prop.Param != "" {
-		result = strings.ReplaceAll(result, "{param}", prop.Param)
+		param := prop.Param
+		if skillParamCodes[prop.Code] {
+			param = t.skills.Name(param)
+		}
+		result = strings.ReplaceAll(result, "{param}", param)
 	}
 
 	return result
@@ -405,6 +429,19 @@ func (t *PropertyTranslator) TranslateProperties(props []Property) []string {
 	return results
 }
 
+// Locale returns the language the translator writes in
+func (t *PropertyTranslator) Locale() string {
+	return t.locale
+}
+
+// WithSkillNames returns a copy of the translator that resolves skill params
+// with skills
+func (t *PropertyTranslator) WithSkillNames(skills *SkillNames) *PropertyTranslator {
+	tr := *t
+	tr.skills = skills
+	return &tr
+}
+
 // HasRange returns true if the property has a range of values
 func (t *PropertyTranslator) HasRange(prop Property) bool {
 	return prop.Min != prop.Max
@@ -0,0 +1,124 @@
+package d2
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language of the built-in property formats. Every other
+// locale falls back to it for codes its table doesn't cover.
+const DefaultLocale = "en"
+
+// localeFiles holds one translation table per non-default locale, named
+// <locale>.json
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// localeTable is the on-disk shape of a locale file. Keys are property codes
+// (formats, per_level) or skill tab numbers (skill_tabs); values use the same
+// placeholders as the English templates.
+type localeTable struct {
+	Formats   map[string]string `json:"formats"`
+	PerLevel  map[string]string `json:"per_level"`
+	SkillTabs map[string]string `json:"skill_tabs"`
+}
+
+// localeTranslators holds a translator per supported locale, built once from
+// the embedded tables
+var localeTranslators = loadLocaleTranslators()
+
+func loadLocaleTranslators() map[string]*PropertyTranslator {
+	translators := map[string]*PropertyTranslator{DefaultLocale: DefaultTranslator}
+
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("read embedded locales: %v", err))
+	}
+	for _, entry := range entries {
+		locale := strings.TrimSuffix(entry.Name(), ".json")
+		data, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("read locale %s: %v", locale, err))
+		}
+		var table localeTable
+		if err := json.Unmarshal(data, &table); err != nil {
+			panic(fmt.Sprintf("parse locale %s: %v", locale, err))
+		}
+		translators[locale] = newLocalizedTranslator(locale, table)
+	}
+	return translators
+}
+
+// newLocalizedTranslator layers a locale's table over the English formats, so
+// codes the table doesn't cover still render in English
+func newLocalizedTranslator(locale string, table localeTable) *PropertyTranslator {
+	t := NewPropertyTranslator()
+	t.locale = locale
+
+	for code, format := range table.Formats {
+		t.formats[code] = format
+	}
+
+	perLevel := make(map[string]string, len(perLevelCodes))
+	for code, format := range perLevelCodes {
+		perLevel[code] = format
+	}
+	for code, format := range table.PerLevel {
+		perLevel[code] = format
+	}
+	t.perLevel = perLevel
+
+	for tab, name := range table.SkillTabs {
+		if n, err := strconv.Atoi(tab); err == nil {
+			t.skillTabs[n] = name
+		}
+	}
+	return t
+}
+
+// SupportedLocales returns every locale with a translator, sorted
+func SupportedLocales() []string {
+	locales := make([]string, 0, len(localeTranslators))
+	for locale := range localeTranslators {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// MatchLocale resolves a language tag such as "pt-BR" or "pt" to a supported
+// locale. An exact match wins, case-insensitively; otherwise the primary
+// language subtag is compared.
+func MatchLocale(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", false
+	}
+	for locale := range localeTranslators {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	for _, locale := range SupportedLocales() {
+		lp, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(lp, primary) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// TranslatorForLocale returns the translator for a supported locale, or the
+// English translator for anything else
+func TranslatorForLocale(locale string) *PropertyTranslator {
+	if t, ok := localeTranslators[locale]; ok {
+		return t
+	}
+	return DefaultTranslator
+}
@@ -0,0 +1,62 @@
+package d2
+
+import "testing"
+
+func TestTranslateReductionStatSign(t *testing.T) {
+	tests := []struct {
+		code   string
+		single string
+		ranged string
+	}{
+		{"ease", "Requirements -20%", "Requirements -(10-15)%"},
+		{"reduce-ac", "-20% Target Defense", "-(10-15)% Target Defense"},
+		{"red-dmg", "Damage Reduced By 20", "Damage Reduced By 10-15"},
+		{"red-dmg%", "Damage Reduced By 20%", "Damage Reduced By 10-15%"},
+		{"red-mag", "Magic Damage Reduced By 20", "Magic Damage Reduced By 10-15"},
+		{"res-pois-len", "Poison Length Reduced By 20%", "Poison Length Reduced By 10-15%"},
+		{"cheap", "Reduces All Vendor Prices 20%", "Reduces All Vendor Prices 10-15%"},
+		{"pierce-fire", "-20% To Enemy Fire Resistance", "-(10-15)% To Enemy Fire Resistance"},
+		{"pierce-cold", "-20% To Enemy Cold Resistance", "-(10-15)% To Enemy Cold Resistance"},
+		{"pierce-ltng", "-20% To Enemy Lightning Resistance", "-(10-15)% To Enemy Lightning Resistance"},
+		{"pierce-pois", "-20% To Enemy Poison Resistance", "-(10-15)% To Enemy Poison Resistance"},
+		{"pierce-mag", "-20% To Enemy Magic Resistance", "-(10-15)% To Enemy Magic Resistance"},
+	}
+	if len(tests) != len(reductionStatCodes) {
+		t.Fatalf("covers %d reduction stats, reductionStatCodes has %d", len(tests), len(reductionStatCodes))
+	}
+
+	tr := NewPropertyTranslator()
+	rt := NewReverseTranslator()
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if !IsReductionStat(tt.code) {
+				t.Fatalf("%s is not a reduction stat", tt.code)
+			}
+
+			// Positive magnitudes and the game's negative values render alike
+			cases := []struct {
+				prop Property
+				want string
+			}{
+				{Property{Code: tt.code, Min: 20, Max: 20}, tt.single},
+				{Property{Code: tt.code, Min: -20, Max: -20}, tt.single},
+				{Property{Code: tt.code, Min: 10, Max: 15}, tt.ranged},
+				{Property{Code: tt.code, Min: -15, Max: -10}, tt.ranged},
+			}
+			for _, c := range cases {
+				if got := tr.Translate(c.prop); got != c.want {
+					t.Errorf("Translate(%d-%d) = %q, want %q", c.prop.Min, c.prop.Max, got, c.want)
+				}
+			}
+
+			// Display text parses back to the positive magnitude
+			for text, want := range map[string][2]int{tt.single: {20, 20}, tt.ranged: {10, 15}} {
+				got := rt.ReverseTranslate(text)
+				if got.Code != tt.code || got.Min != want[0] || got.Max != want[1] {
+					t.Errorf("ReverseTranslate(%q) = %s %d-%d, want %s %d-%d",
+						text, got.Code, got.Min, got.Max, tt.code, want[0], want[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package games
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Game is one game's catalog: its database schema, its importer and its
+// API routes. The API server mounts each registered game's public routes at
+// /api/v1/<Name()> and its admin routes at /api/v1/admin/<Name()>.
+type Game interface {
+	// Name is the URL segment for the game's routes, e.g. "d2"
+	Name() string
+
+	// SchemaName is the Postgres schema holding the game's tables
+	SchemaName() string
+
+	// Import loads the game's catalog data from path
+	Import(ctx context.Context, path string) error
+
+	// RegisterRoutes mounts the game's public endpoints on router, which is
+	// already scoped to the game's prefix
+	RegisterRoutes(router fiber.Router)
+
+	// RegisterAdminRoutes mounts the game's admin endpoints on router, which
+	// is scoped to the game's admin prefix and already requires an admin
+	RegisterAdminRoutes(router fiber.Router)
+}
+
+// Registry holds games in registration order
+type Registry struct {
+	games []Game
+}
+
+// Register adds a game. Names must be unique.
+func (r *Registry) Register(g Game) error {
+	if r.Get(g.Name()) != nil {
+		return fmt.Errorf("game already registered: %s", g.Name())
+	}
+	r.games = append(r.games, g)
+	return nil
+}
+
+// Get returns the game with the given name, or nil
+func (r *Registry) Get(name string) Game {
+	for _, g := range r.games {
+		if g.Name() == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// All returns the registered games in registration order
+func (r *Registry) All() []Game {
+	return r.games
+}
@@ -0,0 +1,212 @@
+// Package metrics keeps process-wide counters and histograms and renders them
+// in the Prometheus text exposition format. It is deliberately small: no
+// client library, just what the catalog reports.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the latency histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Labels are the label values of one series, by label name
+type Labels map[string]string
+
+// key renders labels in the exposition format, sorted by name, so equal
+// label sets share a series: {category="uniques",result="ok"}
+func (l Labels) key() string {
+	if len(l) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strconv.Quote(l[name]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// withLabel returns the rendered labels with one more label appended, for
+// histogram bucket series
+func withLabel(key, name, value string) string {
+	label := name + "=" + strconv.Quote(value)
+	if key == "" {
+		return "{" + label + "}"
+	}
+	return key[:len(key)-1] + "," + label + "}"
+}
+
+type family struct {
+	kind    string // "counter" or "histogram"
+	help    string
+	buckets []float64
+
+	counters   map[string]float64
+	histograms map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket, not cumulative; the last slot is +Inf
+	sum    float64
+	count  uint64
+}
+
+// Registry holds metric families by name. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Default is the registry the server exposes at /metrics
+var Default = NewRegistry()
+
+// NewCounter declares a counter. Declaring is optional but gives the family
+// its HELP text and makes it show up before the first increment.
+func (r *Registry) NewCounter(name, help string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, "counter", nil).help = help
+}
+
+// NewHistogram declares a histogram with the given bucket upper bounds
+// (DefaultBuckets when nil)
+func (r *Registry) NewHistogram(name, help string, buckets []float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, "histogram", buckets).help = help
+}
+
+// Add increments a counter series by v. Negative values are ignored:
+// counters only go up.
+func (r *Registry) Add(name string, labels Labels, v float64) {
+	if v < 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, "counter", nil).counters[labels.key()] += v
+}
+
+// Inc increments a counter series by one
+func (r *Registry) Inc(name string, labels Labels) {
+	r.Add(name, labels, 1)
+}
+
+// Observe records one value in a histogram series
+func (r *Registry) Observe(name string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(name, "histogram", nil)
+	key := labels.key()
+	h, ok := f.histograms[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(f.buckets)+1)}
+		f.histograms[key] = h
+	}
+	i := sort.SearchFloat64s(f.buckets, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// family returns the named family, creating it on first use. Callers hold mu.
+func (r *Registry) family(name, kind string, buckets []float64) *family {
+	if f, ok := r.families[name]; ok {
+		return f
+	}
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	f := &family{
+		kind:       kind,
+		buckets:    buckets,
+		counters:   make(map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+	r.families[name] = f
+	return f
+}
+
+// WriteText writes every family in the Prometheus text format, sorted by
+// name and series
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		if f.help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, f.help)
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.kind)
+
+		if f.kind == "counter" {
+			for _, key := range sortedKeys(f.counters) {
+				fmt.Fprintf(&b, "%s%s %s\n", name, key, formatValue(f.counters[key]))
+			}
+			continue
+		}
+
+		keys := make([]string, 0, len(f.histograms))
+		for key := range f.histograms {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			h := f.histograms[key]
+			var cumulative uint64
+			for i, le := range f.buckets {
+				cumulative += h.counts[i]
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, withLabel(key, "le", formatValue(le)), cumulative)
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, withLabel(key, "le", "+Inf"), h.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, key, formatValue(h.sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, key, h.count)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatValue renders whole numbers without a decimal point
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
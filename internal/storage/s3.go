@@ -61,6 +61,16 @@ func (s *S3Storage) UploadImage(ctx context.Context, path string, data []byte, c
 	return s.GetPublicURL(path), nil
 }
 
+// UploadThumbnail uploads a ThumbnailSize copy of an image under
+// ThumbnailPath(path) and returns its public URL
+func (s *S3Storage) UploadThumbnail(ctx context.Context, path string, data []byte) (string, error) {
+	thumb, err := GenerateThumbnail(data, ThumbnailSize)
+	if err != nil {
+		return "", err
+	}
+	return s.UploadImage(ctx, ThumbnailPath(path), thumb, "image/png")
+}
+
 // GetPublicURL returns the public URL for a file path
 func (s *S3Storage) GetPublicURL(path string) string {
 	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.publicURL, s.bucketName, path)
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+
+	_ "image/jpeg" // decode jpeg icons
+
+	"golang.org/x/image/draw"
+)
+
+// InventoryCellSize is the pixel size of one inventory cell in item icons
+const InventoryCellSize = 28
+
+// ThumbnailSize bounds both thumbnail dimensions (two inventory cells)
+const ThumbnailSize = 2 * InventoryCellSize
+
+// ThumbnailUploader is implemented by storages that can also keep a
+// downscaled copy of uploaded images. It is optional: callers should check
+// for it with UploadThumbnail instead of requiring it.
+type ThumbnailUploader interface {
+	UploadThumbnail(ctx context.Context, path string, data []byte) (string, error)
+}
+
+// ThumbnailPath returns where the thumbnail of the image at path is stored
+func ThumbnailPath(path string) string {
+	return "thumb/" + path
+}
+
+// UploadThumbnail uploads a thumbnail of data when s supports thumbnails.
+// Returns "" with no error for storages that don't.
+func UploadThumbnail(ctx context.Context, s Storage, path string, data []byte) (string, error) {
+	uploader, ok := s.(ThumbnailUploader)
+	if !ok {
+		return "", nil
+	}
+	return uploader.UploadThumbnail(ctx, path, data)
+}
+
+// GenerateThumbnail scales an image down to fit within maxSize x maxSize,
+// keeping its aspect ratio, and encodes the result as PNG. Images that
+// already fit are re-encoded without scaling.
+func GenerateThumbnail(data []byte, maxSize int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}